@@ -0,0 +1,273 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/internal/secrets"
+	"github.com/vennekilde/gw2verify/v2/pkg/audit"
+	"github.com/vennekilde/gw2verify/v2/pkg/retention"
+	"github.com/vennekilde/gw2verify/v2/pkg/services"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+const usage = `Usage: gw2verify-admin <command> [arguments]
+
+Commands:
+  services list                      List all services
+  services create <name> [scope...]  Create a service with the scopes and print its API key
+  services rename <uuid> <name>      Rename a service
+  services scopes <uuid> [scope...]  Replace the scopes of a service
+  services rotate <uuid>             Replace the API key of a service and print the new key
+  services disable <uuid>            Disable a service
+  apikeys generate-master-key        Print a new random master key for encrypting secrets
+  apikeys reencrypt                  Encrypt stored gw2 api keys and webhook secrets with the current master key
+  apikeys decrypt                    Store encrypted gw2 api keys and webhook secrets in plaintext again
+  retention run [dry-run]            Enforce the retention policy and print what was removed
+
+Rotating the master key:
+  1. Generate a new master key
+  2. Configure it as MasterKey and add the old key to PreviousMasterKeys
+  3. Run apikeys reencrypt, after which the old key can be removed
+
+Disabling api key encryption:
+  1. Run apikeys decrypt while the master keys are still configured
+  2. Remove MasterKey, MasterKeyFile and PreviousMasterKeys from the configuration
+  The encryption migrations can only be rolled back once no encrypted api keys or webhook secrets remain
+
+The retention policy is read from RetentionPolicyFile, a json file of the form:
+  {"tables": [{"table": "voice_user_states", "max_age": "8760h",
+               "downsample_after": "720h", "downsample_interval": "1h"}]}
+Tables: voice_user_states, achievements, histories, events, audit_entries, token_infos.
+Only voice_user_states and achievements can be downsampled. Data of banned users is kept.
+
+Scopes: status:read, apikey:write, requirements:skip, ban:write, temporary:write,
+        statistics:write, properties:own, webhooks:own, audit:read, privacy:read,
+        privacy:erase, admin
+
+Changes are recorded in the audit log as made by the service gw2verify-admin.
+The database is configured with the same environment variables as gw2verify.
+`
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, "error:", err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) < 2 {
+		exitUsage()
+	}
+	switch args[0] {
+	case "services":
+		return runServices(args[1:])
+	case "apikeys":
+		return runAPIKeys(args[1:])
+	case "retention":
+		return runRetention(args[1:])
+	}
+	exitUsage()
+	return nil
+}
+
+func exitUsage() {
+	fmt.Fprint(os.Stderr, usage)
+	os.Exit(2)
+}
+
+func runAPIKeys(args []string) error {
+	switch {
+	case args[0] == "generate-master-key" && len(args) == 1:
+		key, err := secrets.GenerateMasterKey()
+		if err != nil {
+			return err
+		}
+		fmt.Println(key)
+	case args[0] == "reencrypt" && len(args) == 1:
+		kr, err := secrets.DefaultKeyring()
+		if err != nil {
+			return err
+		}
+		var updated, updatedWebhooks int
+		err = audited(func(tx bun.Tx) (entry api.AuditEntry, err error) {
+			if updated, err = orm.ReencryptAPIKeys(tx, kr); err != nil {
+				return entry, err
+			}
+			if updatedWebhooks, err = orm.ReencryptWebhookSecrets(tx, kr); err != nil {
+				return entry, err
+			}
+			keyID := kr.CurrentKeyID()
+			return api.AuditEntry{
+				Action:  api.AuditActionAPIKeysReencrypt,
+				Subject: &keyID,
+				After:   map[string]int{"api_keys": updated, "webhook_secrets": updatedWebhooks},
+			}, nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("re-encrypted %d api keys and %d webhook secrets with master key %s\n", updated, updatedWebhooks, kr.CurrentKeyID())
+	case args[0] == "decrypt" && len(args) == 1:
+		kr, err := secrets.DefaultKeyring()
+		if err != nil {
+			return err
+		}
+		var updated, updatedWebhooks int
+		err = audited(func(tx bun.Tx) (entry api.AuditEntry, err error) {
+			if updated, err = orm.DecryptAPIKeys(tx, kr); err != nil {
+				return entry, err
+			}
+			if updatedWebhooks, err = orm.DecryptWebhookSecrets(tx, kr); err != nil {
+				return entry, err
+			}
+			return api.AuditEntry{
+				Action: api.AuditActionAPIKeysDecrypt,
+				After:  map[string]int{"api_keys": updated, "webhook_secrets": updatedWebhooks},
+			}, nil
+		})
+		if err != nil {
+			return err
+		}
+		fmt.Printf("decrypted %d api keys and %d webhook secrets, remove the master keys from the configuration before they are encrypted again\n", updated, updatedWebhooks)
+	default:
+		exitUsage()
+	}
+	return nil
+}
+
+func runRetention(args []string) error {
+	if args[0] != "run" || len(args) > 2 || (len(args) == 2 && args[1] != "dry-run") {
+		exitUsage()
+	}
+	policy, err := retention.ConfiguredPolicy()
+	if err != nil {
+		return err
+	}
+	// Events are picked up by the listeners of the running instances
+	report, err := retention.NewService(policy, nil, verify.NewEventEmitter(nil)).Run(len(args) == 2)
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TABLE\tDOWNSAMPLED\tDELETED")
+	for _, table := range report.Tables {
+		fmt.Fprintf(w, "%s\t%d\t%d\n", table.Table, table.Downsampled, table.Deleted)
+	}
+	if err = w.Flush(); err != nil {
+		return err
+	}
+	if report.DryRun {
+		fmt.Println("\nDry run, nothing was removed.")
+	}
+	return nil
+}
+
+func runServices(args []string) error {
+	var action api.AuditAction
+	var change func(tx bun.Tx) (*api.Service, error)
+	switch cmd, cmdArgs := args[0], args[1:]; {
+	case cmd == "list" && len(cmdArgs) == 0:
+		serviceList, err := services.GetServices(orm.DB())
+		if err == nil {
+			printServices(serviceList...)
+		}
+		return err
+	case cmd == "create" && len(cmdArgs) >= 1:
+		action = api.AuditActionServiceCreate
+		change = func(tx bun.Tx) (*api.Service, error) {
+			return services.CreateService(tx, cmdArgs[0], parseScopes(cmdArgs[1:]))
+		}
+	case cmd == "rename" && len(cmdArgs) == 2:
+		action = api.AuditActionServiceUpdate
+		change = func(tx bun.Tx) (*api.Service, error) {
+			return services.UpdateService(tx, cmdArgs[0], api.ServiceUpdate{Name: &cmdArgs[1]})
+		}
+	case cmd == "scopes" && len(cmdArgs) >= 1:
+		scopes := parseScopes(cmdArgs[1:])
+		action = api.AuditActionServiceUpdate
+		change = func(tx bun.Tx) (*api.Service, error) {
+			return services.UpdateService(tx, cmdArgs[0], api.ServiceUpdate{Scopes: &scopes})
+		}
+	case cmd == "rotate" && len(cmdArgs) == 1:
+		action = api.AuditActionServiceRotate
+		change = func(tx bun.Tx) (*api.Service, error) {
+			return services.RotateServiceKey(tx, cmdArgs[0])
+		}
+	case cmd == "disable" && len(cmdArgs) == 1:
+		action = api.AuditActionServiceDisable
+		change = func(tx bun.Tx) (*api.Service, error) {
+			return services.DisableService(tx, cmdArgs[0])
+		}
+	default:
+		exitUsage()
+	}
+
+	var service *api.Service
+	err := audited(func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		var before *api.Service
+		if action != api.AuditActionServiceCreate {
+			before, _ = services.GetService(tx, args[1])
+		}
+		service, err = change(tx)
+		if err != nil {
+			return entry, err
+		}
+		return audit.ServiceEntry(action, before, service), nil
+	})
+	if err != nil {
+		return err
+	}
+
+	printServices(*service)
+	if service.APIKey != nil {
+		fmt.Printf("\nAPI key: %s\nStore it now, it cannot be shown again.\n", *service.APIKey)
+	}
+	return nil
+}
+
+// audited runs the change and records the audit entry it returns in a single transaction
+func audited(change func(tx bun.Tx) (api.AuditEntry, error)) error {
+	return orm.DB().RunInTx(context.Background(), nil, func(ctx context.Context, tx bun.Tx) error {
+		entry, err := change(tx)
+		if err != nil {
+			return err
+		}
+		entry.ServiceUuid = audit.AdminCLI
+		return audit.Record(tx, &entry)
+	})
+}
+
+func parseScopes(args []string) []api.Scope {
+	scopes := make([]api.Scope, len(args))
+	for i, arg := range args {
+		scopes[i] = api.Scope(arg)
+	}
+	return scopes
+}
+
+func printServices(serviceList ...api.Service) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "UUID\tNAME\tSCOPES\tCREATED\tDISABLED")
+	for _, service := range serviceList {
+		disabled := "-"
+		if service.DisabledAt != nil {
+			disabled = service.DisabledAt.Format(time.RFC3339)
+		}
+		scopes := make([]string, len(service.Scopes))
+		for i, scope := range service.Scopes {
+			scopes[i] = string(scope)
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\n", service.UUID, service.Name, strings.Join(scopes, ","), service.DbCreated.Format(time.RFC3339), disabled)
+	}
+	w.Flush()
+}
@@ -5,15 +5,21 @@ import (
 	"database/sql"
 	"time"
 
-	"github.com/MrGunflame/gw2api"
 	"github.com/alexlast/bunzap"
 	"github.com/gin-gonic/gin"
 	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/internal/logging"
 	"github.com/vennekilde/gw2verify/v2/internal/migrations"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/internal/secrets"
 	"github.com/vennekilde/gw2verify/v2/internal/server"
+	"github.com/vennekilde/gw2verify/v2/pkg/cluster"
 	"github.com/vennekilde/gw2verify/v2/pkg/history"
+	"github.com/vennekilde/gw2verify/v2/pkg/privacy"
+	"github.com/vennekilde/gw2verify/v2/pkg/ratelimit"
+	"github.com/vennekilde/gw2verify/v2/pkg/retention"
 	"github.com/vennekilde/gw2verify/v2/pkg/sync"
 	"github.com/vennekilde/gw2verify/v2/pkg/verify"
 	"github.com/vennekilde/gw2verify/v2/resources"
@@ -21,7 +27,8 @@ import (
 )
 
 func init() {
-	logger, _ := zap.NewDevelopment()
+	// Secrets are scrubbed from all log entries
+	logger, _ := zap.NewDevelopment(zap.WrapCore(logging.NewRedactingCore))
 	defer logger.Sync()
 	_ = zap.ReplaceGlobals(logger)
 	zap.L().Info("replaced zap's global loggers")
@@ -44,6 +51,14 @@ func main() {
 	}
 	orm.DB().AddQueryHook(hook)
 
+	// Ensure the master key is valid before any api keys are encrypted with it
+	if _, err := secrets.DefaultKeyring(); err != nil && err != secrets.ErrNoMasterKey {
+		zap.L().Panic("could not load master key", zap.Error(err))
+	}
+
+	// All gw2 api requests share a single rate limit
+	ratelimit.Install()
+
 	// Migrate DB
 	if err := migrations.MigrateDB(orm.DB().DB, resources.Migrations); err != nil {
 		zap.L().Panic("could not migrate database", zap.Error(err))
@@ -57,21 +72,46 @@ func main() {
 	}()*/
 
 	// Services initialization
-	worldsService := verify.NewWorlds(gw2api.New())
+	// Jobs that must only run on a single instance are run by the leader
+	election := cluster.NewElection("gw2verify")
+	worldsService := verify.NewWorlds(ratelimit.NewSession(api.GW2APIBudgetSync))
+	// Restore world links, so linked worlds can be verified before the matchups are synchronized
+	if err := worldsService.RestoreWorldLinks(); err != nil {
+		zap.L().Error("unable to restore linked worlds", zap.Error(err))
+	}
 	verificationService := verify.NewVerification(worldsService)
 	statisticsService := history.NewStatistics(verificationService)
 	eventEmitter := verify.NewEventEmitter(verificationService)
-	syncService := sync.NewService(eventEmitter)
+	syncService := sync.NewService(verificationService, worldsService, eventEmitter)
 	banService := verify.NewBanService(eventEmitter)
+	unlinkService := verify.NewUnlinkService(eventEmitter)
+	mergeService := verify.NewMergeService(eventEmitter)
+	privacyService, err := privacy.NewService(eventEmitter)
+	if err != nil {
+		zap.L().Panic("could not create privacy service", zap.Error(err))
+	}
+	retentionPolicy, err := retention.ConfiguredPolicy()
+	if err != nil {
+		zap.L().Panic("could not load retention policy", zap.Error(err))
+	}
+	retentionService := retention.NewService(retentionPolicy, election, eventEmitter)
+	webhookDispatcher := verify.NewWebhookDispatcher(eventEmitter)
+	expiryScheduler := verify.NewExpiryScheduler(eventEmitter, election)
+	verify.NewLinkReevaluator(worldsService, eventEmitter)
 
 	// REST endpoints
-	verificationEndpoints := server.NewVerificationEndpoint(verificationService, worldsService, statisticsService, eventEmitter, syncService, banService)
-	endpoints := server.NewEndpoints(verificationEndpoints)
+	verificationEndpoints := server.NewVerificationEndpoint(verificationService, worldsService, statisticsService, eventEmitter, syncService, banService, unlinkService, mergeService)
+	endpoints := server.NewEndpoints(verificationEndpoints, webhookDispatcher, privacyService)
 	// REST server
 	restServer := server.NewRESTServer(endpoints)
 	go restServer.Start()
 
-	go worldsService.Start()
+	go election.Start()
+	go eventEmitter.ListenForEvents()
+	go worldsService.Start(election)
+	go expiryScheduler.Start()
+	go webhookDispatcher.Start()
+	go retentionService.Start()
 	syncService.Start()
 }
 
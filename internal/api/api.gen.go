@@ -27,6 +27,53 @@ const (
 const (
 	HOMEWORLD   AccessType = "HOME_WORLD"
 	LINKEDWORLD AccessType = "LINKED_WORLD"
+	WVWTEAM     AccessType = "WVW_TEAM"
+)
+
+// Defines values for AuditAction.
+const (
+	AuditActionAPIKeyDelete       AuditAction = "apikey_delete"
+	AuditActionAPIKeySet          AuditAction = "apikey_set"
+	AuditActionAPIKeysDecrypt     AuditAction = "apikeys_decrypt"
+	AuditActionAPIKeysReencrypt   AuditAction = "apikeys_reencrypt"
+	AuditActionAccountDelete      AuditAction = "account_delete"
+	AuditActionBan                AuditAction = "ban"
+	AuditActionBanLift            AuditAction = "ban_lift"
+	AuditActionBanUpdate          AuditAction = "ban_update"
+	AuditActionPlatformLinkDelete AuditAction = "platform_link_delete"
+	AuditActionPropertyPut        AuditAction = "property_put"
+	AuditActionRefresh            AuditAction = "refresh"
+	AuditActionServiceCreate      AuditAction = "service_create"
+	AuditActionServiceDisable     AuditAction = "service_disable"
+	AuditActionServiceRotate      AuditAction = "service_rotate"
+	AuditActionServiceUpdate      AuditAction = "service_update"
+	AuditActionTemporaryAccess    AuditAction = "temporary_access"
+	AuditActionUserErase          AuditAction = "user_erase"
+	AuditActionUserMerge          AuditAction = "user_merge"
+	AuditActionWebhookCreate      AuditAction = "webhook_create"
+	AuditActionWebhookDelete      AuditAction = "webhook_delete"
+)
+
+// Defines values for GW2APIBudget.
+const (
+	GW2APIBudgetInteractive GW2APIBudget = "interactive"
+	GW2APIBudgetSync        GW2APIBudget = "sync"
+)
+
+// Defines values for Scope.
+const (
+	ScopeAPIKeyWrite      Scope = "apikey:write"
+	ScopeAdmin            Scope = "admin"
+	ScopeAuditRead        Scope = "audit:read"
+	ScopeBanWrite         Scope = "ban:write"
+	ScopePrivacyErase     Scope = "privacy:erase"
+	ScopePrivacyRead      Scope = "privacy:read"
+	ScopePropertiesOwn    Scope = "properties:own"
+	ScopeRequirementsSkip Scope = "requirements:skip"
+	ScopeStatisticsWrite  Scope = "statistics:write"
+	ScopeStatusRead       Scope = "status:read"
+	ScopeTemporaryWrite   Scope = "temporary:write"
+	ScopeWebhooksOwn      Scope = "webhooks:own"
 )
 
 // Defines values for Status.
@@ -37,10 +84,20 @@ const (
 	ACCESSDENIEDINVALIDWORLD          Status = "ACCESS_DENIED_INVALID_WORLD"
 	ACCESSDENIEDREQUIREMENTNOTMET     Status = "ACCESS_DENIED_REQUIREMENT_NOT_MET"
 	ACCESSDENIEDUNKNOWN               Status = "ACCESS_DENIED_UNKNOWN"
+	ACCESSGRANTEDGUILD                Status = "ACCESS_GRANTED_GUILD"
 	ACCESSGRANTEDHOMEWORLD            Status = "ACCESS_GRANTED_HOME_WORLD"
 	ACCESSGRANTEDHOMEWORLDTEMPORARY   Status = "ACCESS_GRANTED_HOME_WORLD_TEMPORARY"
 	ACCESSGRANTEDLINKEDWORLD          Status = "ACCESS_GRANTED_LINKED_WORLD"
 	ACCESSGRANTEDLINKEDWORLDTEMPORARY Status = "ACCESS_GRANTED_LINKED_WORLD_TEMPORARY"
+	ACCESSGRANTEDWVWALLIANCE          Status = "ACCESS_GRANTED_WVW_ALLIANCE"
+	ACCESSGRANTEDWVWTEAM              Status = "ACCESS_GRANTED_WVW_TEAM"
+	ACCESSGRANTEDWVWTEAMTEMPORARY     Status = "ACCESS_GRANTED_WVW_TEAM_TEMPORARY"
+)
+
+// Defines values for WebhookPayload.
+const (
+	WebhookPayloadUser               WebhookPayload = "user"
+	WebhookPayloadVerificationStatus WebhookPayload = "verification_status"
 )
 
 // APIKeyData defines model for APIKeyData.
@@ -63,35 +120,91 @@ type AccessType string
 
 // Account defines model for Account.
 type Account struct {
-	Access       *[]string   `json:"access,omitempty"`
-	Age          int         `json:"age"`
-	ApiKeys      []TokenInfo `bun:"rel:has-many,join:id=account_id" json:"api_keys"`
-	Commander    bool        `json:"commander"`
-	Created      time.Time   `json:"created"`
-	DailyAp      *int        `json:"daily_ap,omitempty"`
-	DbCreated    time.Time   `bun:",nullzero,notnull,default:current_timestamp,scanonly" json:"db_created,omitempty"`
-	DbUpdated    time.Time   `bun:",nullzero,notnull,default:current_timestamp" json:"db_updated,omitempty"`
-	Expired      *bool       `bun:"-" json:"expired,omitempty"`
-	FractalLevel *int        `json:"fractal_level,omitempty"`
-	GuildLeader  *[]string   `json:"guild_leader,omitempty"`
-	Guilds       *[]string   `json:"guilds,omitempty"`
-	ID           string      `bun:",pk" json:"id"`
-	LastModified *time.Time  `json:"last_modified,omitempty"`
-	MonthlyAp    *int        `json:"monthly_ap,omitempty"`
-	Name         string      `json:"name"`
-	UserID       int64       `json:"user_id"`
-	World        int         `json:"world"`
-	WorldStatus  *Status     `bun:"-" json:"world_status,omitempty"`
-	WvWGuildID   *string     `bun:"wvw_guild_id" json:"wvw_guild_id,omitempty"`
-	WvWRank      int         `bun:"wvw_rank" json:"wvw_rank"`
-	WvWTeamID    int         `bun:"wvw_team_id" json:"wvw_team_id"`
+	Access  *[]string   `json:"access,omitempty"`
+	Age     int         `json:"age"`
+	ApiKeys []TokenInfo `bun:"rel:has-many,join:id=account_id" json:"api_keys"`
+
+	// CharacterLevelSynced Whether the character levels of the account have been fetched from the gw2 api
+	CharacterLevelSynced bool `json:"character_level_synced,omitempty"`
+
+	// CharacterLevelSyncedAt When the character levels of the account were last fetched from the gw2 api
+	CharacterLevelSyncedAt *time.Time `json:"character_level_synced_at,omitempty"`
+	Commander              bool       `json:"commander"`
+	Created                time.Time  `json:"created"`
+	DailyAp                *int       `json:"daily_ap,omitempty"`
+	DbCreated              time.Time  `bun:",nullzero,notnull,default:current_timestamp,scanonly" json:"db_created,omitempty"`
+	DbUpdated              time.Time  `bun:",nullzero,notnull,default:current_timestamp" json:"db_updated,omitempty"`
+	Expired                *bool      `bun:"-" json:"expired,omitempty"`
+	FractalLevel           *int       `json:"fractal_level,omitempty"`
+	GuildLeader            *[]string  `json:"guild_leader,omitempty"`
+	Guilds                 *[]string  `json:"guilds,omitempty"`
+	ID                     string     `bun:",pk" json:"id"`
+	LastModified           *time.Time `json:"last_modified,omitempty"`
+
+	// MaxCharacterLevel Highest level among the account's characters. Only known if character_level_synced is set
+	MaxCharacterLevel int     `json:"max_character_level,omitempty"`
+	MonthlyAp         *int    `json:"monthly_ap,omitempty"`
+	Name              string  `json:"name"`
+	UserID            int64   `json:"user_id"`
+	World             int     `json:"world"`
+	WorldStatus       *Status `bun:"-" json:"world_status,omitempty"`
+	WvWGuildID        *string `bun:"wvw_guild_id" json:"wvw_guild_id,omitempty"`
+	WvWRank           int     `bun:"wvw_rank" json:"wvw_rank"`
+	WvWTeamID         int     `bun:"wvw_team_id" json:"wvw_team_id"`
+}
+
+// AuditAction defines model for AuditAction.
+type AuditAction string
+
+// AuditEntry A mutating call made by a service
+type AuditEntry struct {
+	Action AuditAction `json:"action"`
+
+	// After State of the target after the call, if any
+	After interface{} `bun:"type:jsonb" json:"after,omitempty"`
+
+	// Before State of the target before the call, if any
+	Before         interface{} `bun:"type:jsonb" json:"before,omitempty"`
+	DbCreated      time.Time   `bun:",nullzero,notnull,default:current_timestamp" json:"db_created"`
+	ID             int64       `bun:",pk,autoincrement" json:"id"`
+	PlatformID     *int        `json:"platform_id,omitempty"`
+	PlatformUserID *string     `json:"platform_user_id,omitempty"`
+
+	// ServiceUuid Service that made the call, or gw2verify-admin for calls made with the admin command line tool
+	ServiceUuid string `json:"service_uuid"`
+
+	// Subject Target of the call that is not a user, e.g. a ban id, property subject, webhook id or service uuid
+	Subject *string `json:"subject,omitempty"`
+	UserID  *int64  `json:"user_id,omitempty"`
 }
 
 // Ban defines model for Ban.
 type Ban struct {
-	Reason string    `json:"reason"`
-	Until  time.Time `json:"until"`
-	UserID int64     `json:"user_id"`
+	// AccountId GW2 account the ban is attached to. The ban is copied to any user the account is linked to later, and stays with the users it was on before
+	AccountID *string `json:"account_id,omitempty"`
+
+	// CreatedBy Uuid of the service that issued the ban
+	CreatedBy *string `json:"created_by,omitempty"`
+
+	// GroupId Bans issued together, or copied from the same ban, share a group. Changing or lifting a ban applies to its whole group
+	GroupID  int64      `bun:",nullzero" json:"group_id,omitempty"`
+	ID       int64      `bun:",pk,autoincrement" json:"id,omitempty"`
+	LiftedAt *time.Time `json:"lifted_at,omitempty"`
+
+	// LiftedBy Uuid of the service that lifted the ban
+	LiftedBy *string   `json:"lifted_by,omitempty"`
+	Reason   string    `json:"reason"`
+	Until    time.Time `json:"until"`
+
+	// UpdatedBy Uuid of the service that last changed the ban
+	UpdatedBy *string `json:"updated_by,omitempty"`
+	UserID    int64   `json:"user_id"`
+}
+
+// BanUpdate defines model for BanUpdate.
+type BanUpdate struct {
+	Reason *string    `json:"reason,omitempty"`
+	Until  *time.Time `json:"until,omitempty"`
 }
 
 // ChannelMetadata defines model for ChannelMetadata.
@@ -118,12 +231,42 @@ type Configuration struct {
 	WorldLinks map[string]WorldLinks `json:"world_links"`
 }
 
+// DataExport Everything stored about a user
+type DataExport struct {
+	Achievements []ExportedAchievement `json:"achievements"`
+	AuditEntries []AuditEntry          `json:"audit_entries"`
+	ExportedAt   time.Time             `json:"exported_at"`
+	Histories    []ExportedHistory     `json:"histories"`
+	Merges       []UserMerge           `json:"merges"`
+
+	// Properties Service properties stored with one of the user's platform user ids as the subject
+	Properties  []Property           `json:"properties"`
+	User        User                 `json:"user"`
+	VoiceStates []ExportedVoiceState `json:"voice_states"`
+}
+
 // EphemeralAssociation defines model for EphemeralAssociation.
 type EphemeralAssociation struct {
 	AccessType *AccessType `bun:"-" json:"access_type,omitempty"`
 	Until      *time.Time  `json:"until,omitempty"`
 	UserID     int64       `json:"user_id,omitempty"`
-	World      *int        `json:"world,omitempty"`
+
+	// World World id or WvW team id the user is temporarily associated with
+	World *int `json:"world,omitempty"`
+}
+
+// ErasureReport What was erased about a user
+type ErasureReport struct {
+	// Accounts Amount of gw2 accounts erased along with their api keys, histories and achievements
+	Accounts   int `json:"accounts"`
+	BansErased int `json:"bans_erased"`
+
+	// BansKept Amount of bans kept because of the erasure ban policy. The bans still apply if one of the banned gw2 accounts is linked again
+	BansKept int `json:"bans_kept"`
+
+	// PlatformLinks Amount of platform identities erased
+	PlatformLinks int   `json:"platform_links"`
+	UserID        int64 `json:"user_id"`
 }
 
 // Error defines model for Error.
@@ -132,6 +275,86 @@ type Error struct {
 	SafeDisplayError string `json:"safe-display-error"`
 }
 
+// ExportedAchievement A recorded achievement value of a gw2 account
+type ExportedAchievement struct {
+	AccountID   string    `json:"account_id"`
+	Achievement int       `json:"achievement"`
+	ID          int64     `bun:",pk" json:"id"`
+	Timestamp   time.Time `json:"timestamp"`
+	Value       int       `json:"value"`
+}
+
+// ExportedHistory A recorded change of a gw2 account
+type ExportedHistory struct {
+	AccountID string    `json:"account_id"`
+	ID        int64     `bun:"r_id,pk" json:"id"`
+	New       *string   `json:"new,omitempty"`
+	Old       *string   `json:"old,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Type      string    `json:"type"`
+}
+
+// ExportedVoiceState A recorded voice channel state of a platform user
+type ExportedVoiceState struct {
+	Age                int       `json:"age"`
+	ChannelID          string    `json:"channel_id"`
+	Deafened           bool      `json:"deafened"`
+	Muted              bool      `json:"muted"`
+	PlatformID         int       `json:"platform_id"`
+	PlatformUserID     string    `json:"platform_user_id"`
+	Timestamp          time.Time `json:"timestamp"`
+	VerificationStatus int       `json:"verification_status"`
+	WvWRank            int       `bun:"wvw_rank" json:"wvw_rank"`
+}
+
+// GW2APIBudget What a GW2 API request is made for. Interactive requests are made on behalf of a waiting user, sync requests by background synchronization
+type GW2APIBudget string
+
+// GW2APIBudgetUsage defines model for GW2APIBudgetUsage.
+type GW2APIBudgetUsage struct {
+	// Budget What a GW2 API request is made for. Interactive requests are made on behalf of a waiting user, sync requests by background synchronization
+	Budget GW2APIBudget `json:"budget"`
+
+	// Failures Requests that still failed after all retries
+	Failures int64 `json:"failures"`
+
+	// RateLimited Responses rejected by the GW2 API for exceeding its rate limit
+	RateLimited int64 `json:"rate_limited"`
+
+	// Requests Requests sent to the GW2 API, including retries
+	Requests int64 `json:"requests"`
+
+	// Retries Requests that were retried after being rate limited or failing
+	Retries int64 `json:"retries"`
+
+	// WaitSeconds Total time requests have waited for the rate limiter
+	WaitSeconds float64 `json:"wait_seconds"`
+}
+
+// GW2APIUsage GW2 API rate limit usage of this instance
+type GW2APIUsage struct {
+	// AvailableTokens Requests that can be made right now without waiting
+	AvailableTokens float64             `json:"available_tokens"`
+	Budgets         []GW2APIBudgetUsage `json:"budgets"`
+
+	// Since When the usage started being counted
+	Since time.Time `json:"since"`
+}
+
+// Matchup One side of a WvW matchup
+type Matchup struct {
+	Color     string    `bun:",pk" json:"color"`
+	EndTime   time.Time `json:"end_time"`
+	MatchID   string    `bun:",pk" json:"match_id"`
+	StartTime time.Time `bun:",pk" json:"start_time"`
+
+	// TeamId WvW team id of the side. 0 if the side is not playing as a team
+	TeamID int `json:"team_id,omitempty"`
+
+	// Worlds All worlds linked together on the side
+	Worlds []int `json:"worlds"`
+}
+
 // PlatformLink defines model for PlatformLink.
 type PlatformLink struct {
 	DisplayName *string `json:"display_name,omitempty"`
@@ -145,9 +368,50 @@ type PlatformLink struct {
 
 // Property defines model for Property.
 type Property struct {
-	Name    string  `json:"name"`
-	Subject *string `json:"subject,omitempty"`
-	Value   string  `json:"value"`
+	Name string `json:"name"`
+
+	// PlatformId Platform the subject is a user of, if set when the property was stored
+	PlatformID *int    `json:"platform_id,omitempty"`
+	Subject    *string `json:"subject,omitempty"`
+	Value      string  `json:"value"`
+}
+
+// Requirement Access requirement a user did not meet
+type Requirement struct {
+	Description string `json:"description"`
+
+	// Name Name of the rule in the access policy
+	Name string `json:"name"`
+}
+
+// Scope Permission granted to a service. admin grants every scope
+type Scope string
+
+// Service A consumer of the API
+type Service struct {
+	// ApiKey Bearer token of the service. Only returned when the service is created or its key is rotated, as only a salted hash of the key is stored
+	APIKey    *string   `bun:"-" json:"api_key,omitempty"`
+	DbCreated time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"db_created,omitempty"`
+
+	// DisabledAt Time the service was disabled. Disabled services cannot access the API
+	DisabledAt *time.Time `json:"disabled_at,omitempty"`
+	Name       string     `json:"name"`
+	Scopes     []Scope    `bun:",array" json:"scopes,omitempty"`
+	UUID       string     `bun:",pk" json:"uuid,omitempty"`
+}
+
+// ServiceCreate defines model for ServiceCreate.
+type ServiceCreate struct {
+	Name   string  `json:"name"`
+	Scopes []Scope `json:"scopes,omitempty"`
+}
+
+// ServiceUpdate defines model for ServiceUpdate.
+type ServiceUpdate struct {
+	Name *string `json:"name,omitempty"`
+
+	// Scopes Replaces the scopes of the service
+	Scopes *[]Scope `json:"scopes,omitempty"`
 }
 
 // Status defines model for Status.
@@ -171,20 +435,111 @@ type User struct {
 	DbCreated             time.Time              `bun:",nullzero,notnull,default:current_timestamp,scanonly" json:"db_created,omitempty"`
 	DbUpdated             time.Time              `bun:",nullzero,notnull,default:current_timestamp" json:"db_updated,omitempty"`
 	EphemeralAssociations []EphemeralAssociation `bun:"rel:has-many,join:id=user_id" json:"ephemeral_associations,omitempty"`
-	Id                    int64                  `bun:",pk,autoincrement" json:"id"`
-	PlatformLinks         []PlatformLink         `bun:"rel:has-many,join:id=user_id" json:"platform_links,omitempty"`
+
+	// EventId Id of the event in the event log, if the user was received as an event. Can be used as the cursor for replaying missed events
+	EventID *int64 `bun:"-" json:"event_id,omitempty"`
+	Id      int64  `bun:",pk,autoincrement" json:"id"`
+
+	// MergedInto Id of the user this user has been merged into. Only set on the event emitted for a user that has been merged into another user, which carries the platform links of both users
+	MergedInto    *int64         `bun:"-" json:"merged_into,omitempty"`
+	PlatformLinks []PlatformLink `bun:"rel:has-many,join:id=user_id" json:"platform_links,omitempty"`
+}
+
+// UserMerge Record of a user that has been merged into another user
+type UserMerge struct {
+	// AccountId The gw2 account that was linked from both users, if the merge was automatic
+	AccountID *string `json:"account_id,omitempty"`
+
+	// Automatic True if the users were merged because the same gw2 account was linked from both users, false if the merge was requested
+	Automatic bool      `bun:",notnull" json:"automatic"`
+	DbCreated time.Time `bun:",nullzero,notnull,default:current_timestamp" json:"db_created"`
+	ID        int64     `bun:",pk,autoincrement" json:"id"`
+
+	// ServiceUuid Service that requested the merge, if the merge was requested
+	ServiceUuid *string `json:"service_uuid,omitempty"`
+
+	// SourceUser Snapshot of the source user before it was merged
+	SourceUser User `bun:"type:jsonb" json:"source_user,omitempty"`
+
+	// SourceUserId User that was merged and removed
+	SourceUserID int64 `json:"source_user_id"`
+
+	// TargetUserId User the source user was merged into
+	TargetUserID int64 `json:"target_user_id"`
+}
+
+// UserMergeRequest defines model for UserMergeRequest.
+type UserMergeRequest struct {
+	// TargetUserId User the user is merged into
+	TargetUserID int64 `json:"target_user_id"`
 }
 
 // VerificationStatus defines model for VerificationStatus.
 type VerificationStatus struct {
-	Ban          *Ban          `json:"ban,omitempty"`
+	Ban *Ban `json:"ban,omitempty"`
+
+	// EventId Id of the event in the event log. Can be used as the cursor for replaying missed events
+	EventID      *int64        `json:"event_id,omitempty"`
 	PlatformLink *PlatformLink `json:"platform_link,omitempty"`
 	Status       Status        `bun:"-" json:"status"`
+
+	// UnmetRequirement Access requirement a user did not meet
+	UnmetRequirement *Requirement `json:"unmet_requirement,omitempty"`
+}
+
+// Webhook defines model for Webhook.
+type Webhook struct {
+	ID int64 `bun:",pk,autoincrement" json:"id,omitempty"`
+
+	// Payload user delivers User events, verification_status delivers VerificationStatus events
+	Payload WebhookPayload `json:"payload"`
+
+	// PlatformId Only deliver events for users on the platform
+	PlatformID *int `json:"platform_id,omitempty"`
+
+	// Secret Secret used to sign deliveries with HMAC-SHA256. Generated if not provided and only returned when the webhook is registered
+	Secret      *string `bun:"-" json:"secret,omitempty"`
+	ServiceUuid string  `json:"service_uuid,omitempty"`
+
+	// Url Must use http or https, and must not resolve to a loopback, link-local or private address
+	URL string `json:"url"`
+
+	// World World perspective the verification status is computed from. Required for verification_status payloads
+	World *int `json:"world,omitempty"`
+}
+
+// WebhookDelivery An event that could not be delivered to a webhook
+type WebhookDelivery struct {
+	Attempts int `json:"attempts"`
+
+	// Body The payload that was delivered
+	Body           map[string]interface{} `bun:"type:jsonb" json:"body"`
+	EventID        *int64                 `json:"event_id,omitempty"`
+	FailedAt       time.Time              `bun:"db_created,nullzero,notnull,default:current_timestamp" json:"failed_at,omitempty"`
+	ID             int64                  `bun:",pk,autoincrement" json:"id"`
+	LastError      *string                `json:"last_error,omitempty"`
+	LastStatusCode *int                   `json:"last_status_code,omitempty"`
+	WebhookID      int64                  `json:"webhook_id"`
 }
 
+// WebhookPayload user delivers User events, verification_status delivers VerificationStatus events
+type WebhookPayload string
+
 // WorldLinks defines model for WorldLinks.
 type WorldLinks = []int
 
+// AccountId defines model for account_id.
+type AccountId = string
+
+// ApikeyId defines model for apikey_id.
+type ApikeyId = string
+
+// BanId defines model for ban_id.
+type BanId = int64
+
+// DeliveryId defines model for delivery_id.
+type DeliveryId = int64
+
 // GuildIdent defines model for guild_ident.
 type GuildIdent = string
 
@@ -203,21 +558,60 @@ type ServiceUuid = string
 // Subject defines model for subject.
 type Subject = string
 
+// TraitEventCursor defines model for trait_event_cursor.
+type TraitEventCursor = int64
+
 // TraitPlatformUserDisplayName defines model for trait_platform_user_display_name.
 type TraitPlatformUserDisplayName = string
 
 // TraitSecuredAuthentication defines model for trait_secured_authentication.
 type TraitSecuredAuthentication = string
 
+// TraitStatusFilter defines model for trait_status_filter.
+type TraitStatusFilter = []Status
+
 // TraitWorldView defines model for trait_world_view.
 type TraitWorldView = int
 
 // TraitWorldViewOptional defines model for trait_world_view_optional.
 type TraitWorldViewOptional = int
 
+// UserId defines model for user_id.
+type UserId = int64
+
+// WebhookId defines model for webhook_id.
+type WebhookId = int64
+
 // TraitErrorResp defines model for trait_error_resp.
 type TraitErrorResp = Error
 
+// TraitGw2apiRateLimited429 defines model for trait_gw2api_rate_limited_429.
+type TraitGw2apiRateLimited429 = Error
+
+// GetAuditEntriesParams defines parameters for GetAuditEntries.
+type GetAuditEntriesParams struct {
+	// ServiceUuid Only include calls made by the service
+	ServiceUuid    *string      `form:"service_uuid,omitempty" json:"service_uuid,omitempty"`
+	UserId         *int64       `form:"user_id,omitempty" json:"user_id,omitempty"`
+	PlatformId     *int         `form:"platform_id,omitempty" json:"platform_id,omitempty"`
+	PlatformUserId *string      `form:"platform_user_id,omitempty" json:"platform_user_id,omitempty"`
+	Action         *AuditAction `form:"action,omitempty" json:"action,omitempty"`
+	Limit          *int         `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset         *int         `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
+// GetBansParams defines parameters for GetBans.
+type GetBansParams struct {
+	// Active Only include bans that are active (true) or inactive (false)
+	Active *bool  `form:"active,omitempty" json:"active,omitempty"`
+	UserId *int64 `form:"user_id,omitempty" json:"user_id,omitempty"`
+
+	// Reason Only include bans with a reason containing the given text
+	Reason *string `form:"reason,omitempty" json:"reason,omitempty"`
+	Limit  *int    `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int    `form:"offset,omitempty" json:"offset,omitempty"`
+}
+
 // PostChannelPlatformStatisticsParams defines parameters for PostChannelPlatformStatistics.
 type PostChannelPlatformStatisticsParams struct {
 	World TraitWorldView `form:"world" json:"world"`
@@ -228,9 +622,26 @@ type GetV1ConfigurationParams struct {
 	World *TraitWorldViewOptional `form:"world,omitempty" json:"world,omitempty"`
 }
 
+// GetMatchupsParams defines parameters for GetMatchups.
+type GetMatchupsParams struct {
+	World *TraitWorldViewOptional `form:"world,omitempty" json:"world,omitempty"`
+
+	// Since Only include matchups that ended after the given time
+	Since *time.Time `form:"since,omitempty" json:"since,omitempty"`
+}
+
 // GetPlatformUserUpdatesParams defines parameters for GetPlatformUserUpdates.
 type GetPlatformUserUpdatesParams struct {
 	World TraitWorldView `form:"world" json:"world"`
+
+	// Since Event id of the last received event. If provided, events missed since then are replayed, oldest first, before waiting for new events
+	Since *TraitEventCursor `form:"since,omitempty" json:"since,omitempty"`
+}
+
+// GetPlatformUserUpdatesStreamParams defines parameters for GetPlatformUserUpdatesStream.
+type GetPlatformUserUpdatesStreamParams struct {
+	// Since Event id of the last received event. If provided, events missed since then are replayed, oldest first, before waiting for new events
+	Since *TraitEventCursor `form:"since,omitempty" json:"since,omitempty"`
 }
 
 // GetPlatformUserParams defines parameters for GetPlatformUser.
@@ -241,7 +652,7 @@ type GetPlatformUserParams struct {
 
 // PutPlatformUserAPIKeyParams defines parameters for PutPlatformUserAPIKey.
 type PutPlatformUserAPIKeyParams struct {
-	// SkipRequirements Skip the variuse apikey requirements that might be in place
+	// SkipRequirements Skip the variuse apikey requirements that might be in place. Requires the requirements:skip scope
 	SkipRequirements *bool                   `form:"skip-requirements,omitempty" json:"skip-requirements,omitempty"`
 	World            *TraitWorldViewOptional `form:"world,omitempty" json:"world,omitempty"`
 }
@@ -251,9 +662,59 @@ type GetPlatformUserAPIKeyNameParams struct {
 	World *TraitWorldViewOptional `form:"world,omitempty" json:"world,omitempty"`
 }
 
+// PutPlatformUserBanParams defines parameters for PutPlatformUserBan.
+type PutPlatformUserBanParams struct {
+	// AllIdentities Also ban every user that has ever been linked to one of the user's gw2 accounts, along with all of their platform identities
+	AllIdentities *bool `form:"all_identities,omitempty" json:"all_identities,omitempty"`
+}
+
+// PutServiceSubjectPropertiesParams defines parameters for PutServiceSubjectProperties.
+type PutServiceSubjectPropertiesParams struct {
+	// PlatformId Platform the subject is a user of, if the subject is a platform user id. Erasing the platform user removes the property
+	PlatformId *int `form:"platform_id,omitempty" json:"platform_id,omitempty"`
+}
+
+// PutServiceSubjectPropertyParams defines parameters for PutServiceSubjectProperty.
+type PutServiceSubjectPropertyParams struct {
+	// PlatformId Platform the subject is a user of, if the subject is a platform user id. Erasing the platform user removes the property
+	PlatformId *int `form:"platform_id,omitempty" json:"platform_id,omitempty"`
+}
+
+// GetVerificationPlatformUsersParams defines parameters for GetVerificationPlatformUsers.
+type GetVerificationPlatformUsersParams struct {
+	Limit  *int           `form:"limit,omitempty" json:"limit,omitempty"`
+	Offset *int           `form:"offset,omitempty" json:"offset,omitempty"`
+	World  TraitWorldView `form:"world" json:"world"`
+}
+
+// PostVerificationPlatformUsersJSONBody defines parameters for PostVerificationPlatformUsers.
+type PostVerificationPlatformUsersJSONBody = []string
+
+// PostVerificationPlatformUsersParams defines parameters for PostVerificationPlatformUsers.
+type PostVerificationPlatformUsersParams struct {
+	World TraitWorldView `form:"world" json:"world"`
+}
+
 // GetVerificationPlatformUserUpdatesParams defines parameters for GetVerificationPlatformUserUpdates.
 type GetVerificationPlatformUserUpdatesParams struct {
 	World TraitWorldView `form:"world" json:"world"`
+
+	// Since Event id of the last received event. If provided, events missed since then are replayed, oldest first, before waiting for new events
+	Since *TraitEventCursor `form:"since,omitempty" json:"since,omitempty"`
+
+	// Status Only receive verification updates with one of the given statuses. All statuses are received if omitted
+	Status *TraitStatusFilter `form:"status,omitempty" json:"status,omitempty"`
+}
+
+// GetVerificationPlatformUserUpdatesStreamParams defines parameters for GetVerificationPlatformUserUpdatesStream.
+type GetVerificationPlatformUserUpdatesStreamParams struct {
+	World TraitWorldView `form:"world" json:"world"`
+
+	// Since Event id of the last received event. If provided, events missed since then are replayed, oldest first, before waiting for new events
+	Since *TraitEventCursor `form:"since,omitempty" json:"since,omitempty"`
+
+	// Status Only receive verification updates with one of the given statuses. All statuses are received if omitted
+	Status *TraitStatusFilter `form:"status,omitempty" json:"status,omitempty"`
 }
 
 // GetVerificationPlatformUserStatusParams defines parameters for GetVerificationPlatformUserStatus.
@@ -274,6 +735,18 @@ type PutVerificationPlatformUserTemporaryParams struct {
 	World TraitWorldView `form:"world" json:"world"`
 }
 
+// PostAdminServiceJSONRequestBody defines body for PostAdminService for application/json ContentType.
+type PostAdminServiceJSONRequestBody = ServiceCreate
+
+// PatchAdminServiceJSONRequestBody defines body for PatchAdminService for application/json ContentType.
+type PatchAdminServiceJSONRequestBody = ServiceUpdate
+
+// PostAdminUserMergeJSONRequestBody defines body for PostAdminUserMerge for application/json ContentType.
+type PostAdminUserMergeJSONRequestBody = UserMergeRequest
+
+// PatchBanJSONRequestBody defines body for PatchBan for application/json ContentType.
+type PatchBanJSONRequestBody = BanUpdate
+
 // PostChannelPlatformStatisticsJSONRequestBody defines body for PostChannelPlatformStatistics for application/json ContentType.
 type PostChannelPlatformStatisticsJSONRequestBody = ChannelMetadata
 
@@ -283,12 +756,60 @@ type PutPlatformUserAPIKeyJSONRequestBody = APIKeyData
 // PutPlatformUserBanJSONRequestBody defines body for PutPlatformUserBan for application/json ContentType.
 type PutPlatformUserBanJSONRequestBody = Ban
 
+// PostServiceWebhookJSONRequestBody defines body for PostServiceWebhook for application/json ContentType.
+type PostServiceWebhookJSONRequestBody = Webhook
+
+// PostVerificationPlatformUsersJSONRequestBody defines body for PostVerificationPlatformUsers for application/json ContentType.
+type PostVerificationPlatformUsersJSONRequestBody = PostVerificationPlatformUsersJSONBody
+
 // PutVerificationPlatformUserTemporaryJSONRequestBody defines body for PutVerificationPlatformUserTemporary for application/json ContentType.
 type PutVerificationPlatformUserTemporaryJSONRequestBody = EphemeralAssociation
 
 // ServerInterface represents all server handlers.
 type ServerInterface interface {
 
+	// (GET /v1/admin/gw2api/usage)
+	GetAdminGW2APIUsage(c *gin.Context)
+
+	// (GET /v1/admin/services)
+	GetAdminServices(c *gin.Context)
+
+	// (POST /v1/admin/services)
+	PostAdminService(c *gin.Context)
+
+	// (GET /v1/admin/services/{service_uuid})
+	GetAdminService(c *gin.Context, serviceUuid ServiceUuid)
+
+	// (PATCH /v1/admin/services/{service_uuid})
+	PatchAdminService(c *gin.Context, serviceUuid ServiceUuid)
+
+	// (POST /v1/admin/services/{service_uuid}/disable)
+	PostAdminServiceDisable(c *gin.Context, serviceUuid ServiceUuid)
+
+	// (POST /v1/admin/services/{service_uuid}/rotate)
+	PostAdminServiceRotate(c *gin.Context, serviceUuid ServiceUuid)
+
+	// (POST /v1/admin/users/{user_id}/merge)
+	PostAdminUserMerge(c *gin.Context, userId UserId)
+
+	// (GET /v1/admin/users/{user_id}/merges)
+	GetAdminUserMerges(c *gin.Context, userId UserId)
+
+	// (GET /v1/audit)
+	GetAuditEntries(c *gin.Context, params GetAuditEntriesParams)
+
+	// (GET /v1/bans)
+	GetBans(c *gin.Context, params GetBansParams)
+
+	// (DELETE /v1/bans/{ban_id})
+	DeleteBan(c *gin.Context, banId BanId)
+
+	// (GET /v1/bans/{ban_id})
+	GetBan(c *gin.Context, banId BanId)
+
+	// (PATCH /v1/bans/{ban_id})
+	PatchBan(c *gin.Context, banId BanId)
+
 	// (POST /v1/channels/{platform_id}/{channel}/statistics)
 	PostChannelPlatformStatistics(c *gin.Context, platformId PlatformId, channel string, params PostChannelPlatformStatisticsParams)
 
@@ -298,20 +819,44 @@ type ServerInterface interface {
 	// (GET /v1/guilds/{guild_ident}/users)
 	GetGuildUsers(c *gin.Context, guildIdent GuildIdent)
 
+	// (GET /v1/matchups)
+	GetMatchups(c *gin.Context, params GetMatchupsParams)
+
 	// (GET /v1/platform/{platform_id}/users/updates)
 	GetPlatformUserUpdates(c *gin.Context, platformId PlatformId, params GetPlatformUserUpdatesParams)
 
+	// (GET /v1/platform/{platform_id}/users/updates/stream)
+	GetPlatformUserUpdatesStream(c *gin.Context, platformId PlatformId, params GetPlatformUserUpdatesStreamParams)
+
+	// (DELETE /v1/platform/{platform_id}/users/{platform_user_id})
+	DeletePlatformUser(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId)
+
 	// (GET /v1/platform/{platform_id}/users/{platform_user_id})
 	GetPlatformUser(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, params GetPlatformUserParams)
 
+	// (DELETE /v1/platform/{platform_id}/users/{platform_user_id}/accounts/{account_id})
+	DeletePlatformUserAccount(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, accountId AccountId)
+
 	// (PUT /v1/platform/{platform_id}/users/{platform_user_id}/apikey)
 	PutPlatformUserAPIKey(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, params PutPlatformUserAPIKeyParams)
 
 	// (GET /v1/platform/{platform_id}/users/{platform_user_id}/apikey/name)
 	GetPlatformUserAPIKeyName(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, params GetPlatformUserAPIKeyNameParams)
 
+	// (DELETE /v1/platform/{platform_id}/users/{platform_user_id}/apikeys/{apikey_id})
+	DeletePlatformUserAPIKey(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, apikeyId ApikeyId)
+
 	// (PUT /v1/platform/{platform_id}/users/{platform_user_id}/ban)
-	PutPlatformUserBan(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId)
+	PutPlatformUserBan(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, params PutPlatformUserBanParams)
+
+	// (GET /v1/platform/{platform_id}/users/{platform_user_id}/bans)
+	GetPlatformUserBans(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId)
+
+	// (POST /v1/platform/{platform_id}/users/{platform_user_id}/erase)
+	PostPlatformUserErase(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId)
+
+	// (GET /v1/platform/{platform_id}/users/{platform_user_id}/export)
+	GetPlatformUserExport(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId)
 
 	// (POST /v1/platform/{platform_id}/users/{platform_user_id}/refresh)
 	PostPlatformUserRefresh(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId)
@@ -323,17 +868,44 @@ type ServerInterface interface {
 	GetServiceSubjectProperties(c *gin.Context, serviceUuid ServiceUuid, subject Subject)
 
 	// (PUT /v1/services/{service_uuid}/properties/{subject})
-	PutServiceSubjectProperties(c *gin.Context, serviceUuid ServiceUuid, subject Subject)
+	PutServiceSubjectProperties(c *gin.Context, serviceUuid ServiceUuid, subject Subject, params PutServiceSubjectPropertiesParams)
 
 	// (GET /v1/services/{service_uuid}/properties/{subject}/{property_name})
 	GetServiceSubjectProperty(c *gin.Context, serviceUuid ServiceUuid, subject Subject, propertyName PropertyName)
 
 	// (PUT /v1/services/{service_uuid}/properties/{subject}/{property_name})
-	PutServiceSubjectProperty(c *gin.Context, serviceUuid ServiceUuid, subject Subject, propertyName PropertyName)
+	PutServiceSubjectProperty(c *gin.Context, serviceUuid ServiceUuid, subject Subject, propertyName PropertyName, params PutServiceSubjectPropertyParams)
+
+	// (GET /v1/services/{service_uuid}/webhooks)
+	GetServiceWebhooks(c *gin.Context, serviceUuid ServiceUuid)
+
+	// (POST /v1/services/{service_uuid}/webhooks)
+	PostServiceWebhook(c *gin.Context, serviceUuid ServiceUuid)
+
+	// (DELETE /v1/services/{service_uuid}/webhooks/{webhook_id})
+	DeleteServiceWebhook(c *gin.Context, serviceUuid ServiceUuid, webhookId WebhookId)
+
+	// (GET /v1/services/{service_uuid}/webhooks/{webhook_id}/dead-letters)
+	GetServiceWebhookDeadLetters(c *gin.Context, serviceUuid ServiceUuid, webhookId WebhookId)
+
+	// (POST /v1/services/{service_uuid}/webhooks/{webhook_id}/dead-letters/{delivery_id}/retry)
+	PostServiceWebhookDeadLetterRetry(c *gin.Context, serviceUuid ServiceUuid, webhookId WebhookId, deliveryId DeliveryId)
+
+	// (POST /v1/services/{service_uuid}/webhooks/{webhook_id}/ping)
+	PostServiceWebhookPing(c *gin.Context, serviceUuid ServiceUuid, webhookId WebhookId)
+
+	// (GET /v1/verification/platform/{platform_id}/users)
+	GetVerificationPlatformUsers(c *gin.Context, platformId PlatformId, params GetVerificationPlatformUsersParams)
+
+	// (POST /v1/verification/platform/{platform_id}/users)
+	PostVerificationPlatformUsers(c *gin.Context, platformId PlatformId, params PostVerificationPlatformUsersParams)
 
 	// (GET /v1/verification/platform/{platform_id}/users/updates)
 	GetVerificationPlatformUserUpdates(c *gin.Context, platformId PlatformId, params GetVerificationPlatformUserUpdatesParams)
 
+	// (GET /v1/verification/platform/{platform_id}/users/updates/stream)
+	GetVerificationPlatformUserUpdatesStream(c *gin.Context, platformId PlatformId, params GetVerificationPlatformUserUpdatesStreamParams)
+
 	// (GET /v1/verification/platform/{platform_id}/users/{platform_user_id})
 	GetVerificationPlatformUserStatus(c *gin.Context, platformId PlatformId, platformUserId PlatformUserId, params GetVerificationPlatformUserStatusParams)
 
@@ -353,48 +925,40 @@ type ServerInterfaceWrapper struct {
 
 type MiddlewareFunc func(c *gin.Context)
 
-// PostChannelPlatformStatistics operation middleware
-func (siw *ServerInterfaceWrapper) PostChannelPlatformStatistics(c *gin.Context) {
-
-	var err error
+// GetAdminGW2APIUsage operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminGW2APIUsage(c *gin.Context) {
 
-	// ------------- Path parameter "platform_id" -------------
-	var platformId PlatformId
+	c.Set(BearerAuthScopes, []string{"admin"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
-		return
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
 	}
 
-	// ------------- Path parameter "channel" -------------
-	var channel string
-
-	err = runtime.BindStyledParameterWithOptions("simple", "channel", c.Param("channel"), &channel, runtime.BindStyledParameterOptions{Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter channel: %w", err), http.StatusBadRequest)
-		return
-	}
+	siw.Handler.GetAdminGW2APIUsage(c)
+}
 
-	c.Set(BearerAuthScopes, []string{})
+// GetAdminServices operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminServices(c *gin.Context) {
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params PostChannelPlatformStatisticsParams
+	c.Set(BearerAuthScopes, []string{"admin"})
 
-	// ------------- Required query parameter "world" -------------
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
 
-	if paramValue := c.Query("world"); paramValue != "" {
+	siw.Handler.GetAdminServices(c)
+}
 
-	} else {
-		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
-		return
-	}
+// PostAdminService operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminService(c *gin.Context) {
 
-	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
-		return
-	}
+	c.Set(BearerAuthScopes, []string{"admin"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -403,27 +967,25 @@ func (siw *ServerInterfaceWrapper) PostChannelPlatformStatistics(c *gin.Context)
 		}
 	}
 
-	siw.Handler.PostChannelPlatformStatistics(c, platformId, channel, params)
+	siw.Handler.PostAdminService(c)
 }
 
-// GetV1Configuration operation middleware
-func (siw *ServerInterfaceWrapper) GetV1Configuration(c *gin.Context) {
+// GetAdminService operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminService(c *gin.Context) {
 
 	var err error
 
-	c.Set(BearerAuthScopes, []string{})
-
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetV1ConfigurationParams
-
-	// ------------- Optional query parameter "world" -------------
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
+	c.Set(BearerAuthScopes, []string{"admin"})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -431,24 +993,24 @@ func (siw *ServerInterfaceWrapper) GetV1Configuration(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetV1Configuration(c, params)
+	siw.Handler.GetAdminService(c, serviceUuid)
 }
 
-// GetGuildUsers operation middleware
-func (siw *ServerInterfaceWrapper) GetGuildUsers(c *gin.Context) {
+// PatchAdminService operation middleware
+func (siw *ServerInterfaceWrapper) PatchAdminService(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "guild_ident" -------------
-	var guildIdent GuildIdent
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindStyledParameterWithOptions("simple", "guild_ident", c.Param("guild_ident"), &guildIdent, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter guild_ident: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"admin"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -457,40 +1019,861 @@ func (siw *ServerInterfaceWrapper) GetGuildUsers(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetGuildUsers(c, guildIdent)
+	siw.Handler.PatchAdminService(c, serviceUuid)
 }
 
-// GetPlatformUserUpdates operation middleware
-func (siw *ServerInterfaceWrapper) GetPlatformUserUpdates(c *gin.Context) {
+// PostAdminServiceDisable operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminServiceDisable(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "platform_id" -------------
-	var platformId PlatformId
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
-
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetPlatformUserUpdatesParams
-
-	// ------------- Required query parameter "world" -------------
+	c.Set(BearerAuthScopes, []string{"admin"})
 
-	if paramValue := c.Query("world"); paramValue != "" {
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostAdminServiceDisable(c, serviceUuid)
+}
+
+// PostAdminServiceRotate operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminServiceRotate(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
+
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"admin"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostAdminServiceRotate(c, serviceUuid)
+}
+
+// PostAdminUserMerge operation middleware
+func (siw *ServerInterfaceWrapper) PostAdminUserMerge(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "user_id" -------------
+	var userId UserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", c.Param("user_id"), &userId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"admin"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostAdminUserMerge(c, userId)
+}
+
+// GetAdminUserMerges operation middleware
+func (siw *ServerInterfaceWrapper) GetAdminUserMerges(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "user_id" -------------
+	var userId UserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "user_id", c.Param("user_id"), &userId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"admin"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetAdminUserMerges(c, userId)
+}
+
+// GetAuditEntries operation middleware
+func (siw *ServerInterfaceWrapper) GetAuditEntries(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"audit:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetAuditEntriesParams
+
+	// ------------- Optional query parameter "service_uuid" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "service_uuid", c.Request.URL.Query(), &params.ServiceUuid)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", c.Request.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "platform_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "platform_id", c.Request.URL.Query(), &params.PlatformId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "platform_user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "platform_user_id", c.Request.URL.Query(), &params.PlatformUserId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "action" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "action", c.Request.URL.Query(), &params.Action)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter action: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetAuditEntries(c, params)
+}
+
+// GetBans operation middleware
+func (siw *ServerInterfaceWrapper) GetBans(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetBansParams
+
+	// ------------- Optional query parameter "active" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "active", c.Request.URL.Query(), &params.Active)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter active: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "user_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "user_id", c.Request.URL.Query(), &params.UserId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "reason" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "reason", c.Request.URL.Query(), &params.Reason)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter reason: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetBans(c, params)
+}
+
+// DeleteBan operation middleware
+func (siw *ServerInterfaceWrapper) DeleteBan(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "ban_id" -------------
+	var banId BanId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ban_id", c.Param("ban_id"), &banId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ban_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"ban:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeleteBan(c, banId)
+}
+
+// GetBan operation middleware
+func (siw *ServerInterfaceWrapper) GetBan(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "ban_id" -------------
+	var banId BanId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ban_id", c.Param("ban_id"), &banId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ban_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetBan(c, banId)
+}
+
+// PatchBan operation middleware
+func (siw *ServerInterfaceWrapper) PatchBan(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "ban_id" -------------
+	var banId BanId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "ban_id", c.Param("ban_id"), &banId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter ban_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"ban:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PatchBan(c, banId)
+}
+
+// PostChannelPlatformStatistics operation middleware
+func (siw *ServerInterfaceWrapper) PostChannelPlatformStatistics(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "channel" -------------
+	var channel string
+
+	err = runtime.BindStyledParameterWithOptions("simple", "channel", c.Param("channel"), &channel, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter channel: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"statistics:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostChannelPlatformStatisticsParams
+
+	// ------------- Required query parameter "world" -------------
+
+	if paramValue := c.Query("world"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostChannelPlatformStatistics(c, platformId, channel, params)
+}
+
+// GetV1Configuration operation middleware
+func (siw *ServerInterfaceWrapper) GetV1Configuration(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetV1ConfigurationParams
+
+	// ------------- Optional query parameter "world" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetV1Configuration(c, params)
+}
+
+// GetGuildUsers operation middleware
+func (siw *ServerInterfaceWrapper) GetGuildUsers(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "guild_ident" -------------
+	var guildIdent GuildIdent
+
+	err = runtime.BindStyledParameterWithOptions("simple", "guild_ident", c.Param("guild_ident"), &guildIdent, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter guild_ident: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetGuildUsers(c, guildIdent)
+}
+
+// GetMatchups operation middleware
+func (siw *ServerInterfaceWrapper) GetMatchups(c *gin.Context) {
+
+	var err error
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetMatchupsParams
+
+	// ------------- Optional query parameter "world" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetMatchups(c, params)
+}
+
+// GetPlatformUserUpdates operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatformUserUpdates(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPlatformUserUpdatesParams
+
+	// ------------- Required query parameter "world" -------------
+
+	if paramValue := c.Query("world"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatformUserUpdates(c, platformId, params)
+}
+
+// GetPlatformUserUpdatesStream operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatformUserUpdatesStream(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPlatformUserUpdatesStreamParams
+
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatformUserUpdatesStream(c, platformId, params)
+}
+
+// DeletePlatformUser operation middleware
+func (siw *ServerInterfaceWrapper) DeletePlatformUser(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"apikey:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeletePlatformUser(c, platformId, platformUserId)
+}
+
+// GetPlatformUser operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatformUser(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPlatformUserParams
+
+	// ------------- Optional query parameter "display_name" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "display_name", c.Request.URL.Query(), &params.DisplayName)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter display_name: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatformUser(c, platformId, platformUserId, params)
+}
+
+// DeletePlatformUserAccount operation middleware
+func (siw *ServerInterfaceWrapper) DeletePlatformUserAccount(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "account_id" -------------
+	var accountId AccountId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "account_id", c.Param("account_id"), &accountId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter account_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"apikey:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeletePlatformUserAccount(c, platformId, platformUserId, accountId)
+}
+
+// PutPlatformUserAPIKey operation middleware
+func (siw *ServerInterfaceWrapper) PutPlatformUserAPIKey(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"apikey:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutPlatformUserAPIKeyParams
+
+	// ------------- Optional query parameter "skip-requirements" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "skip-requirements", c.Request.URL.Query(), &params.SkipRequirements)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter skip-requirements: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "world" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PutPlatformUserAPIKey(c, platformId, platformUserId, params)
+}
+
+// GetPlatformUserAPIKeyName operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatformUserAPIKeyName(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetPlatformUserAPIKeyNameParams
+
+	// ------------- Optional query parameter "world" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatformUserAPIKeyName(c, platformId, platformUserId, params)
+}
+
+// DeletePlatformUserAPIKey operation middleware
+func (siw *ServerInterfaceWrapper) DeletePlatformUserAPIKey(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "apikey_id" -------------
+	var apikeyId ApikeyId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "apikey_id", c.Param("apikey_id"), &apikeyId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter apikey_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"apikey:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.DeletePlatformUserAPIKey(c, platformId, platformUserId, apikeyId)
+}
 
-	} else {
-		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
+// PutPlatformUserBan operation middleware
+func (siw *ServerInterfaceWrapper) PutPlatformUserBan(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"ban:write"})
+
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutPlatformUserBanParams
+
+	// ------------- Optional query parameter "all_identities" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "all_identities", c.Request.URL.Query(), &params.AllIdentities)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter all_identities: %w", err), http.StatusBadRequest)
 		return
 	}
 
@@ -501,11 +1884,116 @@ func (siw *ServerInterfaceWrapper) GetPlatformUserUpdates(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetPlatformUserUpdates(c, platformId, params)
+	siw.Handler.PutPlatformUserBan(c, platformId, platformUserId, params)
 }
 
-// GetPlatformUser operation middleware
-func (siw *ServerInterfaceWrapper) GetPlatformUser(c *gin.Context) {
+// GetPlatformUserBans operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatformUserBans(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"status:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatformUserBans(c, platformId, platformUserId)
+}
+
+// PostPlatformUserErase operation middleware
+func (siw *ServerInterfaceWrapper) PostPlatformUserErase(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"privacy:erase"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostPlatformUserErase(c, platformId, platformUserId)
+}
+
+// GetPlatformUserExport operation middleware
+func (siw *ServerInterfaceWrapper) GetPlatformUserExport(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "platform_user_id" -------------
+	var platformUserId PlatformUserId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"privacy:read"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetPlatformUserExport(c, platformId, platformUserId)
+}
+
+// PostPlatformUserRefresh operation middleware
+func (siw *ServerInterfaceWrapper) PostPlatformUserRefresh(c *gin.Context) {
 
 	var err error
 
@@ -527,19 +2015,159 @@ func (siw *ServerInterfaceWrapper) GetPlatformUser(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"apikey:write"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PostPlatformUserRefresh(c, platformId, platformUserId)
+}
+
+// GetServiceProperties operation middleware
+func (siw *ServerInterfaceWrapper) GetServiceProperties(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
+
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"properties:own"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetServiceProperties(c, serviceUuid)
+}
+
+// GetServiceSubjectProperties operation middleware
+func (siw *ServerInterfaceWrapper) GetServiceSubjectProperties(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
+
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "subject" -------------
+	var subject Subject
+
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"properties:own"})
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.GetServiceSubjectProperties(c, serviceUuid, subject)
+}
+
+// PutServiceSubjectProperties operation middleware
+func (siw *ServerInterfaceWrapper) PutServiceSubjectProperties(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
+
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "subject" -------------
+	var subject Subject
+
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"properties:own"})
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetPlatformUserParams
+	var params PutServiceSubjectPropertiesParams
+
+	// ------------- Optional query parameter "platform_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "platform_id", c.Request.URL.Query(), &params.PlatformId)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
+	}
+
+	siw.Handler.PutServiceSubjectProperties(c, serviceUuid, subject, params)
+}
+
+// GetServiceSubjectProperty operation middleware
+func (siw *ServerInterfaceWrapper) GetServiceSubjectProperty(c *gin.Context) {
+
+	var err error
+
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
+
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "subject" -------------
+	var subject Subject
+
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+		return
+	}
 
-	// ------------- Optional query parameter "display_name" -------------
+	// ------------- Path parameter "property_name" -------------
+	var propertyName PropertyName
 
-	err = runtime.BindQueryParameter("form", true, false, "display_name", c.Request.URL.Query(), &params.DisplayName)
+	err = runtime.BindStyledParameterWithOptions("simple", "property_name", c.Param("property_name"), &propertyName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter display_name: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter property_name: %w", err), http.StatusBadRequest)
 		return
 	}
 
+	c.Set(BearerAuthScopes, []string{"properties:own"})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -547,50 +2175,51 @@ func (siw *ServerInterfaceWrapper) GetPlatformUser(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetPlatformUser(c, platformId, platformUserId, params)
+	siw.Handler.GetServiceSubjectProperty(c, serviceUuid, subject, propertyName)
 }
 
-// PutPlatformUserAPIKey operation middleware
-func (siw *ServerInterfaceWrapper) PutPlatformUserAPIKey(c *gin.Context) {
+// PutServiceSubjectProperty operation middleware
+func (siw *ServerInterfaceWrapper) PutServiceSubjectProperty(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "platform_id" -------------
-	var platformId PlatformId
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "platform_user_id" -------------
-	var platformUserId PlatformUserId
+	// ------------- Path parameter "subject" -------------
+	var subject Subject
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
-
-	// Parameter object where we will unmarshal all parameters from the context
-	var params PutPlatformUserAPIKeyParams
-
-	// ------------- Optional query parameter "skip-requirements" -------------
+	// ------------- Path parameter "property_name" -------------
+	var propertyName PropertyName
 
-	err = runtime.BindQueryParameter("form", true, false, "skip-requirements", c.Request.URL.Query(), &params.SkipRequirements)
+	err = runtime.BindStyledParameterWithOptions("simple", "property_name", c.Param("property_name"), &propertyName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter skip-requirements: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter property_name: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Optional query parameter "world" -------------
+	c.Set(BearerAuthScopes, []string{"properties:own"})
 
-	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PutServiceSubjectPropertyParams
+
+	// ------------- Optional query parameter "platform_id" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "platform_id", c.Request.URL.Query(), &params.PlatformId)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
@@ -601,45 +2230,51 @@ func (siw *ServerInterfaceWrapper) PutPlatformUserAPIKey(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PutPlatformUserAPIKey(c, platformId, platformUserId, params)
+	siw.Handler.PutServiceSubjectProperty(c, serviceUuid, subject, propertyName, params)
 }
 
-// GetPlatformUserAPIKeyName operation middleware
-func (siw *ServerInterfaceWrapper) GetPlatformUserAPIKeyName(c *gin.Context) {
+// GetServiceWebhooks operation middleware
+func (siw *ServerInterfaceWrapper) GetServiceWebhooks(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "platform_id" -------------
-	var platformId PlatformId
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "platform_user_id" -------------
-	var platformUserId PlatformUserId
+	c.Set(BearerAuthScopes, []string{"webhooks:own"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
-		return
+	for _, middleware := range siw.HandlerMiddlewares {
+		middleware(c)
+		if c.IsAborted() {
+			return
+		}
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	siw.Handler.GetServiceWebhooks(c, serviceUuid)
+}
 
-	// Parameter object where we will unmarshal all parameters from the context
-	var params GetPlatformUserAPIKeyNameParams
+// PostServiceWebhook operation middleware
+func (siw *ServerInterfaceWrapper) PostServiceWebhook(c *gin.Context) {
 
-	// ------------- Optional query parameter "world" -------------
+	var err error
 
-	err = runtime.BindQueryParameter("form", true, false, "world", c.Request.URL.Query(), &params.World)
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
+
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
+	c.Set(BearerAuthScopes, []string{"webhooks:own"})
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -647,33 +2282,33 @@ func (siw *ServerInterfaceWrapper) GetPlatformUserAPIKeyName(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetPlatformUserAPIKeyName(c, platformId, platformUserId, params)
+	siw.Handler.PostServiceWebhook(c, serviceUuid)
 }
 
-// PutPlatformUserBan operation middleware
-func (siw *ServerInterfaceWrapper) PutPlatformUserBan(c *gin.Context) {
+// DeleteServiceWebhook operation middleware
+func (siw *ServerInterfaceWrapper) DeleteServiceWebhook(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "platform_id" -------------
-	var platformId PlatformId
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "platform_user_id" -------------
-	var platformUserId PlatformUserId
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", c.Param("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter webhook_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"webhooks:own"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -682,33 +2317,33 @@ func (siw *ServerInterfaceWrapper) PutPlatformUserBan(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PutPlatformUserBan(c, platformId, platformUserId)
+	siw.Handler.DeleteServiceWebhook(c, serviceUuid, webhookId)
 }
 
-// PostPlatformUserRefresh operation middleware
-func (siw *ServerInterfaceWrapper) PostPlatformUserRefresh(c *gin.Context) {
+// GetServiceWebhookDeadLetters operation middleware
+func (siw *ServerInterfaceWrapper) GetServiceWebhookDeadLetters(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "platform_id" -------------
-	var platformId PlatformId
+	// ------------- Path parameter "service_uuid" -------------
+	var serviceUuid ServiceUuid
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "platform_user_id" -------------
-	var platformUserId PlatformUserId
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "platform_user_id", c.Param("platform_user_id"), &platformUserId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", c.Param("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_user_id: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter webhook_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"webhooks:own"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -717,11 +2352,11 @@ func (siw *ServerInterfaceWrapper) PostPlatformUserRefresh(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PostPlatformUserRefresh(c, platformId, platformUserId)
+	siw.Handler.GetServiceWebhookDeadLetters(c, serviceUuid, webhookId)
 }
 
-// GetServiceProperties operation middleware
-func (siw *ServerInterfaceWrapper) GetServiceProperties(c *gin.Context) {
+// PostServiceWebhookDeadLetterRetry operation middleware
+func (siw *ServerInterfaceWrapper) PostServiceWebhookDeadLetterRetry(c *gin.Context) {
 
 	var err error
 
@@ -734,7 +2369,25 @@ func (siw *ServerInterfaceWrapper) GetServiceProperties(c *gin.Context) {
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", c.Param("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter webhook_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Path parameter "delivery_id" -------------
+	var deliveryId DeliveryId
+
+	err = runtime.BindStyledParameterWithOptions("simple", "delivery_id", c.Param("delivery_id"), &deliveryId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter delivery_id: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	c.Set(BearerAuthScopes, []string{"webhooks:own"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -743,11 +2396,11 @@ func (siw *ServerInterfaceWrapper) GetServiceProperties(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetServiceProperties(c, serviceUuid)
+	siw.Handler.PostServiceWebhookDeadLetterRetry(c, serviceUuid, webhookId, deliveryId)
 }
 
-// GetServiceSubjectProperties operation middleware
-func (siw *ServerInterfaceWrapper) GetServiceSubjectProperties(c *gin.Context) {
+// PostServiceWebhookPing operation middleware
+func (siw *ServerInterfaceWrapper) PostServiceWebhookPing(c *gin.Context) {
 
 	var err error
 
@@ -760,16 +2413,16 @@ func (siw *ServerInterfaceWrapper) GetServiceSubjectProperties(c *gin.Context) {
 		return
 	}
 
-	// ------------- Path parameter "subject" -------------
-	var subject Subject
+	// ------------- Path parameter "webhook_id" -------------
+	var webhookId WebhookId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "webhook_id", c.Param("webhook_id"), &webhookId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter webhook_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"webhooks:own"})
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -778,33 +2431,58 @@ func (siw *ServerInterfaceWrapper) GetServiceSubjectProperties(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetServiceSubjectProperties(c, serviceUuid, subject)
+	siw.Handler.PostServiceWebhookPing(c, serviceUuid, webhookId)
 }
 
-// PutServiceSubjectProperties operation middleware
-func (siw *ServerInterfaceWrapper) PutServiceSubjectProperties(c *gin.Context) {
+// GetVerificationPlatformUsers operation middleware
+func (siw *ServerInterfaceWrapper) GetVerificationPlatformUsers(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "service_uuid" -------------
-	var serviceUuid ServiceUuid
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "subject" -------------
-	var subject Subject
+	c.Set(BearerAuthScopes, []string{"status:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetVerificationPlatformUsersParams
+
+	// ------------- Optional query parameter "limit" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "limit", c.Request.URL.Query(), &params.Limit)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter limit: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "offset" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "offset", c.Request.URL.Query(), &params.Offset)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter offset: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Required query parameter "world" -------------
+
+	if paramValue := c.Query("world"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
+		return
+	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -813,43 +2491,43 @@ func (siw *ServerInterfaceWrapper) PutServiceSubjectProperties(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PutServiceSubjectProperties(c, serviceUuid, subject)
+	siw.Handler.GetVerificationPlatformUsers(c, platformId, params)
 }
 
-// GetServiceSubjectProperty operation middleware
-func (siw *ServerInterfaceWrapper) GetServiceSubjectProperty(c *gin.Context) {
+// PostVerificationPlatformUsers operation middleware
+func (siw *ServerInterfaceWrapper) PostVerificationPlatformUsers(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "service_uuid" -------------
-	var serviceUuid ServiceUuid
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "subject" -------------
-	var subject Subject
+	c.Set(BearerAuthScopes, []string{"status:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
-	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+	// Parameter object where we will unmarshal all parameters from the context
+	var params PostVerificationPlatformUsersParams
+
+	// ------------- Required query parameter "world" -------------
+
+	if paramValue := c.Query("world"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "property_name" -------------
-	var propertyName PropertyName
-
-	err = runtime.BindStyledParameterWithOptions("simple", "property_name", c.Param("property_name"), &propertyName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter property_name: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
-
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -857,42 +2535,58 @@ func (siw *ServerInterfaceWrapper) GetServiceSubjectProperty(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.GetServiceSubjectProperty(c, serviceUuid, subject, propertyName)
+	siw.Handler.PostVerificationPlatformUsers(c, platformId, params)
 }
 
-// PutServiceSubjectProperty operation middleware
-func (siw *ServerInterfaceWrapper) PutServiceSubjectProperty(c *gin.Context) {
+// GetVerificationPlatformUserUpdates operation middleware
+func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserUpdates(c *gin.Context) {
 
 	var err error
 
-	// ------------- Path parameter "service_uuid" -------------
-	var serviceUuid ServiceUuid
+	// ------------- Path parameter "platform_id" -------------
+	var platformId PlatformId
 
-	err = runtime.BindStyledParameterWithOptions("simple", "service_uuid", c.Param("service_uuid"), &serviceUuid, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindStyledParameterWithOptions("simple", "platform_id", c.Param("platform_id"), &platformId, runtime.BindStyledParameterOptions{Explode: false, Required: true})
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter service_uuid: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter platform_id: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "subject" -------------
-	var subject Subject
+	c.Set(BearerAuthScopes, []string{"status:read"})
 
-	err = runtime.BindStyledParameterWithOptions("simple", "subject", c.Param("subject"), &subject, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	// Parameter object where we will unmarshal all parameters from the context
+	var params GetVerificationPlatformUserUpdatesParams
+
+	// ------------- Required query parameter "world" -------------
+
+	if paramValue := c.Query("world"); paramValue != "" {
+
+	} else {
+		siw.ErrorHandler(c, fmt.Errorf("Query argument world is required, but not found"), http.StatusBadRequest)
+		return
+	}
+
+	err = runtime.BindQueryParameter("form", true, true, "world", c.Request.URL.Query(), &params.World)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter subject: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter world: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	// ------------- Path parameter "property_name" -------------
-	var propertyName PropertyName
+	// ------------- Optional query parameter "since" -------------
 
-	err = runtime.BindStyledParameterWithOptions("simple", "property_name", c.Param("property_name"), &propertyName, runtime.BindStyledParameterOptions{Explode: false, Required: true})
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
 	if err != nil {
-		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter property_name: %w", err), http.StatusBadRequest)
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", c.Request.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter status: %w", err), http.StatusBadRequest)
+		return
+	}
 
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
@@ -901,11 +2595,11 @@ func (siw *ServerInterfaceWrapper) PutServiceSubjectProperty(c *gin.Context) {
 		}
 	}
 
-	siw.Handler.PutServiceSubjectProperty(c, serviceUuid, subject, propertyName)
+	siw.Handler.GetVerificationPlatformUserUpdates(c, platformId, params)
 }
 
-// GetVerificationPlatformUserUpdates operation middleware
-func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserUpdates(c *gin.Context) {
+// GetVerificationPlatformUserUpdatesStream operation middleware
+func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserUpdatesStream(c *gin.Context) {
 
 	var err error
 
@@ -918,10 +2612,10 @@ func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserUpdates(c *gin.Con
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"status:read"})
 
 	// Parameter object where we will unmarshal all parameters from the context
-	var params GetVerificationPlatformUserUpdatesParams
+	var params GetVerificationPlatformUserUpdatesStreamParams
 
 	// ------------- Required query parameter "world" -------------
 
@@ -938,6 +2632,22 @@ func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserUpdates(c *gin.Con
 		return
 	}
 
+	// ------------- Optional query parameter "since" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "since", c.Request.URL.Query(), &params.Since)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter since: %w", err), http.StatusBadRequest)
+		return
+	}
+
+	// ------------- Optional query parameter "status" -------------
+
+	err = runtime.BindQueryParameter("form", true, false, "status", c.Request.URL.Query(), &params.Status)
+	if err != nil {
+		siw.ErrorHandler(c, fmt.Errorf("Invalid format for parameter status: %w", err), http.StatusBadRequest)
+		return
+	}
+
 	for _, middleware := range siw.HandlerMiddlewares {
 		middleware(c)
 		if c.IsAborted() {
@@ -945,7 +2655,7 @@ func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserUpdates(c *gin.Con
 		}
 	}
 
-	siw.Handler.GetVerificationPlatformUserUpdates(c, platformId, params)
+	siw.Handler.GetVerificationPlatformUserUpdatesStream(c, platformId, params)
 }
 
 // GetVerificationPlatformUserStatus operation middleware
@@ -971,7 +2681,7 @@ func (siw *ServerInterfaceWrapper) GetVerificationPlatformUserStatus(c *gin.Cont
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"status:read"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params GetVerificationPlatformUserStatusParams
@@ -1032,7 +2742,7 @@ func (siw *ServerInterfaceWrapper) PostVerificationPlatformUserRefresh(c *gin.Co
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"apikey:write"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params PostVerificationPlatformUserRefreshParams
@@ -1085,7 +2795,7 @@ func (siw *ServerInterfaceWrapper) PutVerificationPlatformUserTemporary(c *gin.C
 		return
 	}
 
-	c.Set(BearerAuthScopes, []string{})
+	c.Set(BearerAuthScopes, []string{"temporary:write"})
 
 	// Parameter object where we will unmarshal all parameters from the context
 	var params PutVerificationPlatformUserTemporaryParams
@@ -1142,21 +2852,52 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 		ErrorHandler:       errorHandler,
 	}
 
+	router.GET(options.BaseURL+"/v1/admin/gw2api/usage", wrapper.GetAdminGW2APIUsage)
+	router.GET(options.BaseURL+"/v1/admin/services", wrapper.GetAdminServices)
+	router.POST(options.BaseURL+"/v1/admin/services", wrapper.PostAdminService)
+	router.GET(options.BaseURL+"/v1/admin/services/:service_uuid", wrapper.GetAdminService)
+	router.PATCH(options.BaseURL+"/v1/admin/services/:service_uuid", wrapper.PatchAdminService)
+	router.POST(options.BaseURL+"/v1/admin/services/:service_uuid/disable", wrapper.PostAdminServiceDisable)
+	router.POST(options.BaseURL+"/v1/admin/services/:service_uuid/rotate", wrapper.PostAdminServiceRotate)
+	router.POST(options.BaseURL+"/v1/admin/users/:user_id/merge", wrapper.PostAdminUserMerge)
+	router.GET(options.BaseURL+"/v1/admin/users/:user_id/merges", wrapper.GetAdminUserMerges)
+	router.GET(options.BaseURL+"/v1/audit", wrapper.GetAuditEntries)
+	router.GET(options.BaseURL+"/v1/bans", wrapper.GetBans)
+	router.DELETE(options.BaseURL+"/v1/bans/:ban_id", wrapper.DeleteBan)
+	router.GET(options.BaseURL+"/v1/bans/:ban_id", wrapper.GetBan)
+	router.PATCH(options.BaseURL+"/v1/bans/:ban_id", wrapper.PatchBan)
 	router.POST(options.BaseURL+"/v1/channels/:platform_id/:channel/statistics", wrapper.PostChannelPlatformStatistics)
 	router.GET(options.BaseURL+"/v1/configuration", wrapper.GetV1Configuration)
 	router.GET(options.BaseURL+"/v1/guilds/:guild_ident/users", wrapper.GetGuildUsers)
+	router.GET(options.BaseURL+"/v1/matchups", wrapper.GetMatchups)
 	router.GET(options.BaseURL+"/v1/platform/:platform_id/users/updates", wrapper.GetPlatformUserUpdates)
+	router.GET(options.BaseURL+"/v1/platform/:platform_id/users/updates/stream", wrapper.GetPlatformUserUpdatesStream)
+	router.DELETE(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id", wrapper.DeletePlatformUser)
 	router.GET(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id", wrapper.GetPlatformUser)
+	router.DELETE(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/accounts/:account_id", wrapper.DeletePlatformUserAccount)
 	router.PUT(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/apikey", wrapper.PutPlatformUserAPIKey)
 	router.GET(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/apikey/name", wrapper.GetPlatformUserAPIKeyName)
+	router.DELETE(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/apikeys/:apikey_id", wrapper.DeletePlatformUserAPIKey)
 	router.PUT(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/ban", wrapper.PutPlatformUserBan)
+	router.GET(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/bans", wrapper.GetPlatformUserBans)
+	router.POST(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/erase", wrapper.PostPlatformUserErase)
+	router.GET(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/export", wrapper.GetPlatformUserExport)
 	router.POST(options.BaseURL+"/v1/platform/:platform_id/users/:platform_user_id/refresh", wrapper.PostPlatformUserRefresh)
 	router.GET(options.BaseURL+"/v1/services/:service_uuid/properties", wrapper.GetServiceProperties)
 	router.GET(options.BaseURL+"/v1/services/:service_uuid/properties/:subject", wrapper.GetServiceSubjectProperties)
 	router.PUT(options.BaseURL+"/v1/services/:service_uuid/properties/:subject", wrapper.PutServiceSubjectProperties)
 	router.GET(options.BaseURL+"/v1/services/:service_uuid/properties/:subject/:property_name", wrapper.GetServiceSubjectProperty)
 	router.PUT(options.BaseURL+"/v1/services/:service_uuid/properties/:subject/:property_name", wrapper.PutServiceSubjectProperty)
+	router.GET(options.BaseURL+"/v1/services/:service_uuid/webhooks", wrapper.GetServiceWebhooks)
+	router.POST(options.BaseURL+"/v1/services/:service_uuid/webhooks", wrapper.PostServiceWebhook)
+	router.DELETE(options.BaseURL+"/v1/services/:service_uuid/webhooks/:webhook_id", wrapper.DeleteServiceWebhook)
+	router.GET(options.BaseURL+"/v1/services/:service_uuid/webhooks/:webhook_id/dead-letters", wrapper.GetServiceWebhookDeadLetters)
+	router.POST(options.BaseURL+"/v1/services/:service_uuid/webhooks/:webhook_id/dead-letters/:delivery_id/retry", wrapper.PostServiceWebhookDeadLetterRetry)
+	router.POST(options.BaseURL+"/v1/services/:service_uuid/webhooks/:webhook_id/ping", wrapper.PostServiceWebhookPing)
+	router.GET(options.BaseURL+"/v1/verification/platform/:platform_id/users", wrapper.GetVerificationPlatformUsers)
+	router.POST(options.BaseURL+"/v1/verification/platform/:platform_id/users", wrapper.PostVerificationPlatformUsers)
 	router.GET(options.BaseURL+"/v1/verification/platform/:platform_id/users/updates", wrapper.GetVerificationPlatformUserUpdates)
+	router.GET(options.BaseURL+"/v1/verification/platform/:platform_id/users/updates/stream", wrapper.GetVerificationPlatformUserUpdatesStream)
 	router.GET(options.BaseURL+"/v1/verification/platform/:platform_id/users/:platform_user_id", wrapper.GetVerificationPlatformUserStatus)
 	router.POST(options.BaseURL+"/v1/verification/platform/:platform_id/users/:platform_user_id/refresh", wrapper.PostVerificationPlatformUserRefresh)
 	router.PUT(options.BaseURL+"/v1/verification/platform/:platform_id/users/:platform_user_id/temporary", wrapper.PutVerificationPlatformUserTemporary)
@@ -1165,58 +2906,159 @@ func RegisterHandlersWithOptions(router gin.IRouter, si ServerInterface, options
 // Base64 encoded, gzipped, json marshaled Swagger object
 var swaggerSpec = []string{
 
-	"H4sIAAAAAAAC/+xc6Y/buBX/Vwi1wH6Rx9lsWhQD5IMTu6mxiTOdY6dFNhBo6dnmWiK1JOWJO/D/XvDQ",
-	"TdtS5srs5ttYIvkO/vguPs2tF7IkZRSoFN7prZdijhOQwPWvZUbiKCARUKl+RiBCTlJJGPVOvaur6Rgx",
-	"jihOALEF0oM93yPqZYrlyvM99c47ra3jexx+zwiHyDuVPAPfE+EKEqwIyG2qhgvJCV16u53vpTGWC8aT",
-	"gERtDl6g1+ga5oJI8NGP6DW6BJyIFPDaRy/RazQmImR8D0/VlTvwRKiEJfA6U5kAbjk7RCEf1lN0zlLg",
-	"chuY5dwkamP6rS+Ab0gIQZa5dJtR8nsGiERqa+UKkB3uVmZtrZ5sZPPfIJR7BMzf9ltTckxkUNd/REQa",
-	"41KbdXHH5m0BZiWxmpb/na+lH56gaxLHaA5ISMYhQljoQTGWIKQaEqGouuB8i+QK6zc8V+DvGfBtKWiN",
-	"vS7SCQgzDlGAM7kCKkmIjShWjSvAkSZm1x/Vh32NPm8Yj6NgQ+CmoNIQQo/oe5qaiwdMbwqOj1E5sOpO",
-	"8SBSRgVoQ2ZoAOeMB+qFehYyKq1hw2kaW80MfxNGi+Xqf+Ww8E69vwxLUzk0b8VwopY0BOuAmtAoZYRK",
-	"dIMFyiiex4AkQ2qJGCSgLcs4UnoCIb3Wpr568VMbo6MwBCGQZGugiNANjknkNRTIOAEq9Qov2itMzSSk",
-	"x6qznXK2IRFE5iQamdS00dn0Z9iOsdQKsFaGGF3ilKxh2178cgUIp0RJKUCiBePFKfL8JqiUeSMJ5o51",
-	"LkAiUjmBYsWyOFKnTR9EM61yOLFEKeaShFmMeXFQT9DlCjigEFPEaLxV8xkFlEI5Rv/AYcgyKk/QBUhJ",
-	"6BJhROGmTudGHXe2Ac5JZNhgcaSWKwWbMxYDpp6BXg7/T7m2Snk/F1OYMW0736p7Zi1TXd1ue2WVvQZr",
-	"YQptrZQtYsVDwvNhZFGbQAQCumA8hKi9Ow0ZNAtOvjUiL/XjWw9olqjh//r4YRJcfzx/P/Z87/109vNk",
-	"bH9+blLyvS8DhlMyCFkES6AD+CI5Hki81LLPMyXrQDM0MvvkwKNmQhsLCYlwWLCCKuYcb9VvvASX3fDV",
-	"dgVr2NaXO2QALtVpnNIFa5FRsi3ZQD0biDVJB7ldG2jDADy3jh1UwCE+XWExSDDd+r8xQk9J9NoiV0UW",
-	"SkEhSxJMldEvJStQ6XshByxBe3qFfSyV08ESBpIk4DqgESbxNsCpW1HRPOi34n2qw6dZHP8POPMpk+pv",
-	"P4IFzmJ5GmacA5WBYkFInKS+CDFVBkDrKJoHWRp9+1xrZuFLak5gazt7HJsFx6HEcRDDBmL3VproPDYB",
-	"Q69jpGf2PHokag+zWrb+fTruJqCfrrWIMRYySFhEFqQPvhNG5eoAwnPD25pYifoLSoTKv78qqeTL1AW7",
-	"EsCnY7WECWGcZI0fFxLL7KjxuTCj1KzNTZBnWcf0e725fqeGdtVzbe2dJcYxXTsEaFE6V+M6k9Gr5iQk",
-	"4KQuzl4qKvHrI06+9K7p6nT6YmNw5ST8ItrMrV3V0JZYqCilznzFpbg86BtM2x6NA7ZBaBt7VJK4O8bv",
-	"DNWGekpxDSN+zqtLtrcrTCnEH0DiyBlJHjxh3V2wpaNYLmi1DI8rpMkJHWC+tmhLgAjwAqjTRO+xdMru",
-	"ZHLfjL0KEZIDTtQPx7wDEDa0/JLR6lJOsRldkGXGi2SyLrB2Sfqd9lVuCyYhSRnHfBuY0CzoNMvYvZjQ",
-	"tYnqoogYT3tW4+AQEK7VEu/1Cq187D0RUuULmoxAhk5LAQ1VNhnvIFtdEJeKJ+kKEuA4HgnBQrJH03Z5",
-	"aWPrQ2JXovDHtxAdA6RDPm/nUpLOq9v4yx+3TwhewMAWUQb7hjW3Vw9zznVt3JlNGxXCHLagUV9qJ7yH",
-	"SpnTcavSVKR0RCW/MaNLldkd25acyb5BlKumeSiIyOlUYNCdVjX11xFwHkW3TeJ9ezBHUbZeCc6ZK0k7",
-	"sWALrz2cWqXQ2Xq3wXEGxwFrrboZ7WLroggb82R89Pbt5OIiGE9m08k4uJr9PPt4PfP8/Pm789HscjIO",
-	"ajl7410thT8wM7icfDj7eD46/+/hNVzjLH+jt28/Xs0ug9nHSzulNWTyn7PpueP5dPbL6P20xaZ9+2Y0",
-	"mzkmnU/+fTU9n3yYWJofJpd3KlKU1QCXQc+zdRcCvufTD55P79G8Th9FVlSR7k+KQxFdCjwhQhBG++TP",
-	"XQkfympKHNa5cNkTZUT3Qrl7hJ7X7h69RJabcKWQOabdOX5jvM/TcfvdHjx8fS0PxANcRuLdIeKM458U",
-	"M93DpC6hmo8zyQgNOSRAZT1ILNK0TpqqBc5PqKG2VXTZvF+Ak4W9DSzjqboFnJuyTQcLUlNZX0X1qwI2",
-	"xLOTXSJWEmWH76mm8s0air6gJHJ7oWhbXQDmwEeZXBXXpjqG149L+K2kTM0eEBsfSSJj9UYXI9E15gK9",
-	"RFXto9HZVIW7wIXJkjYvFVMsBYpT4p16P528OHmh3BiWK83LcPPjMDSVGzG8rQT2u+Gtfb4bKsUQIUnY",
-	"6nj55OxBsBP73Zm7t6wkNqxmHR2Gt67gd599L2XC0ZjzlsUxhBKVcqI5FhAhRu0Vqrn0ReXyCNMICbzR",
-	"d4aJvrtdESEZJyGOUZrxlAkQnta9qXZMI5UEMiFtoSyH7kWpXKMvEPINi7b3duPerCo2jrWNu2q3/y9d",
-	"d+EKSfaS/GE7AQyhn/bNLzgdtlsAdr73N8Nil5mV9oad5kOfhmZBbwkOyLwDiTCqjVW/JCaU0CXiEMMG",
-	"U4nU0VXeRb1XIOEZpebO3HYAoTmTLZi8A/nLj/XKonuH7gcgNUJ7N6SzWh2NFXfYUe1G6yan58kv+2N2",
-	"n+0mm3u44W2lz243LIro7h3naj+xqTAVVU3bGcIh1to7QaOiDBtvfW09ftU10MBeTv7qobwHzXRJzAFx",
-	"SNgGIrTgrKxi/SBQxYU6EKK9wJXm+QHBoRMaByZiWx7eaPcDkeZZIGIspmltfErY3MEQ9MVbtVmzQFju",
-	"rRpOVWtpaEL+/Vh7z+gSpSyOjS0REkHeHaWNCIRANuqdBmO+mgMj1aLjVTHs0cHyxEh49eIfR3Ss8hyW",
-	"SR9RhmCj1kR4g0msu9CijJt90L4ZpcAJi+6CMN8zUfin/DqtN+TuHgt1gelts/a7O+IPa02fPwgUgcQk",
-	"PorMPyEkH8049URKq9rfGV0Hmoe/Fm3DsnPyGxXaHWT4XppJd59m+4yMzqbINDw2UoWsdkZMx6PX2v4G",
-	"iTVJtQPeYE4yUbQ82nA/0ZZNd4AmZLmSKvggVLFU9qk3eod1haE63dVHXN6sf36YHKbSXnsP6cuzOvB3",
-	"OjrDvJzew2g3umS3CHPIARQhydS4ZnOsilE4CXWaU2+UPWj4K228D+gCKlS+O4KnsolfCWNbN3xkUfdZ",
-	"8DeY2gzwB4GWNy/zXniTuc1BhYl5QnTMoL/B9IEKPrqQ+r3Ic5/WlMOCg1g9ERSdRct/KgsrEEaWt7wd",
-	"RsUTEZZYVyg5yIxT87EVhRuTL5q3Cwm8PmGFBZoD0HxFiE7QjEnlBYjQ34VIvAaE0QJukICQ0chd4azC",
-	"/Nxq7slC/KeCmK3vieFt9Vu/3bB+L7LfL8dxUSI8XAe6MKPOqoPupOtuV1R5P0/70uOQi/1DGpW+rrj2",
-	"8WfhGo8DZnhrW6KO5eF22NESooXOhRn+zSPo6+s/rxxfY2tTyEAgyiRa2aucvMoDUVGmfUJoHHcYeY/c",
-	"kaSzEyDOsucGiD98nNLHJgxvax+397YR284WYvvdPjw7+9Ah9qz9a4S+BmXb2Zx0QI+EL1LFwqSBm2bT",
-	"wJ/VLGwqPR+PdNNUJXnoxqnajvLIt0+OPqTvd1HP6C6qO6p7X0yZy+kahm23Vw8IX+Qz/twI/iNXLO/3",
-	"uusugH7CktO99tZ9VZnKcVAftGC178TfU/GqU7jrOvu98qDneKt1pxNSNGE9ozOSffOdZWfZ3uNwWSj8",
-	"YS4u3B8IdL7J6MyD679f1bfEqDQ3OkuyAYpwoq962KIwJc/17FXa0vVhqTakf/qscKrSzPwoZTy23eji",
-	"dDjEKTlZYC5WZAM8BbwWJyFLlM/7fwAAAP//DMpS0BpRAAA=",
+	"H4sIAAAAAAAC/+x9aW/kOLLgXyFyF+h9gHx0vZ7BPgPzwV32VhtTh9d2tXcxVUgwJaaTU0pRQ1J25Rj+",
+	"7w8RPERJlFJpO+1ytz/ZKVE8I4Jxx+0kFctSFKzQanJwOymppEummcRfNE1FVegpz+BXxlQqeam5KCYH",
+	"k3eXb4h9T3g2SSYcnpZULybJpKBLNjkIv08mkv2r4pJlkwMtK5ZMVLpgSwod61UJrZWWvLia3N0lE1ry",
+	"b2wVHfckI2JO9IKRw9MT8o2tCFVEslJIzTIyW+Grq5s3RItvrCC8mIueyfkxNpvbjBZ2YpFO7cuhHudC",
+	"LqmeHEx4of/6yyRxQ/BCsysmcYyM5fyayVX/QGGLh452VfE8m/KMFbq7358/nxwRIQmMCzuPjeM7Gvaz",
+	"2Z6WOdUw0+iJ75O/kUs2U1yzhPxM/kYuGF2qktFvCXlD/kaOuEqF7JlT2POIOYXb4j+tFJP9J9FptuHS",
+	"pSiZ1Kup6S4+RKPNZv0rJq95yqZVFdvbquD/qhjhHqls8/hmNvracBrV7J8s1T0LdG8361NLyvWUXbNC",
+	"T9NKKiG7CzyGt8H6cqo0kSxl/JplBL/dJSdzUkpxzTOWJeaZIkuuFMuI4kXK4MuCUMmA0OR0Bc1EnjGl",
+	"yZxLpRMyY3MhGbmhXPPiiswBYdiN7ctt5r8qJlfBoqHryabIahbdBLqMK5iWB6HmFhyZtx6DYRvgM/e/",
+	"6wsf7pJLnudkxojSQrIMqKvZNw3LrWBPsrBDpLgU38iehTamN+ZIFUsrybIprWDjNU+pWYqFnQWjGQ5m",
+	"+z9sNrsPEClNdaWmc55rFoGiT0W+clBDrpnkczsYqcoMdobccL0govAbfMWvWUFMt0ztksM8978sJFkY",
+	"5HMillxrlvXBCX7W2Diu2RJv6P8p2XxyMPkfe/U1vmeaqb1z892dByMqJV0Fi74RMs+m15zd+K1tDY0t",
+	"NqWb7c6nAreR5utGGex1mAiPob1jsOuGzRZCfOsfKGjwsLHu4HNVikIxPElLzKQUcgov4FkqCm0vZVqW",
+	"uYW5vX8qgwz1cENgcAxdmgFbpLHISsELTW6oIlVBZzkjWhDoImeakZWoJIElMqUn/lyvbt7Qkk8l1Wya",
+	"8yXXLJv+8ua/tj/diwUjwHQC28cVgQkQnAAQXDtNtUvOmJYrQueaSUTEolrODK1TLBVFpggv8AU23DnE",
+	"hp6imH/wQIL3zdl3j7JDuH7Z/88uETlMU6aU50uvac6zSQtfhOSswC3d34/wvuYjgm3hUnO3lrlizQ7C",
+	"Z4enJ39nqyOqccKWfeAG0Azf2+0c9peWHEBAMY1XmLspJkmbcCaTUvIllZF+zpkGmua+JWohqjyDGwUe",
+	"2c+CC4hqUlKpeVrlVPrLaJdcLJhkJKUFEUB9ZwzJa8nqNvjDyhi75JxpBAWKN29jnBu40sQ1k5JnZhoi",
+	"z6C7emEzIXJGi8ndXYjW/3C7Va/3q/9EGJ7lLrHb/dHevs3tjt/JdrNBfIEG9W4t4L4V/iGXrhmfNz7g",
+	"irBiLmSK10b3WgvXgFOIzhsh8gIf305YUS2h+W+fPhxPLz+dvT+aJJP3Jx//fnzkf17+fjm9OD78MPna",
+	"HjSZfN8RtOQ7qcjYFSt22Hct6Y6mV7gNswqWvYNzOzRHFgFNnE/jguvAXfMuSyb0isWQEmXI6Te2UqPv",
+	"ywtAzBOQF9vDwNquxA4821HfeLnjbrQdJKBMugtgxBZIlh8sqNpZ0mKV/FPw4oBnfwsEZdigdEElTTWT",
+	"05xds3yqVgWccweKLhdMLyyd898Q/EY5RsR2TRb0mpEZYwWZM50uWEbmUiy9tExL3kWGkevunfGU6uik",
+	"i1EzvmHS8usDM/YXbUY129F8yWLEKhXLJS0yQ8nbGJ9MUsmoNhs8rr+M8nw1pWUc8rLZdLMeHxO+kqLK",
+	"838zKZJCaPg/ydicVrk+SCspQVSCKShNl2WiUloAcTU6h9nUMLM/+Kxxsux7aajbbQxmR9KhOYAfzQ3I",
+	"xo/SqDRywx5sRJfwyw1pGc+6zewuW/bz5GjcApPyGy4R0Ge6FBmf803ge0m/T1so3UXk3/jVgiltsJfQ",
+	"pSiuQvz9SdUYrnYJilDfCnFTwFUWpxdwqymmu+zyeEK0FIVeDOCmu447Sw7ki3XMe/NIPismT46gCyPH",
+	"RIfFV1bKHC+33VzfTJ1SbR1kXF5fvoOmYyGk0fedHUzS4ltkAZ2RzqDd6GGwVzeEZnTZXE7vKKDn22Q5",
+	"ruu7NgOEwprVPtArNrHnMakpf3hFJIE06affnHzAXUT5qirj+jB1SgvHWM2QPIGK2BBa+yPncwR5tiyF",
+	"pHI1tWyQV4MblJBsLplaTAKVYVnBCyeSmrUEDzKWM3zg9Ha+hXvg5+EeSKGbDzKuQDCcBOrQnBdB3451",
+	"qR+YSfvfuJlLJq/8DyapqluqqWSsSOWq1MGzjJknUT4TNnTnmko4UgU7G2z4r7jJzQef3TKbj9+bjQ8e",
+	"XrgzOHRHELw0XP45a31z5s8leHhqj+i0arW+NGfz1p1E99WR27jg1bk5jdhX9lVsifbVmdB9r4786YZz",
+	"twf9nhfRyVj2PfrKyJ2RN0AlP1ggaD0+tuDQ6UedBYDRfXvkQMRh3HGhYwLpIVlWmqJsmNI8J0uaodqS",
+	"BsruthziUHeITodYDvLGPKo6BGrutYKayiumA+0ETChB2a54XDEDOjkAzcvMGK1QPz1udqbtk07vXhzz",
+	"ozOV97n9N2DIElppwYtUsiUrdMzuNTiSQ0vDa8SMU0Psgfu65laGDUOWQBj9DGJMDQ9CggCGavDVDs2W",
+	"vEBtEbxUpi1qw+ED89berSTnBSNaiDzGcwYWopauxIClBVJEYZwVV6QQmlBUnCSE7V7tEkpmtCA8S4i7",
+	"JIntNyH2WkRjkHS4T6wt6/H5wRgPEsB50ramWaIT4yfgTospS0YZ52HTcFMUoVpTFKS1QP2ae56KkuNT",
+	"wHOjhwoFca7g5L6ZFjnVsNtwnErTlarPGr5ThBt9sigsHUHOhWbA/jv6MASn9nIxIGr3ajqLUPXPVcdo",
+	"6cBCVTBVs7y1w4OoJkVVRvfxV1oo36G4Qm0Lwr/dMa+QUGgKo0VC1IJKRijBTnfJ2wUtruDqEZIAo2c0",
+	"lLDvqCJnqO3jWpGbhciZ+WqSdEGuZxFxEHwHvZwcPSrF9sR0iFRuNMvHnmCUwsKee0VU9GJZCx62i42A",
+	"0HyzCRBKRq2dpPOqKjTPxwvvVouz4YRBz5YCsG427ccmk7XoZVbtN6aHLlq+t0MdH2s77yLDAk4XLP/A",
+	"NM2iJpZBJcN4hbQdx7DOdqyYNbej63cDfe2ffKPTzgIyRuesiOrXetRUoHqpdN8XvRuitGR0CT8i3w1I",
+	"8WaspJ5o2FV02aKY86tKek+C5oJRn4jvkCeMK3HaEvp01FdG9QMXqLm2s4wbGnfamMEQIFxCF++xh45Z",
+	"9D1XyBvhMOaiVpPOBrS2sj3xEWtrLiS2xWBuPP5eCqmjbjhypRdw+zm/kpmoHPMWEcAWnF0jFR+PLmZs",
+	"lh3WH0cNRiC2TVmhJWfjOw8EzEifzA49dM90YH/BYSs423yFv+GX0ZmgomV8j7VYHumrTRNi8kHdxh1s",
+	"2xMGDvgn1fQyIjxTzrOo9v0aNWOnWYlNGLoes2Joey2A91aa6nscwO/wMcrOawlyCBp2huHRJ01Yb82r",
+	"cQb+cNswHEPG43LBlkzS/FApkfIesmdxXVsL8CAC1Lbie7AkD+QSxuv/vQ6+ZXR0HhNCksvrS6IZXcJP",
+	"b3fnijgSyHPwKTa7ZuG5xxWvs+mSqkqyMxYngpcLaoQjVICuJYEoCEVQ73AJL9AX98aLeXWnOdhhnFBm",
+	"/AfATVolxEMdSm8tuOteXDNaKKOr7TFqYINvrNRDc4RGBBqRGUtppTxdYGazoAEpRc7TlZdJgZaAywaI",
+	"SOj5EJCTGbAvWXPptYBKrygvoqtpqK8Hd9W1JOjJzJG22W2Idbw9Drg15aSGiebhhCcRpQXoUtXledzj",
+	"LldG52zHem3u9DVrTds0i34bnVPksu6eCZEsFTJjDWgl1zSvEB5oCAZ9GDRCOdZQOtDmjLoHvmU1oSEt",
+	"XjE5msbirvQ4T3bY6EZkRj1Yc/Wuz6Hzc6zI0NkZsXJrB7bN8wBMdGdSGD/ZzsaLPC4P3eMM3UU8jGq1",
+	"wrL3IIeOLGBehk4NGRE8u4IZ32V7hA1OrnuIff5YtqcRp2tFVHO6w6LogMz5nLr1+6Bv4FUeOApE5Mlt",
+	"2+lb0BZSh2Y0TSTyJTjkqJAe2NONIT627Bjwvrt8c3h68muVXbE+zop692DrDUy4NUfMhdwlJ4Vmkqaa",
+	"XzPXwHjiYxPUVy9oPjcw7gI5jGkBvFPqb2YrMqPpN1DVghJ8VaQLKQr+bxd94Mz9vB5wkkyg3UhrdrjW",
+	"k0Yn4Ztz7LC1NZ+Vxb8mUs78vg2x+I09Bv8oyvNKxqS/M7cXqDs0zBq0Zpm1bYKRRjJtRZy13vDJJPQn",
+	"j41nPeWJZAARdaSfO3IwQLHvKWMZHBvXoYP4yBnYNQ2sVrFCg74+GDghvEjzCkfdbMHMKyCG9hadIE1b",
+	"t7czhoP55TGUamD7DUSNGPzGOquDP3zE4CY0zQngfQ306D4Kn7HMu4YHU5DhuJmojFOBHdi44HeuMQuU",
+	"wc63wKDepAAWW5PvpxUeFbqmMaQRfvKkgpZGvuCK8EJpWsS8Aq4pz8FdYoou/GuPDlzXZ5a8SH610KQQ",
+	"NyiZgdhnKcyYfUvsVo3XU3RJQkRbYuLP+v1zzbYoTU1kLUIdMhssa8x6UHkeHrgLeOtsZL3A2HF+oDpd",
+	"VGUsKosRxTPLloBYv7RN20eXijwmw2zEkrMi81resV6UOl2M4B9wgZtKCHgum0xoo94DL70WdATKE2dH",
+	"4hnbJfuE1z+dbR6EQLR4KkLxs3V8eeDyt4G+JybO53moEg/st3DVu3mGSseIvn9Ir+cPN7HQ1TiSAFz8",
+	"HGOwHfpaRcwwrbjObhDOUNw0xG23IjxrdRcgNGiKwP687lAC7ngjIHogH73ZWGE4Ejr5tEyWgWTw2Bqb",
+	"CB/cYpTt5Oqho7DgNNvj7YmD538anrrVsqMXiAu+Qs8uxTS5cSTfO82AltLo8ze16jdFqcCpZ63CYih6",
+	"aUgVcWYa96iQTNCdrNu49Wc8Qxq1ZKyrimh0Epl7PKrrYxBhLaucuVBDo2a3Ws5x4VpNM19s2eepKCNz",
+	"OGUSQte5KMiVpIW2fj3O2r9rXbLwnSIM7HJEYVe15GLksAM4d++Se3AjuQ6SD6De+ACIs9E/+vfekuif",
+	"QHdcaZ4q/6je7QNxU9Q+y+4nGjjcBErJr2m6av/0bsSwoJGiFW6acbA/M73hE+NRemknh48CqFLnZpX4",
+	"/FdaNNp5l+HG03O/5Mbj2uT76aZwD63fb/gIrY3hBE/NmiOPnPes+c5sBUCHOe6YhicVhaqWdfz/4elJ",
+	"l9c17u0RjyhGJZM2iLXpSGJjPCTTlSxYVlMV+x49zYxHFwgsIKNhYKMixus8Swg6juXomktzaLegauGG",
+	"sY3XkaW4whAPeJPgoBEuqaMm8CzhUdZrPx6Ad8GXrHEuQOzdF7vE+oRn7rUCQQYdLW38sgeZ+7lT9fuC",
+	"AASPl3EM/dtmqGZSc37OSXYTmIPMOY/t4dZVz/UG9zZjBnp5iiX9/p4VV3oxOXjzl78mkyUv3O+fk+c9",
+	"pE2X2ucL9uCltqX8MqcpM5hgGkWy59x/d6Km5XOvEXaX9OHbt8fn59Oj448nx0fTzx///vHT5cdJ4p6/",
+	"Ozv8eHF8NG1Ec7fetYK7e7+cXhx/OP10dnj2/4f7iLWz8zt8+/bT548X04+fLuwnnSbH/+/05Czy/OTj",
+	"74fvTzrTtG9/Pfz4MfLR2fH//Xxydvzh2I754fiiO3cfzd77Zmjl0Obw/fuTw49vj7tv330+eX/0oDD5",
+	"Oh59je93Nzz5NQB52zdsz85jvK2qfB6Dx1vF0M1Zep5/k4Dje1LhRjBlaH0MZxGj0p+td1a/n8s4Tzzz",
+	"wdMnaXCyu00COH7Gvxq1w/PN9pUebJ0eMOdvN6W1w90GjoUxd71nhRmTVm84Aye2cUoO8yMXV0kj/c8N",
+	"VXWuM9ADFy7r3ltjH8GUctYX1GTxQwOTZE55bDPx+Vx6D4s6wYSAJ0ebXMTjVYX3DFJBv85sygsthrbb",
+	"BmZxVacKwoQu5nMCn1sZXDHt1NzmWJjJMIc7S11HVEf7ILQQqCg3xu+bBU8XJKUSPQcb6mT0S0MnP6EX",
+	"2Py+5zPyJLpOfON8hkMt+zNiVfcm7bsnjU92RPRIhcyMvWuDQ1zjadVNTRV4aVkzNPV2FIx5qw/cYzsO",
+	"jg0BwJdU83SyQaxf/VF3RrJiIU1RxipuV+vcSn0YXjj5oXnPaa5Yd/bWGh16e26UW8ZdGH/qwOYNgov9",
+	"ftfnkIw6lUBSF5WEwSyDSfP803xy8I8xgQBf2/E05wUt1UL4eGPTt8E2GxlvQ1wN/G0zLD5YVxRVP3sS",
+	"UM8HfbslW4rrppl+1KGf44CBHxuGXq+dQHOXgrngjbbpJEy89/gw6uYmdSYdkpZBcmu9N7oiyuhdcJEE",
+	"W1t9ayax5fweONTVSqOWOxgtRsos9+cCn5u/a3MLm/IIm+Zqqool01PZtEUOfRqaLdvnPOAIaa1F3UN9",
+	"MSHZJV3lgmZrgx7NQk9t63XWbuR7bVZ8C0sIZ4ZhsMyw62GymRGbpTLmdXqOzw2Aa0EUvyrcDLhLCf3b",
+	"h8O3O+e/Hb75y193yTtWMIn2Lz43/jE2fysSbRG3nvm0FXAJXnGlmYzcghsw0e27+VHMWIABMpIk7kNl",
+	"soaThdYlmP3grzL5I5bwDrZBMiXya2YM1bkQJfjXJsi47eQipTl8iGZfzQjNMmlyMw0aX87eT9aFg5VM",
+	"qpIZj2DY6UZib4OCJjvGsqy05SAhwbDBU4SuiPsysfCt4kFjjVAfmU9qfBjA9iNb7SFizrXitHU5xFS7",
+	"sKUz5kDROQBYOOrKAlqzZal7fKBmIuvJFWynXfMffrxO+PF9eJ/w5tnsBg1uAOOTPD4q9zGJX82k/Pjs",
+	"Oqqt++PB8LVNkQ+d9wQmNHKnbzZ9C+d9DF/QtQXJpIbbAbw5ra+aVt0L9AAyAKsIMnDmzkiiKO1bdrmr",
+	"mm9xhjkrcQ9HNtSbGwT3R3T3Q+6ImG2c69U53JiWs2NUMgkFESKkwtbLQe2B99k4pumCAD0wZC/nSgeW",
+	"zYafQMGYSZ/O0RfeDB66Vu1+KS580ifzfdfPaJd8cqPhvbx3/fOeHUHt3YaX0x2GZzgfd+t3bxv8pAik",
+	"EoVmCamKnClMPbSwjGYwhS+FKy2AojxuUA2OcB0ZzQy3ljbNdQ5vMJsmuaRSkTeNc7feDwAPZmOv38Dx",
+	"iJIVtOSTg8l/7u7v7iNh1ws8FVgizmjPZM/fq5xvejSa5R3TZCFuyLJKvfNLxG3d+EwTHo1rKbKBIBXj",
+	"zV816pt4v3fn8T3BJZmDOsnMtNC/KHSvbxUxeLO//2iFAMJhIuUAYMttmv1YL35ae93M/HfJ5C/7+2O/",
+	"DEoyhEiH+oUQ3f7hHNFAp2CIbv3oLgmAwEF7LwBgTg8IoXEt64CCjhNO/IzO3RAPPKBxbgxmsAiNeuGn",
+	"lkxKoSLnY3xqbKUBT0kvgppkrRRLXLW4e5SWkaM30+4c5KlQjZOsw2R+tRzZoyBZ00mopaG2fH0LgH5+",
+	"7MH74eRep/3iyULrEgyoxCCub5MWrz2p+274L/u/9KuHQY6Zw/31TLgf1ELs0SXXTfbCM0O1cgkBIjET",
+	"Dtbv8BwYcbqwlBm+yniddegB9PZ0BMG60o0iCPt/YILw48LnGNKx57J3H9w+GJqjF6H1FSZ01C2otCgV",
+	"RGV9wxjd5ZJlnGqWr9befnWe6lcS98QgZDPCbwuCrDNto6CrmLcBqpTsmovKBAz0ghFKPdARsGU2WuCB",
+	"bJfPnf4Kd1uCO9TP791ak9bd3tJ5PmwGbt7JohfS0MYXmOnaThK75LTh15L4/FKJyUkF0OXdvEjo5mXS",
+	"SIjrWkthU6kbDxoTtm/8aEIzYW2l7QHCMGn+Ni77junzie/7en099f6k93fx3gABI7DdeoNbwD5Y74+O",
+	"empQLeWPQTVL1qH7UUmlxrDSgt342ri9Kgp/+E+jpBjIdPkHUFPck1Y6cKgyrtdoo6AJsRknASEbNTXU",
+	"iDN3CVRNPo/WjCO2XJNXhYVlBWzOl1aN6na53GZa/YHav/FqtLXfyEalY+OdNePAhwrcrvk+NquRC7KF",
+	"BZKRBLFR1qSvT5dXp+7SR93/vL+PBcT4EswhP+/jT17Yn+O3TsznivWMEXa5H+ny61NQlKGUwC+DpNRB",
+	"1kOXhYt1GCAOYGJgVKYL5JTWEwOoq7AREYBujRIcqysYa8f/0rJi/4GxxIV7hE6d/9FDGupkXG0cCnKO",
+	"PzJVWLco9BKhxOSrh9hsTXnBi6ugirhm33XPisxn96EKrxgcD8x5GagbZmj4etdA1b1bKCpnFce2ClsE",
+	"befa1CHZJZibHf4NUuz+pEwpEpNad26C86107V84sTrI2wvYicl+eVG7a/+EnS9sptA2OTDVwn615Sb+",
+	"EEceZf5/pcUT8P51Jo6vyN71cvLUVviI0eZt6jvwAP5AG97GxE2ZcYOsQxaDtyZ9LqBTXZSBuBx85t4Q",
+	"87HYbGu8DKBz3PbgwOLxtRB1FZctqB8enyi8TNXDc1AfeynZnLBq7zaQhu72bu3zu706P1BE74eMBXjS",
+	"1HyF/XDShpU1XNAaRAwmNxnR3GyNqYlyzdnNgObxrchzlmpSr5PMMFe/8Fm3jBdw3b1hqalxTF2iq6m5",
+	"Pzl4w5aVLIWKWQmF0jZ7slNm1pmItoS+7WpI45H4RWLX1i6RRoasEH3alYsGrvRG21CYkCxn1xTDNIzU",
+	"Au8BqmRVFKYmnbPSzURUZPv952YJpS2yCM2BHm4MNkgqJGeFxoPcfwgIbJcdaJOVqfNArrV1prL83q2r",
+	"3s0KfbfnK4vFoUPSOvGdT9BmEmQCbOBO75JDX5sqXyVImr5gOrepLbf/ZRJkCISczzPmjBh1KURX7ycs",
+	"XNOBJnSu/GwDhrdqX4jBT25rZhkXXZa50pWGHOO+PiuI/aCsagBwNTTalLvrzQZW/oN9r91RWeZy9qpd",
+	"coKZ1xEmufK3YmJsuC5lq8+15L7DH+YriEKwRgiURHs52ndMf3DzfgoG0w62IZP5CnjrieKwhi2AEaoJ",
+	"KzKfRD3QrplAkKgpwSasjqj8hrJeO9RwHGWL8TVmN5PaZECxCmWcSpHn5vpWOH2MQjH3NqbUcCUKiO3N",
+	"iX+pKAqGKnzbkDkXfNwR+AzDFhKiBPHB87BaF6KphUmOypWLcCAzpm/QzCfyPErUw/y9n+3ynoG6PzMG",
+	"/bL/v9ecJoCNqHRCCuH21qdEJ1klzYmbEhYlk1w8mfaitgHYAqIbo+qDpaix35j4MAOtk41Qbs9UCu3F",
+	"PPCRYXLnnBWaHJvjMV/AtdLCNgiesfHPtjwEpjr/gvv3ZWLf+RLZtqmPhz4/PyY82/1SfAhjoO3dBfHR",
+	"jRrTiJ9+I5wG6D1VegfnuXNyRBaMZuDL8hujUs8Ytb25qcGAUI0dhvlSjETic7Nfa1FZs+96D5ewU29x",
+	"v0jeQdxzv8tBQNaPf509E9LcAwFu2+bkQRvB5wL8oJoZfxD8ESK5VtZt6sTwY9j4hqqmEICJz827Vgy2",
+	"ETBEngGZk2xpJdVYUzJjqVgy358xOOBcvjFWKpyMrwZoYp8K1WNpCAF8m9dTM6FAn3+TEZ5ybPPoSr+L",
+	"ztHZugx1OX9X8H97RuYwe/ham0Rjtj8pkjFN+XpuY3I/jrKJDo1qCw82QL5AzuWH5P03pI1tCndvyrjn",
+	"6MnebZ0ta5BeniEyN2scIjEC6mR9m1V9nbdB3RBT8IwgXKmq9iX1XVnb6gi6dugrLLZg+Jc+vetz0Z3E",
+	"sTFulZlgymYwwEq2dhOemkY9MZCu/6YGwgeANK5zc+fqJ1jekN4zmZRVlE+P3RgW0bqmkapxY/iCAIM+",
+	"SFD4waQGoZKDnGx2MIx5t7qFJRb4mmHJD4xo8IlClLXTtqpm+KIbUcUD5J4IPxn2Wfq6HcuO2aOjRzLq",
+	"PIss/ua/xn5pIvOnYQG6KXz+ZDTnQTi953JDb8BbWViGLwFEV1bsNGcMdLcy6RHDdpIpLbnRLHFFGFiT",
+	"0riCtYttH+lyq6E0wSivzNVLIOwPAnlgzPCfsXxZ4QPMkAcLasp3MMOyfkbOtL+CiB3QpNywPAfssMkg",
+	"gcfLqdJuDKxlw03OhkIQYGUwgaS3OY3h4twd9WKYOLfBr0xc/BsPsPcHfpu78amFqB4eDNyKqEObQO6x",
+	"co4pGupAfpccomMa9xIOVu2ldZabWEcgQc1Fnosb1RAUDO5x1YDHRs7jQQbQ+LMNcn+HuRI4YWM6aSZc",
+	"hmcm7KkePyApkZUkhCIyGI/vPLdNuazRDG2r3BrNox7seT5tNIq4VqMLfPJkXKJ36HzcxCGP6bgHpMxC",
+	"HCoGX7Af34+g0ot6+d2Djg37DACGmGgSG9shMHYyCbQjMcqzjhW1oSd/ZE/zHwFGXqzaz1SVfJ4bNuo9",
+	"igUezRWk0XJvCiASOhOVDutCRA7dH3hYph5aZlTT1lVlrs7IVbRLfhcmfYd3X4U7uRkRimFZhShWS3Dq",
+	"2SWXC1NsGa5PE8UdBocokrEUXV5tSKdzXGQZgQOoJPqt1xVTu26uIU67GphbkyyPzZzOWCmk/hNhcbPU",
+	"6gOoPfuOO9dH74/x9XMC+bprw8xwmzAGerbj739SAHt594Rkc8nU4ke6Kf6PkCmQYmLnFhT4NbhgSkPo",
+	"ShbK58nBgzdvvWOc/8CndrA9AmX/KDQzWXRAt6HpN0YomWMixFQUmVpLrc/szj2bgfXHCsLu0QP35WNq",
+	"Ju8e5J7th2tcsW1KoNOw0fa5Y1/s/o8Td7UlAtkolH4fEtnKxDUavvZubfX+uzWGBdvsJzUO0s5N8x8e",
+	"4B4/9Y9Xiy5sZFdQ/KgM5vcyIGn93WUBY50JdxT8nFZD8DOox/N5vWDH7WDA69h4GDH39aYaL1u8UbZL",
+	"QAxwzGXzrTEM2PJ47iSTh6WE+fpKh39oOnwPQrp364ADvcw2Jqyr0WR19UpU/+hEdX3TBrBtTIVXo2nw",
+	"6k9LgdHlvcwpLzb0dX+lpZvQUlv/Zb3I4xoGpbK6WeP6KOilG+UpSKcd7IXmH3L7/FhCSX96YHOMdf0q",
+	"W28Koo9taJuPmlG1G5OtdxzVRTRPe0tZEfzxPm1JhcawsXgDjxV2O9s6UzVY0+7PTLU6ID+SZu3d1tWr",
+	"1sTbyC60By4DXCuSMZqRnGltIudjHkQR8H4G7yE7/BPknHlkUrSeq6qPc702JQoEe3CKO+4Uh660GvPW",
+	"1fcLK0UaRS5FCunyra65844Yzd57sHqy28/XNXwe+eEVSEcC6d6tBTZ0ttyTTMvVQ0sCbLaG9a2DGQ5V",
+	"rDCtCA1JKaFXlBfG2zN8HHh8WvHEo1iaslIrwvUIFqNGrjPcuDGO8xedwWyB9CxAmUdGh6Ng6S2UeLP9",
+	"Kz9cccYNlTMrb637Ca7pvRLEticF8F6IPWfgbIGcGMtI6XM3tKh+gkwCWMv7iH4XME9NzdMHQGNpi1g+",
+	"DWF+figsbTa5+0BgWBB00Nzcn5mgoKVaCO0Mu7GCxWIeuqvWpvxm6LWQmZONO0qWL8UJ7HBm/XMlS0WR",
+	"8tx4ZVDNiBlM6qrcJacUU1NKUV2ZFAfKzbEqNM/BMGy8ZgtbtdJMk6lGgp54KoKw6GZoQI4o/DfMcPyX",
+	"P0uG42692tccRD9KppNeiu8yZvWhd+Awb1IYNVBYBYVs8Le/GKy2FRBPDOhHhtDuvqqSdlnjuvzxkn4/",
+	"MS8NGnZh8xkSwr6izWi02fRye7zEVw30+CESYPVhzhMkw4pB7GtqrNfUWOO/MuudznmuWZBQaGPEflB6",
+	"rdiVNyLbVqTW/otMvrWGgDx9Iq4uWWmk5XqNpv4zIXg8fdiA/wp+FsPpTWD/3H3x5747X/MHbIolg9m9",
+	"HgH4nzEE4FHFzXuFDcQu6m0GEPRRh0cKJniVCh8laOFB6OTz0r8ghKp++GT7p1Uv7lz4Dd+OD8yxK5Z8",
+	"WNdK3oZKp6Na7eCg2VJHoYzSjC4xjYSYe7rzB0RUD34hrg5+gqKuQhnNoF4l88nBZKF1qQ72IIPJ7pxK",
+	"teDXTJaMflO7qVjChfrfAwBZNmfFS/QAAA==",
 }
 
 // GetSwagger returns the content of the embedded swagger specification file
@@ -17,10 +17,15 @@ const (
 	ACCESS_GRANTED_HOME_WORLD_TEMPORARY   = ACCESSGRANTEDHOMEWORLDTEMPORARY
 	ACCESS_GRANTED_LINKED_WORLD           = ACCESSGRANTEDLINKEDWORLD
 	ACCESS_GRANTED_LINKED_WORLD_TEMPORARY = ACCESSGRANTEDLINKEDWORLDTEMPORARY
+	ACCESS_GRANTED_WVW_TEAM               = ACCESSGRANTEDWVWTEAM
+	ACCESS_GRANTED_WVW_TEAM_TEMPORARY     = ACCESSGRANTEDWVWTEAMTEMPORARY
+	ACCESS_GRANTED_WVW_ALLIANCE           = ACCESSGRANTEDWVWALLIANCE
+	ACCESS_GRANTED_GUILD                  = ACCESSGRANTEDGUILD
 )
 const (
 	HOME_WORLD   = HOMEWORLD
 	LINKED_WORLD = LINKEDWORLD
+	WVW_TEAM     = WVWTEAM
 )
 
 func (s *VerificationStatus) WithStatus(status Status) *VerificationStatus {
@@ -57,6 +62,14 @@ func (s Status) ID() int {
 		return 8
 	case ACCESS_DENIED_REQUIREMENT_NOT_MET:
 		return 9
+	case ACCESS_GRANTED_WVW_TEAM:
+		return 10
+	case ACCESS_GRANTED_WVW_TEAM_TEMPORARY:
+		return 11
+	case ACCESS_GRANTED_WVW_ALLIANCE:
+		return 12
+	case ACCESS_GRANTED_GUILD:
+		return 13
 	default:
 		return -1
 	}
@@ -68,20 +81,29 @@ func (s Status) Priority() int {
 		return 100
 	case ACCESSGRANTEDHOMEWORLD:
 		return 90
+	case ACCESSGRANTEDWVWALLIANCE:
+		return 87
+	case ACCESSGRANTEDWVWTEAM:
+		return 85
 	case ACCESSGRANTEDLINKEDWORLD:
 		return 80
+	case ACCESSGRANTEDGUILD:
+		return 75
 	case ACCESSGRANTEDHOMEWORLDTEMPORARY:
 		return 70
+	case ACCESSGRANTEDWVWTEAMTEMPORARY:
+		return 65
 	case ACCESSGRANTEDLINKEDWORLDTEMPORARY:
 		return 60
+	case ACCESSDENIEDREQUIREMENTNOTMET:
+		// An account that would be granted access is closer to access than any other denial, and the user has to know what is missing
+		return 55
 	case ACCESSDENIEDINVALIDWORLD:
 		return 50
 	case ACCESSDENIEDEXPIRED:
 		return 40
 	case ACCESSDENIEDACCOUNTNOTLINKED:
 		return 30
-	case ACCESSDENIEDREQUIREMENTNOTMET:
-		return 20
 	case ACCESSDENIEDUNKNOWN:
 		return 10
 	default:
@@ -9,14 +9,84 @@ import (
 )
 
 type Configuration struct {
+	// DeleteDataAfter is the max age of api keys and accounts if no retention policy file is configured
 	DeleteDataAfter               *time.Duration `mapstructure:"DELETE_DATA_AFTER"`
 	ExpirationTime                int            `mapstructure:"EXPIRATION_TIME"`
 	TemporaryAccessExpirationTime int            `mapstructure:"TEMPORARY_ACCESS_EXPIRATION_TIME"`
 	SkipRestrictions              bool           `mapstructure:"SKIP_RESTRICTIONS"`
 	Debug                         bool           `mapstructure:"DEBUG"`
 	CollectStatisticsAfter        time.Time      `mapstructure:"COLLECT_STATISTICS_AFTER"`
-	SyncInterval                  time.Duration  `mapstructure:"SYNC_INTERVAL"`
-	MaxConcurrentSyncs            int32          `mapstructure:"MAX_CONCURRENT_SYNCS"`
+	// SyncInterval is the time between starting two api key synchronizations. It is shortened if needed to synchronize every api key within the refresh period
+	SyncInterval       time.Duration `mapstructure:"SYNC_INTERVAL"`
+	MaxConcurrentSyncs int32         `mapstructure:"MAX_CONCURRENT_SYNCS"`
+	// SyncRefreshPeriod is how often every api key is synchronized. Defaults to a quarter of the expiration time
+	SyncRefreshPeriod time.Duration `mapstructure:"SYNC_REFRESH_PERIOD"`
+	// SyncActiveRefreshPeriod is how often the api keys of users active in voice within SyncActivityWindow are synchronized
+	SyncActiveRefreshPeriod time.Duration `mapstructure:"SYNC_ACTIVE_REFRESH_PERIOD"`
+	SyncActivityWindow      time.Duration `mapstructure:"SYNC_ACTIVITY_WINDOW"`
+	// SyncFailureBackoff is how long a failing api key is skipped after its first failure. It doubles with every consecutive failure
+	SyncFailureBackoff time.Duration `mapstructure:"SYNC_FAILURE_BACKOFF"`
+	// SyncLeaseDuration is how long an instance has claimed the api keys it is about to synchronize, before other instances may claim them
+	SyncLeaseDuration time.Duration `mapstructure:"SYNC_LEASE_DURATION"`
+	// ExpiryMaxWait is the longest time between checks for expired temporary access, bans and accounts
+	ExpiryMaxWait time.Duration `mapstructure:"EXPIRY_MAX_WAIT"`
+	// WvWAlliances is a comma separated list of "<world perspective>:<alliance guild id>" pairs
+	// Accounts that have selected one of the alliances as their WvW guild are granted access
+	WvWAlliances []string `mapstructure:"WVW_ALLIANCES"`
+	// GuildAccess is a comma separated list of "<world perspective>:<guild id>" pairs
+	// Accounts that are members of one of the guilds are granted access
+	GuildAccess []string `mapstructure:"GUILD_ACCESS"`
+	// ServiceGuildAccess is a comma separated list of "<service uuid>:<guild id>" pairs
+	// Accounts that are members of one of the guilds are granted access when the service checks their status, regardless of world perspective
+	ServiceGuildAccess []string `mapstructure:"SERVICE_GUILD_ACCESS"`
+	// AccessPolicyFile is the path to a json file containing the access policy rules accounts have to meet
+	AccessPolicyFile string `mapstructure:"ACCESS_POLICY_FILE"`
+	// MergeUsersOnSharedAccount merges the users if a gw2 account is linked from another user, instead of moving the account to the new user.
+	// A merge hands every platform link of the previous user to whoever submitted the api key, so anyone with a shared or leaked key gains them.
+	// To limit this, users holding other accounts are never merged automatically, and the account is moved instead
+	MergeUsersOnSharedAccount bool `mapstructure:"MERGE_USERS_ON_SHARED_ACCOUNT"`
+
+	// ErasureBanPolicy decides which bans are kept when a user is erased. One of delete, keep_active or keep_all
+	ErasureBanPolicy string `mapstructure:"ERASURE_BAN_POLICY"`
+
+	// GW2 API
+	// GW2APIRateLimit is the amount of requests per second this instance makes to the gw2 api, shared by every caller
+	GW2APIRateLimit float64 `mapstructure:"GW2_API_RATE_LIMIT"`
+	GW2APIBurst     int     `mapstructure:"GW2_API_BURST"`
+	// GW2APIInteractiveReserve is the part of the burst only interactive requests may use, so background synchronization cannot starve them
+	GW2APIInteractiveReserve int `mapstructure:"GW2_API_INTERACTIVE_RESERVE"`
+	// GW2APIMaxRetries is how many times a rate limited or failed request is retried
+	GW2APIMaxRetries   int           `mapstructure:"GW2_API_MAX_RETRIES"`
+	GW2APIRetryBackoff time.Duration `mapstructure:"GW2_API_RETRY_BACKOFF"`
+
+	// Retention
+	// RetentionInterval is how often the retention policy is enforced. Retention is disabled if zero
+	RetentionInterval time.Duration `mapstructure:"RETENTION_INTERVAL"`
+	// RetentionPolicyFile is the path to a json file containing the retention policy of each table
+	RetentionPolicyFile string `mapstructure:"RETENTION_POLICY_FILE"`
+	// RetentionDryRun only reports what the retention policy would remove
+	RetentionDryRun bool `mapstructure:"RETENTION_DRY_RUN"`
+
+	// Webhooks
+	WebhookMaxAttempts  int           `mapstructure:"WEBHOOK_MAX_ATTEMPTS"`
+	WebhookRetryBackoff time.Duration `mapstructure:"WEBHOOK_RETRY_BACKOFF"`
+	WebhookTimeout      time.Duration `mapstructure:"WEBHOOK_TIMEOUT"`
+	// WebhookWorkers is the number of deliveries made concurrently
+	WebhookWorkers int `mapstructure:"WEBHOOK_WORKERS"`
+	// WebhookQueueSize is the amount of events queued for delivery before events are dropped
+	WebhookQueueSize int `mapstructure:"WEBHOOK_QUEUE_SIZE"`
+	// WebhookAllowPrivateNetworks allows webhooks to be delivered to loopback, link-local and private addresses
+	WebhookAllowPrivateNetworks bool `mapstructure:"WEBHOOK_ALLOW_PRIVATE_NETWORKS"`
+
+	// StreamHeartbeatInterval is how often heartbeats are sent on event streams
+	StreamHeartbeatInterval time.Duration `mapstructure:"STREAM_HEARTBEAT_INTERVAL"`
+
+	// Encryption
+	// MasterKey is the base64 encoded 32 byte key gw2 api keys and webhook secrets are encrypted with at rest. MasterKeyFile takes precedence if set
+	MasterKey     string `mapstructure:"MASTER_KEY"`
+	MasterKeyFile string `mapstructure:"MASTER_KEY_FILE"`
+	// PreviousMasterKeys are base64 encoded master keys that are only used for decrypting, until the secrets have been re-encrypted
+	PreviousMasterKeys []string `mapstructure:"PREVIOUS_MASTER_KEYS"`
 
 	// DB
 	PostgresHost     string `mapstructure:"POSTGRES_HOST"`
@@ -36,8 +106,26 @@ func Config() *Configuration {
 		v.AutomaticEnv()
 
 		conf := Configuration{
-			MaxConcurrentSyncs: 5,
-			SyncInterval:       time.Second,
+			MaxConcurrentSyncs:       5,
+			SyncInterval:             time.Second,
+			SyncActiveRefreshPeriod:  10 * time.Minute,
+			SyncActivityWindow:       15 * time.Minute,
+			SyncFailureBackoff:       time.Minute,
+			SyncLeaseDuration:        5 * time.Minute,
+			ExpiryMaxWait:            time.Minute,
+			ErasureBanPolicy:         "keep_active",
+			RetentionInterval:        time.Hour,
+			GW2APIRateLimit:          5,
+			GW2APIBurst:              50,
+			GW2APIInteractiveReserve: 10,
+			GW2APIMaxRetries:         3,
+			GW2APIRetryBackoff:       time.Second,
+			WebhookMaxAttempts:       5,
+			WebhookRetryBackoff:      2 * time.Second,
+			WebhookTimeout:           10 * time.Second,
+			WebhookWorkers:           4,
+			WebhookQueueSize:         1000,
+			StreamHeartbeatInterval:  15 * time.Second,
 		}
 
 		err := v.Unmarshal(&conf)
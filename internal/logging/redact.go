@@ -0,0 +1,99 @@
+package logging
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"go.uber.org/zap/zapcore"
+)
+
+// Redacted replaces values that must never reach the logs
+const Redacted = "[REDACTED]"
+
+// apiKeyPattern matches gw2 api keys, i.e. 8-4-4-4-20-4-4-4-12 hexadecimal groups,
+// and gw2 subtokens, which are JWTs of three base64url segments where the header and payload are json objects starting with "eyJ"
+var apiKeyPattern = regexp.MustCompile(`(?i:[0-9a-f]{8}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{20}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{4}-[0-9a-f]{12})` +
+	`|eyJ[0-9A-Za-z_-]+\.eyJ[0-9A-Za-z_-]+\.[0-9A-Za-z_-]+`)
+
+// sensitiveKeys are field keys, in lowercase without separators, whose values are always redacted
+var sensitiveKeys = []string{"apikey", "bearer", "authorization", "secret", "password", "masterkey", "token"}
+
+// RedactAPIKeys replaces every gw2 api key and subtoken in the string
+func RedactAPIKeys(s string) string {
+	return apiKeyPattern.ReplaceAllString(s, Redacted)
+}
+
+// redactingCore is a zapcore.Core that scrubs api keys and other secrets from log entries before they are written
+type redactingCore struct {
+	zapcore.Core
+}
+
+// NewRedactingCore wraps the core, so secrets never reach the logs. Can be used with zap.WrapCore
+func NewRedactingCore(core zapcore.Core) zapcore.Core {
+	return &redactingCore{Core: core}
+}
+
+func (c *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	return &redactingCore{Core: c.Core.With(redactFields(fields))}
+}
+
+func (c *redactingCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if c.Enabled(entry.Level) {
+		return checked.AddCore(entry, c)
+	}
+	return checked
+}
+
+func (c *redactingCore) Write(entry zapcore.Entry, fields []zapcore.Field) error {
+	entry.Message = RedactAPIKeys(entry.Message)
+	return c.Core.Write(entry, redactFields(fields))
+}
+
+func redactFields(fields []zapcore.Field) []zapcore.Field {
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = redactField(field)
+	}
+	return redacted
+}
+
+func redactField(field zapcore.Field) zapcore.Field {
+	if isSensitiveKey(field.Key) {
+		return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redacted}
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = RedactAPIKeys(field.String)
+	case zapcore.ErrorType:
+		if err, ok := field.Interface.(error); ok && apiKeyPattern.MatchString(err.Error()) {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: RedactAPIKeys(err.Error())}
+		}
+	case zapcore.StringerType:
+		if stringer, ok := field.Interface.(fmt.Stringer); ok && apiKeyPattern.MatchString(stringer.String()) {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: RedactAPIKeys(stringer.String())}
+		}
+	case zapcore.ReflectType:
+		// Structs may contain api keys in any field, so they are scrubbed in their encoded form
+		encoded, err := json.Marshal(field.Interface)
+		if err != nil {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: Redacted}
+		} else if apiKeyPattern.Match(encoded) {
+			return zapcore.Field{Key: field.Key, Type: zapcore.StringType, String: RedactAPIKeys(string(encoded))}
+		}
+	}
+	return field
+}
+
+// isSensitiveKey checks if the field key names a secret, e.g. "apikey", "API Key" or "api_key"
+func isSensitiveKey(key string) bool {
+	normalized := strings.NewReplacer("_", "", "-", "", " ", "").Replace(strings.ToLower(key))
+	for _, sensitive := range sensitiveKeys {
+		if strings.Contains(normalized, sensitive) {
+			return true
+		}
+	}
+	return false
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/uptrace/bun"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/secrets"
 )
 
 type Model struct {
@@ -32,8 +33,178 @@ type TokenInfo struct {
 	gw2api.TokenInfo `bun:",extend"`
 
 	LastSuccess time.Time
-	APIKey      string `bun:"api_key"`
+	APIKey      string `bun:"-"`
 	AccountID   string
+	// Failures is the number of consecutive failed synchronizations
+	Failures int
+	// LeasedUntil is set while an instance has claimed the api key for synchronization
+	LeasedUntil *time.Time
+	// PreviousAttempt is when the api key was last attempted before it was claimed. Not persisted
+	PreviousAttempt *time.Time `bun:"-"`
+
+	// PlaintextAPIKey is only persisted if no master key is configured, otherwise the api key is stored envelope encrypted
+	PlaintextAPIKey  *string `bun:"api_key"`
+	APIKeyCiphertext []byte  `bun:"api_key_ciphertext"`
+	APIKeyWrappedKey []byte  `bun:"api_key_wrapped_key"`
+	APIKeyKeyID      *string `bun:"api_key_key_id"`
+}
+
+// BeforeAppendModel encrypts the api key before it is persisted
+func (token *TokenInfo) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	if err := token.Model.BeforeAppendModel(ctx, query); err != nil {
+		return err
+	}
+	switch query.(type) {
+	case *bun.InsertQuery, *bun.UpdateQuery:
+		return token.encryptAPIKey()
+	}
+	return nil
+}
+
+// AfterScanRow decrypts the api key after it has been loaded
+func (token *TokenInfo) AfterScanRow(ctx context.Context) error {
+	if token.APIKeyCiphertext == nil {
+		if token.PlaintextAPIKey != nil {
+			token.APIKey = *token.PlaintextAPIKey
+		}
+		return nil
+	}
+	kr, err := secrets.DefaultKeyring()
+	if err != nil {
+		return err
+	}
+	apiKey, err := kr.Open(token.envelope())
+	if err != nil {
+		return err
+	}
+	token.APIKey = string(apiKey)
+	return nil
+}
+
+// encryptAPIKey seals the api key with the current master key
+// The api key is stored in plaintext if no master key is configured
+func (token *TokenInfo) encryptAPIKey() error {
+	if token.APIKey == "" {
+		return nil
+	}
+	kr, err := secrets.DefaultKeyring()
+	if err == secrets.ErrNoMasterKey {
+		token.PlaintextAPIKey = &token.APIKey
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	envelope, err := kr.Seal([]byte(token.APIKey))
+	if err != nil {
+		return err
+	}
+	token.PlaintextAPIKey = nil
+	token.APIKeyCiphertext = envelope.Ciphertext
+	token.APIKeyWrappedKey = envelope.WrappedKey
+	token.APIKeyKeyID = &envelope.KeyID
+	return nil
+}
+
+func (token *TokenInfo) envelope() *secrets.Envelope {
+	envelope := &secrets.Envelope{
+		Ciphertext: token.APIKeyCiphertext,
+		WrappedKey: token.APIKeyWrappedKey,
+	}
+	if token.APIKeyKeyID != nil {
+		envelope.KeyID = *token.APIKeyKeyID
+	}
+	return envelope
+}
+
+// ReencryptAPIKeys encrypts plaintext api keys and rewraps the data keys of api keys that are not encrypted with the current master key
+// Returns the amount of api keys that were updated
+func ReencryptAPIKeys(idb bun.IDB, kr *secrets.Keyring) (updated int, err error) {
+	ctx := context.Background()
+	for {
+		var tokens []TokenInfo
+		err = idb.NewSelect().
+			Model(&tokens).
+			Where("api_key_key_id IS DISTINCT FROM ?", kr.CurrentKeyID()).
+			Where("api_key IS NOT NULL OR api_key_ciphertext IS NOT NULL").
+			Order("id").
+			Limit(500).
+			Scan(ctx)
+		if err != nil {
+			return updated, errors.WithStack(err)
+		}
+		if len(tokens) == 0 {
+			return updated, nil
+		}
+
+		for i := range tokens {
+			token := &tokens[i]
+			envelope := token.envelope()
+			if envelope.Ciphertext == nil {
+				envelope, err = kr.Seal([]byte(token.APIKey))
+			} else {
+				envelope, err = kr.Rewrap(envelope)
+			}
+			if err != nil {
+				return updated, err
+			}
+			// Updated without the model hooks, so the data key is rewrapped instead of the api key being sealed again
+			_, err = idb.NewUpdate().
+				Table("token_infos").
+				Set("api_key = NULL").
+				Set("api_key_ciphertext = ?", envelope.Ciphertext).
+				Set("api_key_wrapped_key = ?", envelope.WrappedKey).
+				Set("api_key_key_id = ?", envelope.KeyID).
+				Where("id = ?", token.ID).
+				Exec(ctx)
+			if err != nil {
+				return updated, errors.WithStack(err)
+			}
+			updated++
+		}
+	}
+}
+
+// DecryptAPIKeys stores encrypted api keys in plaintext again, e.g. before the encryption migration is rolled back
+// Returns the amount of api keys that were updated
+func DecryptAPIKeys(idb bun.IDB, kr *secrets.Keyring) (updated int, err error) {
+	ctx := context.Background()
+	for {
+		var tokens []TokenInfo
+		err = idb.NewSelect().
+			Model(&tokens).
+			Where("api_key_ciphertext IS NOT NULL").
+			Order("id").
+			Limit(500).
+			Scan(ctx)
+		if err != nil {
+			return updated, errors.WithStack(err)
+		}
+		if len(tokens) == 0 {
+			return updated, nil
+		}
+
+		for i := range tokens {
+			token := &tokens[i]
+			apiKey, err := kr.Open(token.envelope())
+			if err != nil {
+				return updated, err
+			}
+			// Updated without the model hooks, so the api key is not sealed again
+			_, err = idb.NewUpdate().
+				Table("token_infos").
+				Set("api_key = ?", string(apiKey)).
+				Set("api_key_ciphertext = NULL").
+				Set("api_key_wrapped_key = NULL").
+				Set("api_key_key_id = NULL").
+				Where("id = ?", token.ID).
+				Exec(ctx)
+			if err != nil {
+				return updated, errors.WithStack(err)
+			}
+			updated++
+		}
+	}
 }
 
 func (token *TokenInfo) Persist(tx bun.IDB) (err error) {
@@ -60,11 +231,36 @@ func (token *TokenInfo) UpdateLastSuccessfulUpdate() (err error) {
 	_, err = DB().NewUpdate().Model(token).
 		Set("db_updated = ?", time.Now().UTC()).
 		Set("last_success = ?", time.Now().UTC()).
+		Set("failures = 0").
+		Set("leased_until = NULL").
 		Where(`"id" = ?`, token.ID).
 		Exec(ctx)
 	return errors.WithStack(err)
 }
 
+func (token *TokenInfo) UpdateFailedUpdate() (err error) {
+	ctx := context.Background()
+	_, err = DB().NewUpdate().Model(token).
+		Set("failures = failures + 1").
+		Set("leased_until = NULL").
+		Where(`"id" = ?`, token.ID).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// ReleaseLease releases the claim on the api key without counting it as an attempt, so it is claimed again as soon as it is due
+func (token *TokenInfo) ReleaseLease() (err error) {
+	ctx := context.Background()
+	query := DB().NewUpdate().Model(token).
+		Set("leased_until = NULL").
+		Where(`"id" = ?`, token.ID)
+	if token.PreviousAttempt != nil {
+		query.Set("db_updated = ?", *token.PreviousAttempt)
+	}
+	_, err = query.Exec(ctx)
+	return errors.WithStack(err)
+}
+
 func FindNextAccountAPIKeysToUpdate(ignoreOlderThan int) (tokens []TokenInfo, err error) {
 	ctx := context.Background()
 	err = DB().NewSelect().
@@ -138,3 +334,38 @@ type Ban struct {
 	api.Ban       `bun:",extend"`
 	bun.BaseModel `bun:"table:bans,alias:bans"`
 }
+
+// AccountUser records that a gw2 account has been linked to a user at some point
+type AccountUser struct {
+	Model         `bun:",extend"`
+	bun.BaseModel `bun:"table:account_users,alias:account_user"`
+
+	AccountID string `bun:",pk"`
+	UserID    int64  `bun:",pk"`
+}
+
+// RecordAccountUser records that the gw2 account is linked to the user
+func RecordAccountUser(idb bun.IDB, accountID string, userID int64) (err error) {
+	ctx := context.Background()
+	accountUser := AccountUser{
+		AccountID: accountID,
+		UserID:    userID,
+	}
+	_, err = idb.NewInsert().
+		Model(&accountUser).
+		On(`CONFLICT ("account_id", "user_id") DO UPDATE`).
+		Set("db_updated = EXCLUDED.db_updated").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// GetAccountUserIDs returns the ids of every user the gw2 account has ever been linked to
+func GetAccountUserIDs(idb bun.IDB, accountID string) (userIDs []int64, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model((*AccountUser)(nil)).
+		Column("user_id").
+		Where("account_id = ?", accountID).
+		Scan(ctx, &userIDs)
+	return userIDs, errors.WithStack(err)
+}
@@ -0,0 +1,164 @@
+package orm
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/secrets"
+)
+
+// Webhook is a registered webhook along with its signing secret
+type Webhook struct {
+	api.Webhook `bun:",extend"`
+
+	// PlaintextSecret is only persisted if no master key is configured, otherwise the secret is stored envelope encrypted
+	PlaintextSecret  *string `bun:"secret"`
+	SecretCiphertext []byte  `bun:"secret_ciphertext"`
+	SecretWrappedKey []byte  `bun:"secret_wrapped_key"`
+	SecretKeyID      *string `bun:"secret_key_id"`
+}
+
+// BeforeAppendModel encrypts the secret before it is persisted
+func (webhook *Webhook) BeforeAppendModel(ctx context.Context, query bun.Query) error {
+	switch query.(type) {
+	case *bun.InsertQuery, *bun.UpdateQuery:
+		return webhook.encryptSecret()
+	}
+	return nil
+}
+
+// AfterScanRow decrypts the secret after it has been loaded
+func (webhook *Webhook) AfterScanRow(ctx context.Context) error {
+	if webhook.SecretCiphertext == nil {
+		webhook.Secret = webhook.PlaintextSecret
+		return nil
+	}
+	kr, err := secrets.DefaultKeyring()
+	if err != nil {
+		return err
+	}
+	secret, err := kr.Open(webhook.envelope())
+	if err != nil {
+		return err
+	}
+	secretStr := string(secret)
+	webhook.Secret = &secretStr
+	return nil
+}
+
+// encryptSecret seals the secret with the current master key
+// The secret is stored in plaintext if no master key is configured
+func (webhook *Webhook) encryptSecret() error {
+	if webhook.Secret == nil {
+		return nil
+	}
+	kr, err := secrets.DefaultKeyring()
+	if err == secrets.ErrNoMasterKey {
+		webhook.PlaintextSecret = webhook.Secret
+		return nil
+	} else if err != nil {
+		return err
+	}
+
+	envelope, err := kr.Seal([]byte(*webhook.Secret))
+	if err != nil {
+		return err
+	}
+	webhook.PlaintextSecret = nil
+	webhook.SecretCiphertext = envelope.Ciphertext
+	webhook.SecretWrappedKey = envelope.WrappedKey
+	webhook.SecretKeyID = &envelope.KeyID
+	return nil
+}
+
+func (webhook *Webhook) envelope() *secrets.Envelope {
+	envelope := &secrets.Envelope{
+		Ciphertext: webhook.SecretCiphertext,
+		WrappedKey: webhook.SecretWrappedKey,
+	}
+	if webhook.SecretKeyID != nil {
+		envelope.KeyID = *webhook.SecretKeyID
+	}
+	return envelope
+}
+
+// ReencryptWebhookSecrets encrypts plaintext webhook secrets and rewraps the data keys of secrets that are not encrypted with the current master key
+// Returns the amount of webhooks that were updated
+func ReencryptWebhookSecrets(idb bun.IDB, kr *secrets.Keyring) (updated int, err error) {
+	ctx := context.Background()
+	var webhooks []Webhook
+	err = idb.NewSelect().
+		Model(&webhooks).
+		Where("secret_key_id IS DISTINCT FROM ?", kr.CurrentKeyID()).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return updated, errors.WithStack(err)
+	}
+
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		envelope := webhook.envelope()
+		if envelope.Ciphertext == nil {
+			envelope, err = kr.Seal([]byte(*webhook.Secret))
+		} else {
+			envelope, err = kr.Rewrap(envelope)
+		}
+		if err != nil {
+			return updated, err
+		}
+		// Updated without the model hooks, so the data key is rewrapped instead of the secret being sealed again
+		_, err = idb.NewUpdate().
+			Table("webhooks").
+			Set("secret = NULL").
+			Set("secret_ciphertext = ?", envelope.Ciphertext).
+			Set("secret_wrapped_key = ?", envelope.WrappedKey).
+			Set("secret_key_id = ?", envelope.KeyID).
+			Where("id = ?", webhook.ID).
+			Exec(ctx)
+		if err != nil {
+			return updated, errors.WithStack(err)
+		}
+		updated++
+	}
+	return updated, nil
+}
+
+// DecryptWebhookSecrets stores encrypted webhook secrets in plaintext again, e.g. before the encryption migration is rolled back
+// Returns the amount of webhooks that were updated
+func DecryptWebhookSecrets(idb bun.IDB, kr *secrets.Keyring) (updated int, err error) {
+	ctx := context.Background()
+	var webhooks []Webhook
+	err = idb.NewSelect().
+		Model(&webhooks).
+		Where("secret_ciphertext IS NOT NULL").
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return updated, errors.WithStack(err)
+	}
+
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		secret, err := kr.Open(webhook.envelope())
+		if err != nil {
+			return updated, err
+		}
+		// Updated without the model hooks, so the secret is not sealed again
+		_, err = idb.NewUpdate().
+			Table("webhooks").
+			Set("secret = ?", string(secret)).
+			Set("secret_ciphertext = NULL").
+			Set("secret_wrapped_key = NULL").
+			Set("secret_key_id = NULL").
+			Where("id = ?", webhook.ID).
+			Exec(ctx)
+		if err != nil {
+			return updated, errors.WithStack(err)
+		}
+		updated++
+	}
+	return updated, nil
+}
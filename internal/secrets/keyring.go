@@ -0,0 +1,224 @@
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"os"
+	"strings"
+	"sync"
+
+	"github.com/pkg/errors"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"go.uber.org/zap"
+)
+
+// MasterKeyLength is the length of master keys in bytes (AES-256)
+const MasterKeyLength = 32
+
+// Errors raised.
+var (
+	ErrNoMasterKey      = errors.New("no master key configured")
+	ErrUnknownMasterKey = errors.New("secret is encrypted with an unknown master key")
+)
+
+// Envelope is a secret encrypted with a random data key, along with the data key encrypted with a master key
+type Envelope struct {
+	// Ciphertext is the secret encrypted with the data key
+	Ciphertext []byte
+	// WrappedKey is the data key encrypted with the master key
+	WrappedKey []byte
+	// KeyID identifies the master key the data key is encrypted with
+	KeyID string
+}
+
+// Keyring holds the master key new secrets are encrypted with, along with previous master keys that can still be decrypted
+type Keyring struct {
+	current *masterKey
+	keys    map[string]*masterKey
+}
+
+type masterKey struct {
+	id   string
+	aead cipher.AEAD
+}
+
+var keyring *Keyring
+var keyringErr error
+var keyringOnce sync.Once
+
+// DefaultKeyring returns the keyring loaded from the configured master keys
+// ErrNoMasterKey is returned if no master key is configured, in which case secrets are stored in plaintext
+func DefaultKeyring() (*Keyring, error) {
+	keyringOnce.Do(func() {
+		keyring, keyringErr = LoadKeyring(config.Config().MasterKey, config.Config().MasterKeyFile, config.Config().PreviousMasterKeys)
+		if keyringErr == ErrNoMasterKey {
+			zap.L().Warn("no master key configured, gw2 api keys and webhook secrets are stored in plaintext")
+		}
+	})
+	return keyring, keyringErr
+}
+
+// LoadKeyring creates a keyring from a base64 encoded master key, or a file containing it, along with base64 encoded previous master keys
+func LoadKeyring(encodedKey string, keyFile string, previousKeys []string) (*Keyring, error) {
+	if keyFile != "" {
+		data, err := os.ReadFile(keyFile)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		encodedKey = string(data)
+	}
+	if strings.TrimSpace(encodedKey) == "" {
+		return nil, ErrNoMasterKey
+	}
+
+	current, err := parseMasterKey(encodedKey)
+	if err != nil {
+		return nil, err
+	}
+	kr := &Keyring{
+		current: current,
+		keys:    map[string]*masterKey{current.id: current},
+	}
+	for _, encoded := range previousKeys {
+		if strings.TrimSpace(encoded) == "" {
+			continue
+		}
+		previous, err := parseMasterKey(encoded)
+		if err != nil {
+			return nil, err
+		}
+		kr.keys[previous.id] = previous
+	}
+	return kr, nil
+}
+
+// GenerateMasterKey returns a new random base64 encoded master key
+func GenerateMasterKey() (string, error) {
+	key := make([]byte, MasterKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return base64.StdEncoding.EncodeToString(key), nil
+}
+
+func parseMasterKey(encoded string) (*masterKey, error) {
+	key, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return nil, errors.Wrap(err, "master key is not valid base64")
+	}
+	if len(key) != MasterKeyLength {
+		return nil, errors.Errorf("master key must be %d bytes, got %d", MasterKeyLength, len(key))
+	}
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+	// The id is a fingerprint of the key, so it can be stored next to the secrets without revealing the key
+	fingerprint := sha256.Sum256(key)
+	return &masterKey{
+		id:   hex.EncodeToString(fingerprint[:8]),
+		aead: aead,
+	}, nil
+}
+
+// CurrentKeyID returns the id of the master key new secrets are encrypted with
+func (kr *Keyring) CurrentKeyID() string {
+	return kr.current.id
+}
+
+// Seal encrypts the secret with a new data key, which is encrypted with the current master key
+func (kr *Keyring) Seal(secret []byte) (*Envelope, error) {
+	dataKey := make([]byte, MasterKeyLength)
+	if _, err := rand.Read(dataKey); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	ciphertext, err := seal(dataAEAD, secret)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := seal(kr.current.aead, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Ciphertext: ciphertext,
+		WrappedKey: wrappedKey,
+		KeyID:      kr.current.id,
+	}, nil
+}
+
+// Open decrypts the secret in the envelope
+func (kr *Keyring) Open(envelope *Envelope) ([]byte, error) {
+	dataKey, err := kr.unwrap(envelope)
+	if err != nil {
+		return nil, err
+	}
+	dataAEAD, err := newAEAD(dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return open(dataAEAD, envelope.Ciphertext)
+}
+
+// Rewrap encrypts the data key of the envelope with the current master key. The secret itself is not re-encrypted
+func (kr *Keyring) Rewrap(envelope *Envelope) (*Envelope, error) {
+	if envelope.KeyID == kr.current.id {
+		return envelope, nil
+	}
+	dataKey, err := kr.unwrap(envelope)
+	if err != nil {
+		return nil, err
+	}
+	wrappedKey, err := seal(kr.current.aead, dataKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Envelope{
+		Ciphertext: envelope.Ciphertext,
+		WrappedKey: wrappedKey,
+		KeyID:      kr.current.id,
+	}, nil
+}
+
+func (kr *Keyring) unwrap(envelope *Envelope) ([]byte, error) {
+	key, ok := kr.keys[envelope.KeyID]
+	if !ok {
+		return nil, errors.Wrapf(ErrUnknownMasterKey, "master key %s", envelope.KeyID)
+	}
+	return open(key.aead, envelope.WrappedKey)
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	aead, err := cipher.NewGCM(block)
+	return aead, errors.WithStack(err)
+}
+
+// seal encrypts the plaintext and prefixes it with the random nonce used
+func seal(aead cipher.AEAD, plaintext []byte) ([]byte, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return aead.Seal(nonce, nonce, plaintext, nil), nil
+}
+
+func open(aead cipher.AEAD, ciphertext []byte) ([]byte, error) {
+	if len(ciphertext) < aead.NonceSize() {
+		return nil, errors.New("ciphertext is too short")
+	}
+	nonce, ciphertext := ciphertext[:aead.NonceSize()], ciphertext[aead.NonceSize():]
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	return plaintext, errors.WithStack(err)
+}
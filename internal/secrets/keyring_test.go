@@ -0,0 +1,183 @@
+package secrets
+
+import (
+	"bytes"
+	"encoding/base64"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pkg/errors"
+)
+
+func generateMasterKey(t *testing.T) string {
+	t.Helper()
+	key, err := GenerateMasterKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key
+}
+
+func loadKeyring(t *testing.T, key string, previousKeys ...string) *Keyring {
+	t.Helper()
+	kr, err := LoadKeyring(key, "", previousKeys)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return kr
+}
+
+func TestLoadKeyring(t *testing.T) {
+	key := generateMasterKey(t)
+	keyFile := filepath.Join(t.TempDir(), "master.key")
+	if err := os.WriteFile(keyFile, []byte(key+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	tests := []struct {
+		name         string
+		key          string
+		keyFile      string
+		previousKeys []string
+		wantErr      error
+		wantAnyErr   bool
+	}{
+		{"key", key, "", nil, nil, false},
+		{"key file", "", keyFile, nil, nil, false},
+		{"previous keys", key, "", []string{generateMasterKey(t), ""}, nil, false},
+		{"no key", " ", "", nil, ErrNoMasterKey, true},
+		{"invalid base64", "not base64!", "", nil, nil, true},
+		{"short key", base64.StdEncoding.EncodeToString([]byte("short")), "", nil, nil, true},
+		{"invalid previous key", key, "", []string{"not base64!"}, nil, true},
+		{"missing key file", "", filepath.Join(t.TempDir(), "missing.key"), nil, nil, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			_, err := LoadKeyring(test.key, test.keyFile, test.previousKeys)
+			if (err != nil) != test.wantAnyErr {
+				t.Fatalf("LoadKeyring() error = %v, want error %v", err, test.wantAnyErr)
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("LoadKeyring() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestSealOpen(t *testing.T) {
+	kr := loadKeyring(t, generateMasterKey(t))
+	tests := []struct {
+		name   string
+		secret []byte
+	}{
+		{"api key", []byte("01234567-89AB-CDEF-0123-456789ABCDEF01234567-89AB-CDEF-0123-456789ABCDEF")},
+		{"empty", []byte{}},
+		{"binary", []byte{0, 1, 2, 255}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			envelope, err := kr.Seal(test.secret)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if envelope.KeyID != kr.CurrentKeyID() {
+				t.Errorf("key id = %s, want %s", envelope.KeyID, kr.CurrentKeyID())
+			}
+			if len(test.secret) > 0 && bytes.Contains(envelope.Ciphertext, test.secret) {
+				t.Error("ciphertext contains the secret")
+			}
+			secret, err := kr.Open(envelope)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(secret, test.secret) {
+				t.Errorf("Open() = %q, want %q", secret, test.secret)
+			}
+		})
+	}
+}
+
+func TestOpenRejects(t *testing.T) {
+	kr := loadKeyring(t, generateMasterKey(t))
+	otherKr := loadKeyring(t, generateMasterKey(t))
+	tests := []struct {
+		name    string
+		tamper  func(envelope *Envelope)
+		wantErr error
+	}{
+		{"unknown master key", func(envelope *Envelope) { envelope.KeyID = otherKr.CurrentKeyID() }, ErrUnknownMasterKey},
+		{"tampered ciphertext", func(envelope *Envelope) { envelope.Ciphertext[len(envelope.Ciphertext)-1] ^= 1 }, nil},
+		{"tampered wrapped key", func(envelope *Envelope) { envelope.WrappedKey[len(envelope.WrappedKey)-1] ^= 1 }, nil},
+		{"truncated ciphertext", func(envelope *Envelope) { envelope.Ciphertext = envelope.Ciphertext[:4] }, nil},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			envelope, err := kr.Seal([]byte("secret"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			test.tamper(envelope)
+			_, err = kr.Open(envelope)
+			if err == nil {
+				t.Fatal("Open() succeeded, want error")
+			}
+			if test.wantErr != nil && !errors.Is(err, test.wantErr) {
+				t.Errorf("Open() error = %v, want %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestRewrap(t *testing.T) {
+	oldKey := generateMasterKey(t)
+	newKey := generateMasterKey(t)
+	oldKr := loadKeyring(t, oldKey)
+	rotatedKr := loadKeyring(t, newKey, oldKey)
+	newKr := loadKeyring(t, newKey)
+
+	envelope, err := oldKr.Seal([]byte("secret"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The rotated keyring still opens secrets of the previous master key
+	secret, err := rotatedKr.Open(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret) != "secret" {
+		t.Errorf("Open() = %q, want %q", secret, "secret")
+	}
+
+	rewrapped, err := rotatedKr.Rewrap(envelope)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rewrapped.KeyID != newKr.CurrentKeyID() {
+		t.Errorf("key id = %s, want %s", rewrapped.KeyID, newKr.CurrentKeyID())
+	}
+	if !bytes.Equal(rewrapped.Ciphertext, envelope.Ciphertext) {
+		t.Error("Rewrap() re-encrypted the secret, want only the data key rewrapped")
+	}
+
+	// Once rewrapped, the previous master key is no longer needed
+	secret, err = newKr.Open(rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(secret) != "secret" {
+		t.Errorf("Open() = %q, want %q", secret, "secret")
+	}
+	if _, err = newKr.Open(envelope); !errors.Is(err, ErrUnknownMasterKey) {
+		t.Errorf("Open() error = %v, want %v", err, ErrUnknownMasterKey)
+	}
+
+	// Envelopes of the current master key are left as is
+	current, err := newKr.Rewrap(rewrapped)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if current != rewrapped {
+		t.Error("Rewrap() rewrapped an envelope of the current master key")
+	}
+}
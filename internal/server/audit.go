@@ -0,0 +1,97 @@
+package server
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/audit"
+	"go.uber.org/zap"
+)
+
+// auditAccount is the part of an account recorded in the audit log
+type auditAccount struct {
+	ID    string `json:"id"`
+	Name  string `json:"name"`
+	World int    `json:"world"`
+}
+
+// recordAudit records a mutating call made by the service of the request
+// The entry must be recorded in the transaction of the call, so the call is rolled back if it cannot be audited
+func recordAudit(c *gin.Context, idb bun.IDB, entry api.AuditEntry) error {
+	entry.ServiceUuid = c.GetString("service_id")
+	return audit.Record(idb, &entry)
+}
+
+// auditedTx runs the mutation and records the audit entry it returns in a single transaction
+func auditedTx(c *gin.Context, mutation func(tx bun.Tx) (api.AuditEntry, error)) error {
+	return orm.DB().RunInTx(c, nil, func(ctx context.Context, tx bun.Tx) error {
+		entry, err := mutation(tx)
+		if err != nil {
+			return err
+		}
+		return recordAudit(c, tx, entry)
+	})
+}
+
+// platformUserAudit returns an audit entry targeting the platform user, including the user id if the platform user is linked to a user
+func platformUserAudit(idb bun.IDB, action api.AuditAction, platformID int, platformUserID string) api.AuditEntry {
+	entry := api.AuditEntry{
+		Action:         action,
+		PlatformID:     &platformID,
+		PlatformUserID: &platformUserID,
+	}
+	var link orm.PlatformLink
+	err := idb.NewSelect().
+		Model(&link).
+		Where("platform_id = ? AND platform_user_id = ?", platformID, platformUserID).
+		Scan(context.Background())
+	if err != nil && err != sql.ErrNoRows {
+		zap.L().Error("unable to find user for audit entry", zap.Error(err))
+	} else if link.UserID != 0 {
+		entry.UserID = &link.UserID
+	}
+	return entry
+}
+
+// subjectAudit returns an audit entry targeting something that is not a user
+func subjectAudit(action api.AuditAction, subject string) api.AuditEntry {
+	return api.AuditEntry{
+		Action:  action,
+		Subject: &subject,
+	}
+}
+
+// idSubject formats an id as an audit subject
+func idSubject(id int64) string {
+	return strconv.FormatInt(id, 10)
+}
+
+// auditAccounts returns the accounts of the user as recorded in the audit log
+func auditAccounts(idb bun.IDB, userID *int64) []auditAccount {
+	if userID == nil {
+		return nil
+	}
+	var accounts []api.Account
+	err := idb.NewSelect().
+		Model(&accounts).
+		Where("user_id = ?", *userID).
+		Scan(context.Background())
+	if err != nil {
+		zap.L().Error("unable to get accounts for audit entry", zap.Error(err))
+		return nil
+	}
+	auditAccounts := make([]auditAccount, len(accounts))
+	for i, acc := range accounts {
+		auditAccounts[i] = auditAccount{
+			ID:    acc.ID,
+			Name:  acc.Name,
+			World: acc.World,
+		}
+	}
+	return auditAccounts
+}
@@ -0,0 +1,177 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/audit"
+	"github.com/vennekilde/gw2verify/v2/pkg/ratelimit"
+	"github.com/vennekilde/gw2verify/v2/pkg/services"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+// (GET /v1/admin/services)
+func (e *Endpoints) GetAdminServices(c *gin.Context) {
+	serviceList, err := services.GetServices(orm.DB())
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &serviceList)
+}
+
+// (POST /v1/admin/services)
+func (e *Endpoints) PostAdminService(c *gin.Context) {
+	var reqBody api.ServiceCreate
+	err := c.Bind(&reqBody)
+	if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+	if err = services.ValidateScopes(reqBody.Scopes); err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	var service *api.Service
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		service, err = services.CreateService(tx, reqBody.Name, reqBody.Scopes)
+		if err != nil {
+			return entry, err
+		}
+		return audit.ServiceEntry(api.AuditActionServiceCreate, nil, service), nil
+	})
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusCreated, service)
+}
+
+// (GET /v1/admin/services/{service_uuid})
+func (e *Endpoints) GetAdminService(c *gin.Context, serviceUuid api.ServiceUuid) {
+	service, err := services.GetService(orm.DB(), serviceUuid)
+	respondService(c, service, err)
+}
+
+// (PATCH /v1/admin/services/{service_uuid})
+func (e *Endpoints) PatchAdminService(c *gin.Context, serviceUuid api.ServiceUuid) {
+	var reqBody api.ServiceUpdate
+	err := c.Bind(&reqBody)
+	if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	if reqBody.Scopes != nil {
+		if err = services.ValidateScopes(*reqBody.Scopes); err != nil {
+			ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+			return
+		}
+	}
+
+	service, err := auditedServiceChange(c, api.AuditActionServiceUpdate, serviceUuid, func(tx bun.Tx) (*api.Service, error) {
+		return services.UpdateService(tx, serviceUuid, reqBody)
+	})
+	respondService(c, service, err)
+}
+
+// (POST /v1/admin/services/{service_uuid}/rotate)
+func (e *Endpoints) PostAdminServiceRotate(c *gin.Context, serviceUuid api.ServiceUuid) {
+	service, err := auditedServiceChange(c, api.AuditActionServiceRotate, serviceUuid, func(tx bun.Tx) (*api.Service, error) {
+		return services.RotateServiceKey(tx, serviceUuid)
+	})
+	respondService(c, service, err)
+}
+
+// (POST /v1/admin/services/{service_uuid}/disable)
+func (e *Endpoints) PostAdminServiceDisable(c *gin.Context, serviceUuid api.ServiceUuid) {
+	service, err := auditedServiceChange(c, api.AuditActionServiceDisable, serviceUuid, func(tx bun.Tx) (*api.Service, error) {
+		return services.DisableService(tx, serviceUuid)
+	})
+	respondService(c, service, err)
+}
+
+// (POST /v1/admin/users/{user_id}/merge)
+func (e *Endpoints) PostAdminUserMerge(c *gin.Context, userId api.UserId) {
+	var reqBody api.UserMergeRequest
+	err := c.Bind(&reqBody)
+	if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	serviceID := c.GetString("service_id")
+	merge := api.UserMerge{
+		SourceUserID: userId,
+		TargetUserID: reqBody.TargetUserID,
+		ServiceUuid:  &serviceID,
+	}
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		if err = e.mergeService.Merge(tx, &merge); err != nil {
+			return entry, err
+		}
+		entry = subjectAudit(api.AuditActionUserMerge, idSubject(merge.ID))
+		entry.UserID = &merge.TargetUserID
+		entry.Before = merge.SourceUser
+		return entry, nil
+	})
+	if err == verify.ErrMergeSameUser {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	} else if err == verify.ErrUserNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &merge)
+}
+
+// (GET /v1/admin/users/{user_id}/merges)
+func (e *Endpoints) GetAdminUserMerges(c *gin.Context, userId api.UserId) {
+	merges, err := verify.GetUserMerges(orm.DB(), userId)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &merges)
+}
+
+func respondService(c *gin.Context, service *api.Service, err error) {
+	if err == services.ErrServiceNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, service)
+}
+
+// (GET /v1/admin/gw2api/usage)
+func (e *Endpoints) GetAdminGW2APIUsage(c *gin.Context) {
+	c.JSON(http.StatusOK, ratelimit.GetUsage())
+}
+
+// auditedServiceChange applies the change to the service and records it in a single transaction
+func auditedServiceChange(c *gin.Context, action api.AuditAction, serviceUUID string, change func(tx bun.Tx) (*api.Service, error)) (service *api.Service, err error) {
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		before, _ := services.GetService(tx, serviceUUID)
+		service, err = change(tx)
+		if err != nil {
+			return entry, err
+		}
+		return audit.ServiceEntry(action, before, service), nil
+	})
+	return service, err
+}
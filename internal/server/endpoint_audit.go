@@ -0,0 +1,31 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/audit"
+)
+
+// (GET /v1/audit)
+func (e *Endpoints) GetAuditEntries(c *gin.Context, params api.GetAuditEntriesParams) {
+	filter := audit.Filter{
+		ServiceUUID:    params.ServiceUuid,
+		UserID:         params.UserId,
+		PlatformID:     params.PlatformId,
+		PlatformUserID: params.PlatformUserId,
+		Action:         params.Action,
+		Limit:          pageLimit(params.Limit, 100),
+		Offset:         pageOffset(params.Offset),
+	}
+
+	entries, err := audit.GetEntries(orm.DB(), filter)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &entries)
+}
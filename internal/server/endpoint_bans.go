@@ -0,0 +1,105 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+// (GET /v1/bans)
+func (e *Endpoints) GetBans(c *gin.Context, params api.GetBansParams) {
+	filter := verify.BanFilter{
+		Active: params.Active,
+		UserID: params.UserId,
+		Reason: params.Reason,
+		Limit:  pageLimit(params.Limit, 100),
+		Offset: pageOffset(params.Offset),
+	}
+
+	bans, err := verify.GetBans(orm.DB(), filter)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &bans)
+}
+
+// (GET /v1/bans/{ban_id})
+func (e *Endpoints) GetBan(c *gin.Context, banId api.BanId) {
+	ban, err := verify.GetBanByID(orm.DB(), banId)
+	if err == verify.ErrBanNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, ban)
+}
+
+// (PATCH /v1/bans/{ban_id})
+func (e *Endpoints) PatchBan(c *gin.Context, banId api.BanId) {
+	var reqBody api.BanUpdate
+	// decode request
+	err := c.Bind(&reqBody)
+	if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	var bans []api.Ban
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		before, _ := verify.GetBanGroup(tx, banId)
+		bans, err = e.banService.UpdateBan(tx, banId, reqBody.Until, reqBody.Reason, c.GetString("service_id"))
+		return banAudit(api.AuditActionBanUpdate, banId, before, bans), err
+	})
+	if err == verify.ErrBanNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &bans)
+}
+
+// (DELETE /v1/bans/{ban_id})
+func (e *Endpoints) DeleteBan(c *gin.Context, banId api.BanId) {
+	var bans []api.Ban
+	err := auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		before, _ := verify.GetBanGroup(tx, banId)
+		bans, err = e.banService.LiftBan(tx, banId, c.GetString("service_id"))
+		return banAudit(api.AuditActionBanLift, banId, before, bans), err
+	})
+	if err == verify.ErrBanNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &bans)
+}
+
+// banAudit returns the audit entry of a change to the group of an existing ban
+func banAudit(action api.AuditAction, banID int64, before []api.Ban, after []api.Ban) api.AuditEntry {
+	entry := subjectAudit(action, idSubject(banID))
+	for i := range after {
+		if after[i].ID == banID {
+			entry.UserID = &after[i].UserID
+		}
+	}
+	if before != nil {
+		entry.Before = before
+	}
+	entry.After = after
+	return entry
+}
@@ -0,0 +1,21 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+// (GET /v1/matchups)
+func (e *Endpoints) GetMatchups(c *gin.Context, params api.GetMatchupsParams) {
+	matchups, err := verify.GetMatchupHistory(orm.DB(), params.World, params.Since)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &matchups)
+}
@@ -3,13 +3,17 @@ package server
 import (
 	"context"
 	"database/sql"
+	"errors"
+	"math"
 	"net/http"
+	"strconv"
 	"time"
 
-	"github.com/MrGunflame/gw2api"
 	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/ratelimit"
 	"github.com/vennekilde/gw2verify/v2/pkg/verify"
 )
 
@@ -18,13 +22,37 @@ func (e *Endpoints) GetPlatformUserUpdates(c *gin.Context, platformId api.Platfo
 	ticker := time.NewTicker(120 * time.Second)
 	defer func() { ticker.Stop() }()
 
-	ch := e.eventEmitter.GetUserListener(c.GetString("service_id"), &platformId)
+	// Listen before replaying, so no events are lost in between
+	listener := e.eventEmitter.SubscribeUsers(c.GetString("service_id"), &platformId)
+	defer e.eventEmitter.UnsubscribeUsers(listener)
 
-	select {
-	case event := <-ch:
-		c.JSON(http.StatusOK, event)
-	case <-ticker.C:
-		c.Status(http.StatusRequestTimeout)
+	// Replay missed events, if any
+	var cursor int64
+	if params.Since != nil {
+		cursor = *params.Since
+		event, err := e.eventEmitter.NextEvent(cursor, &platformId)
+		if err != nil {
+			ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+			return
+		}
+		if event != nil {
+			c.JSON(http.StatusOK, event)
+			return
+		}
+	}
+
+	for {
+		select {
+		case event := <-listener.C():
+			// Skip events that have already been replayed
+			if event.EventID != nil && *event.EventID <= cursor {
+				continue
+			}
+			c.JSON(http.StatusOK, event)
+		case <-ticker.C:
+			c.Status(http.StatusRequestTimeout)
+		}
+		return
 	}
 }
 
@@ -47,6 +75,79 @@ func (e *Endpoints) GetPlatformUser(c *gin.Context, platformId api.PlatformId, p
 	c.JSON(http.StatusOK, &user)
 }
 
+// (DELETE /v1/platform/{platform_id}/users/{platform_user_id})
+func (e *Endpoints) DeletePlatformUser(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId) {
+	var link *api.PlatformLink
+	err := auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		link, err = e.unlinkService.UnlinkPlatformUser(tx, platformId, platformUserId)
+		if err != nil {
+			return entry, err
+		}
+		return api.AuditEntry{
+			Action:         api.AuditActionPlatformLinkDelete,
+			UserID:         &link.UserID,
+			PlatformID:     &platformId,
+			PlatformUserID: &platformUserId,
+			Before:         link,
+		}, nil
+	})
+	if err == verify.ErrPlatformLinkNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, link)
+}
+
+// (DELETE /v1/platform/{platform_id}/users/{platform_user_id}/accounts/{account_id})
+func (e *Endpoints) DeletePlatformUserAccount(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId, accountId api.AccountId) {
+	err := auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		entry = platformUserAudit(tx, api.AuditActionAccountDelete, platformId, platformUserId)
+		entry.Subject = &accountId
+		entry.Before = auditAccounts(tx, entry.UserID)
+		if err = e.unlinkService.DeleteAccount(tx, platformId, platformUserId, accountId); err != nil {
+			return entry, err
+		}
+		entry.After = auditAccounts(tx, entry.UserID)
+		return entry, nil
+	})
+	if err == verify.ErrUserNotFound || err == verify.ErrAccountNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// (DELETE /v1/platform/{platform_id}/users/{platform_user_id}/apikeys/{apikey_id})
+func (e *Endpoints) DeletePlatformUserAPIKey(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId, apikeyId api.ApikeyId) {
+	err := auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		entry = platformUserAudit(tx, api.AuditActionAPIKeyDelete, platformId, platformUserId)
+		entry.Subject = &apikeyId
+		entry.Before = auditAccounts(tx, entry.UserID)
+		if err = e.unlinkService.DeleteAPIKey(tx, platformId, platformUserId, apikeyId); err != nil {
+			return entry, err
+		}
+		entry.After = auditAccounts(tx, entry.UserID)
+		return entry, nil
+	})
+	if err == verify.ErrUserNotFound || err == verify.ErrAPIKeyNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
 // (PUT /v1/platform/{platform_id}/users/{platform_user_id}/apikey)
 func (e *Endpoints) PutPlatformUserAPIKey(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId, params api.PutPlatformUserAPIKeyParams) {
 	var reqBody api.APIKeyData
@@ -67,13 +168,39 @@ func (e *Endpoints) PutPlatformUserAPIKey(c *gin.Context, platformId api.Platfor
 	if params.SkipRequirements != nil {
 		skipRequirements = *params.SkipRequirements
 	}
+	if skipRequirements && !HasScope(c, api.ScopeRequirementsSkip) {
+		ThrowReqError(c, "skipping requirements requires the requirements:skip scope", nil, http.StatusForbidden)
+		return
+	}
 
-	gw2a := gw2api.New()
-	err, userErr := e.syncher.SetAPIKeyByUserService(gw2a, params.World, platformId, platformUserId, reqBody.Primary, reqBody.Apikey, skipRequirements)
-	if err != nil {
+	var userErr error
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		entry = platformUserAudit(tx, api.AuditActionAPIKeySet, platformId, platformUserId)
+		entry.Before = auditAccounts(tx, entry.UserID)
+
+		gw2a := ratelimit.NewSession(api.GW2APIBudgetInteractive)
+		err, userErr = e.syncher.SetAPIKeyByUserService(tx, gw2a, params.World, platformId, platformUserId, reqBody.Primary, reqBody.Apikey, skipRequirements)
+		if err != nil {
+			return entry, err
+		}
+
+		// The platform user may have been linked to a user by setting the api key
+		if entry.UserID == nil {
+			entry.UserID = platformUserAudit(tx, api.AuditActionAPIKeySet, platformId, platformUserId).UserID
+		}
+		entry.After = auditAccounts(tx, entry.UserID)
+		return entry, nil
+	})
+	var rateLimited *ratelimit.RateLimitedError
+	if errors.As(err, &rateLimited) {
+		c.Header("Retry-After", strconv.Itoa(int(math.Ceil(rateLimited.RetryAfter.Seconds()))))
+		ThrowReqError(c, err.Error(), errors.New("the gw2 api is busy, try again later"), http.StatusTooManyRequests)
+		return
+	} else if err != nil {
 		ThrowReqError(c, err.Error(), userErr, http.StatusInternalServerError)
 		return
 	}
+
 	c.Status(http.StatusCreated)
 }
 
@@ -84,7 +211,7 @@ func (e *Endpoints) GetPlatformUserAPIKeyName(c *gin.Context, platformId api.Pla
 }
 
 // (PUT /v1/platform/{platform_id}/users/{platform_user_id}/ban)
-func (e *Endpoints) PutPlatformUserBan(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId) {
+func (e *Endpoints) PutPlatformUserBan(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId, params api.PutPlatformUserBanParams) {
 	var reqBody api.Ban
 	// decode request
 	err := c.Bind(&reqBody)
@@ -93,14 +220,53 @@ func (e *Endpoints) PutPlatformUserBan(c *gin.Context, platformId api.PlatformId
 		return
 	}
 
-	err = e.banService.BanServiceUser(reqBody.Until, reqBody.Reason, platformId, platformUserId)
-	if err != nil {
+	allIdentities := false
+	if params.AllIdentities != nil {
+		allIdentities = *params.AllIdentities
+	}
+
+	var bans []api.Ban
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		entry = platformUserAudit(tx, api.AuditActionBan, platformId, platformUserId)
+		if entry.UserID != nil {
+			if before, err := verify.GetUserBans(tx, *entry.UserID); err == nil {
+				entry.Before = before
+			}
+		}
+		bans, err = e.banService.BanServiceUser(tx, reqBody.Until, reqBody.Reason, platformId, platformUserId, c.GetString("service_id"), allIdentities)
+		entry.After = bans
+		return entry, err
+	})
+	if err == verify.ErrUserNotFound {
+		ThrowReqError(c, err.Error(), err, http.StatusNotFound)
+	} else if err != nil {
 		ThrowReqError(c, err.Error(), err, http.StatusInternalServerError)
 	} else {
-		c.Status(http.StatusCreated)
+		c.JSON(http.StatusCreated, &bans)
 	}
 }
 
+// (GET /v1/platform/{platform_id}/users/{platform_user_id}/bans)
+func (e *Endpoints) GetPlatformUserBans(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId) {
+	link, err := orm.GetPlatformLink(platformId, platformUserId)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+	if link.UserID == 0 {
+		c.Status(http.StatusNotFound)
+		return
+	}
+
+	bans, err := verify.GetUserBans(orm.DB(), link.UserID)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &bans)
+}
+
 // (POST /v1/platform/{platform_id}/users/{platform_user_id}/refresh)
 func (e *Endpoints) PostPlatformUserRefresh(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId) {
 	tx, err := orm.DB().BeginTx(c, nil)
@@ -125,13 +291,22 @@ func (e *Endpoints) PostPlatformUserRefresh(c *gin.Context, platformId api.Platf
 		return
 	}
 
-	gw2API := gw2api.New()
+	entry := platformUserAudit(tx, api.AuditActionRefresh, platformId, platformUserId)
+	entry.Before = auditAccounts(tx, &link.UserID)
+
+	gw2API := ratelimit.NewSession(api.GW2APIBudgetInteractive)
 	err = e.syncher.SynchronizeUser(tx, gw2API, link.UserID)
 	if err != nil {
 		ThrowReqError(c, err.Error(), err, http.StatusInternalServerError)
 		return
 	}
 
+	entry.After = auditAccounts(tx, &link.UserID)
+	if err = recordAudit(c, tx, entry); err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
@@ -0,0 +1,53 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/privacy"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+// (GET /v1/platform/{platform_id}/users/{platform_user_id}/export)
+func (e *Endpoints) GetPlatformUserExport(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId) {
+	export, err := privacy.Export(orm.DB(), c.GetString("service_id"), platformId, platformUserId)
+	if err == verify.ErrUserNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, export)
+}
+
+// (POST /v1/platform/{platform_id}/users/{platform_user_id}/erase)
+func (e *Endpoints) PostPlatformUserErase(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId) {
+	var report *api.ErasureReport
+	err := auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		report, err = e.privacy.Erase(tx, c.GetString("service_id"), platformId, platformUserId)
+		if err != nil {
+			return entry, err
+		}
+		// The platform user id is not recorded, as it has just been erased
+		return api.AuditEntry{
+			Action:     api.AuditActionUserErase,
+			UserID:     &report.UserID,
+			PlatformID: &platformId,
+			After:      report,
+		}, nil
+	})
+	if err == verify.ErrUserNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, report)
+}
@@ -2,10 +2,12 @@ package server
 
 import (
 	"context"
+	"database/sql"
 	"io"
 	"net/http"
 
 	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
 )
@@ -45,7 +47,7 @@ func (e *Endpoints) GetServiceSubjectProperties(c *gin.Context, serviceUuid api.
 }
 
 // (PUT /v1/services/{service_uuid}/properties/{subject})
-func (e *Endpoints) PutServiceSubjectProperties(c *gin.Context, serviceUuid api.ServiceUuid, subject api.Subject) {
+func (e *Endpoints) PutServiceSubjectProperties(c *gin.Context, serviceUuid api.ServiceUuid, subject api.Subject, params api.PutServiceSubjectPropertiesParams) {
 	ctx := context.Background()
 
 	var properties []api.Property
@@ -56,14 +58,34 @@ func (e *Endpoints) PutServiceSubjectProperties(c *gin.Context, serviceUuid api.
 		return
 	}
 
-	_, err = orm.DB().NewInsert().
-		Model(&properties).
-		Value("db_updated", "NOW()").
-		Value("service_uuid", "?", serviceUuid).
-		Value("subject", "?", subject).
-		On(`CONFLICT ("service_uuid", "subject", "name") DO UPDATE`).
-		Set("name = EXCLUDED.name, value = EXCLUDED.value, db_updated = EXCLUDED.db_updated").
-		Exec(ctx)
+	for i := range properties {
+		properties[i].PlatformID = params.PlatformId
+	}
+
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		before, err := getSubjectProperties(tx, serviceUuid, subject)
+		if err != nil {
+			return entry, err
+		}
+
+		_, err = tx.NewInsert().
+			Model(&properties).
+			Value("db_updated", "NOW()").
+			Value("service_uuid", "?", serviceUuid).
+			Value("subject", "?", subject).
+			Value("platform_id", "?", params.PlatformId).
+			On(`CONFLICT ("service_uuid", "subject", "name") DO UPDATE`).
+			Set("name = EXCLUDED.name, value = EXCLUDED.value, platform_id = EXCLUDED.platform_id, db_updated = EXCLUDED.db_updated").
+			Exec(ctx)
+		if err != nil {
+			return entry, err
+		}
+
+		entry = subjectAudit(api.AuditActionPropertyPut, subject)
+		entry.Before = before
+		entry.After = properties
+		return entry, nil
+	})
 	if err != nil {
 		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
 		return
@@ -90,7 +112,7 @@ func (e *Endpoints) GetServiceSubjectProperty(c *gin.Context, serviceUuid api.Se
 }
 
 // (PUT /v1/services/{service_uuid}/properties/{subject}/{property_name})
-func (e *Endpoints) PutServiceSubjectProperty(c *gin.Context, serviceUuid api.ServiceUuid, subject api.Subject, propertyName api.PropertyName) {
+func (e *Endpoints) PutServiceSubjectProperty(c *gin.Context, serviceUuid api.ServiceUuid, subject api.Subject, propertyName api.PropertyName, params api.PutServiceSubjectPropertyParams) {
 	ctx := context.Background()
 
 	// decode request
@@ -100,18 +122,40 @@ func (e *Endpoints) PutServiceSubjectProperty(c *gin.Context, serviceUuid api.Se
 		return
 	}
 
-	property := api.Property{
-		Name:  propertyName,
-		Value: string(value),
-	}
-	_, err = orm.DB().NewInsert().
-		Model(&property).
-		Value("db_updated", "NOW()").
-		Value("service_uuid", "?", serviceUuid).
-		Value("subject", "?", subject).
-		On(`CONFLICT ("service_uuid", "subject", "name") DO UPDATE`).
-		Set("name = EXCLUDED.name, value = EXCLUDED.value, db_updated = EXCLUDED.db_updated").
-		Exec(ctx)
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		entry = subjectAudit(api.AuditActionPropertyPut, subject)
+		var before api.Property
+		err = tx.NewSelect().
+			Model(&before).
+			Where("service_uuid = ? AND subject = ? AND name = ?", serviceUuid, subject, propertyName).
+			Scan(ctx)
+		if err == nil {
+			entry.Before = before
+		} else if err != sql.ErrNoRows {
+			return entry, err
+		}
+
+		property := api.Property{
+			Name:       propertyName,
+			Value:      string(value),
+			PlatformID: params.PlatformId,
+		}
+		_, err = tx.NewInsert().
+			Model(&property).
+			Value("db_updated", "NOW()").
+			Value("service_uuid", "?", serviceUuid).
+			Value("subject", "?", subject).
+			Value("platform_id", "?", params.PlatformId).
+			On(`CONFLICT ("service_uuid", "subject", "name") DO UPDATE`).
+			Set("name = EXCLUDED.name, value = EXCLUDED.value, platform_id = EXCLUDED.platform_id, db_updated = EXCLUDED.db_updated").
+			Exec(ctx)
+		if err != nil {
+			return entry, err
+		}
+
+		entry.After = property
+		return entry, nil
+	})
 	if err != nil {
 		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
 		return
@@ -119,3 +163,12 @@ func (e *Endpoints) PutServiceSubjectProperty(c *gin.Context, serviceUuid api.Se
 
 	c.Status(http.StatusOK)
 }
+
+// getSubjectProperties returns the properties a service has stored for the subject
+func getSubjectProperties(idb bun.IDB, serviceUUID string, subject string) (properties []api.Property, err error) {
+	err = idb.NewSelect().
+		Model(&properties).
+		Where("service_uuid = ? AND subject = ?", serviceUUID, subject).
+		Scan(context.Background())
+	return properties, err
+}
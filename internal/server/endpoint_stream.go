@@ -0,0 +1,174 @@
+package server
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"go.uber.org/zap"
+)
+
+const (
+	sseEventUser               = "user"
+	sseEventVerificationStatus = "verification_status"
+)
+
+// sseStream writes Server-Sent Events to a gin response
+type sseStream struct {
+	c *gin.Context
+	// lastEventID is the id of the last event sent, used to skip events that have already been replayed
+	lastEventID int64
+}
+
+// newSSEStream prepares the response for streaming events
+// The cursor is taken from the since parameter, or the Last-Event-ID header when a client reconnects
+func newSSEStream(c *gin.Context, since *int64) *sseStream {
+	stream := &sseStream{c: c}
+	if since != nil {
+		stream.lastEventID = *since
+	} else if lastEventID, err := strconv.ParseInt(c.GetHeader("Last-Event-ID"), 10, 64); err == nil {
+		stream.lastEventID = lastEventID
+	}
+
+	header := c.Writer.Header()
+	header.Set("Content-Type", "text/event-stream")
+	header.Set("Cache-Control", "no-cache")
+	header.Set("Connection", "keep-alive")
+	// Disable proxy buffering, so events are delivered immediately
+	header.Set("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+	return stream
+}
+
+// replaying reports if events should be replayed from the event log
+func (s *sseStream) replaying() bool {
+	return s.lastEventID > 0
+}
+
+// send writes an event to the stream, unless it has already been sent
+func (s *sseStream) send(event string, eventID *int64, data any) error {
+	if eventID != nil {
+		if *eventID <= s.lastEventID {
+			return nil
+		}
+		s.lastEventID = *eventID
+	}
+
+	body, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+	if eventID != nil {
+		if _, err = fmt.Fprintf(s.c.Writer, "id: %d\n", *eventID); err != nil {
+			return err
+		}
+	}
+	if _, err = fmt.Fprintf(s.c.Writer, "event: %s\ndata: %s\n\n", event, body); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// heartbeat writes a comment to the stream, keeping the connection alive through proxies
+func (s *sseStream) heartbeat() error {
+	if _, err := fmt.Fprint(s.c.Writer, ": heartbeat\n\n"); err != nil {
+		return err
+	}
+	s.c.Writer.Flush()
+	return nil
+}
+
+// (GET /v1/platform/{platform_id}/users/updates/stream)
+func (e *Endpoints) GetPlatformUserUpdatesStream(c *gin.Context, platformId api.PlatformId, params api.GetPlatformUserUpdatesStreamParams) {
+	// Listen before replaying, so no events are lost in between
+	listener := e.eventEmitter.SubscribeUsers(c.GetString("service_id"), &platformId)
+	defer e.eventEmitter.UnsubscribeUsers(listener)
+
+	stream := newSSEStream(c, params.Since)
+	for stream.replaying() {
+		user, err := e.eventEmitter.NextEvent(stream.lastEventID, &platformId)
+		if err != nil {
+			zap.L().Error("unable to replay events", zap.Error(err))
+			return
+		}
+		if user == nil {
+			break
+		}
+		if err = stream.send(sseEventUser, user.EventID, user); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(config.Config().StreamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case user := <-listener.C():
+			err = stream.send(sseEventUser, user.EventID, user)
+		case <-heartbeat.C:
+			err = stream.heartbeat()
+		case <-c.Request.Context().Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// (GET /v1/verification/platform/{platform_id}/users/updates/stream)
+func (e *VerificationEndpoint) GetVerificationPlatformUserUpdatesStream(c *gin.Context, platformId api.PlatformId, params api.GetVerificationPlatformUserUpdatesStreamParams) {
+	var statuses []api.Status
+	if params.Status != nil {
+		statuses = *params.Status
+	}
+
+	// Listen before replaying, so no events are lost in between
+	listener := e.eventEmitter.SubscribeStatuses(c.GetString("service_id"), &platformId, params.World, statuses)
+	defer e.eventEmitter.UnsubscribeStatuses(listener)
+
+	stream := newSSEStream(c, params.Since)
+	for stream.replaying() {
+		user, err := e.eventEmitter.NextEvent(stream.lastEventID, &platformId)
+		if err != nil {
+			zap.L().Error("unable to replay events", zap.Error(err))
+			return
+		}
+		if user == nil {
+			break
+		}
+		status := e.eventEmitter.UserStatus(c.GetString("service_id"), user, params.World, &platformId)
+		if !listener.Matches(status) {
+			stream.lastEventID = *user.EventID
+			continue
+		}
+		if err = stream.send(sseEventVerificationStatus, status.EventID, status); err != nil {
+			return
+		}
+	}
+
+	heartbeat := time.NewTicker(config.Config().StreamHeartbeatInterval)
+	defer heartbeat.Stop()
+	for {
+		var err error
+		select {
+		case status := <-listener.C():
+			err = stream.send(sseEventVerificationStatus, status.EventID, status)
+		case <-heartbeat.C:
+			err = stream.heartbeat()
+		case <-c.Request.Context().Done():
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
@@ -5,33 +5,38 @@ import (
 	"net/http"
 	"time"
 
-	"github.com/MrGunflame/gw2api"
 	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/config"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
 	"github.com/vennekilde/gw2verify/v2/pkg/history"
+	"github.com/vennekilde/gw2verify/v2/pkg/ratelimit"
 	"github.com/vennekilde/gw2verify/v2/pkg/sync"
 	"github.com/vennekilde/gw2verify/v2/pkg/verify"
 )
 
 type VerificationEndpoint struct {
-	verification *verify.Verification
-	worlds       *verify.Worlds
-	statistics   *history.Statistics
-	eventEmitter *verify.EventEmitter
-	syncher      *sync.Service
-	banService   *verify.BanService
+	verification  *verify.Verification
+	worlds        *verify.Worlds
+	statistics    *history.Statistics
+	eventEmitter  *verify.EventEmitter
+	syncher       *sync.Service
+	banService    *verify.BanService
+	unlinkService *verify.UnlinkService
+	mergeService  *verify.MergeService
 }
 
-func NewVerificationEndpoint(verification *verify.Verification, worlds *verify.Worlds, statistics *history.Statistics, eventEmitter *verify.EventEmitter, syncher *sync.Service, banService *verify.BanService) *VerificationEndpoint {
+func NewVerificationEndpoint(verification *verify.Verification, worlds *verify.Worlds, statistics *history.Statistics, eventEmitter *verify.EventEmitter, syncher *sync.Service, banService *verify.BanService, unlinkService *verify.UnlinkService, mergeService *verify.MergeService) *VerificationEndpoint {
 	return &VerificationEndpoint{
-		verification: verification,
-		worlds:       worlds,
-		statistics:   statistics,
-		eventEmitter: eventEmitter,
-		syncher:      syncher,
-		banService:   banService,
+		verification:  verification,
+		worlds:        worlds,
+		statistics:    statistics,
+		eventEmitter:  eventEmitter,
+		syncher:       syncher,
+		banService:    banService,
+		unlinkService: unlinkService,
+		mergeService:  mergeService,
 	}
 }
 
@@ -40,13 +45,49 @@ func (e *VerificationEndpoint) GetVerificationPlatformUserUpdates(c *gin.Context
 	ticker := time.NewTicker(120 * time.Second)
 	defer func() { ticker.Stop() }()
 
-	ch := e.eventEmitter.GetStatusListener(c.GetString("service_id"), &platformId, params.World)
+	var statuses []api.Status
+	if params.Status != nil {
+		statuses = *params.Status
+	}
+
+	// Listen before replaying, so no events are lost in between
+	listener := e.eventEmitter.SubscribeStatuses(c.GetString("service_id"), &platformId, params.World, statuses)
+	defer e.eventEmitter.UnsubscribeStatuses(listener)
 
-	select {
-	case event := <-ch:
-		c.JSON(http.StatusOK, event)
-	case <-ticker.C:
-		c.Status(http.StatusRequestTimeout)
+	// Replay missed events, if any
+	var cursor int64
+	if params.Since != nil {
+		cursor = *params.Since
+		for {
+			user, err := e.eventEmitter.NextEvent(cursor, &platformId)
+			if err != nil {
+				ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+				return
+			}
+			if user == nil {
+				break
+			}
+			cursor = *user.EventID
+			status := e.eventEmitter.UserStatus(c.GetString("service_id"), user, params.World, &platformId)
+			if listener.Matches(status) {
+				c.JSON(http.StatusOK, status)
+				return
+			}
+		}
+	}
+
+	for {
+		select {
+		case event := <-listener.C():
+			// Skip events that have already been replayed
+			if event.EventID != nil && *event.EventID <= cursor {
+				continue
+			}
+			c.JSON(http.StatusOK, event)
+		case <-ticker.C:
+			c.Status(http.StatusRequestTimeout)
+		}
+		return
 	}
 }
 
@@ -63,13 +104,103 @@ func (e *VerificationEndpoint) GetVerificationPlatformUserStatus(c *gin.Context,
 		return
 	}
 
-	status := api.VerificationStatus{
-		Ban:    verify.GetActiveBan(user.Bans),
-		Status: e.verification.Status(params.World, &user),
-	}
+	status := e.verification.VerificationStatus(c.GetString("service_id"), params.World, &user)
 	c.JSON(http.StatusOK, &status)
 }
 
+// (GET /v1/verification/platform/{platform_id}/users)
+func (e *VerificationEndpoint) GetVerificationPlatformUsers(c *gin.Context, platformId api.PlatformId, params api.GetVerificationPlatformUsersParams) {
+	limit := pageLimit(params.Limit, 500)
+	offset := pageOffset(params.Offset)
+
+	// Paginate over the platform links, so every link is returned exactly once
+	var links []orm.PlatformLink
+	err := orm.DB().NewSelect().
+		Model(&links).
+		Where("platform_id = ?", platformId).
+		Order("platform_user_id").
+		Limit(limit).
+		Offset(offset).
+		Scan(c)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	statuses, err := e.platformUserStatuses(c, c.GetString("service_id"), links, params.World)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+	c.JSON(http.StatusOK, &statuses)
+}
+
+// (POST /v1/verification/platform/{platform_id}/users)
+func (e *VerificationEndpoint) PostVerificationPlatformUsers(c *gin.Context, platformId api.PlatformId, params api.PostVerificationPlatformUsersParams) {
+	var reqBody api.PostVerificationPlatformUsersJSONRequestBody
+	err := c.Bind(&reqBody)
+	if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	statuses := []api.VerificationStatus{}
+	if len(reqBody) > 0 {
+		var links []orm.PlatformLink
+		err = orm.DB().NewSelect().
+			Model(&links).
+			Where("platform_id = ? AND platform_user_id IN (?)", platformId, bun.In(reqBody)).
+			Order("platform_user_id").
+			Scan(c)
+		if err != nil {
+			ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+			return
+		}
+		statuses, err = e.platformUserStatuses(c, c.GetString("service_id"), links, params.World)
+		if err != nil {
+			ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+			return
+		}
+	}
+
+	c.JSON(http.StatusOK, &statuses)
+}
+
+// platformUserStatuses returns the verification status of each of the platform links, in the same order.
+// The users of the links are loaded separately, and each user is only evaluated once
+func (e *VerificationEndpoint) platformUserStatuses(ctx context.Context, serviceUUID string, links []orm.PlatformLink, world int) ([]api.VerificationStatus, error) {
+	statuses := []api.VerificationStatus{}
+	if len(links) == 0 {
+		return statuses, nil
+	}
+
+	userIDs := make([]int64, 0, len(links))
+	for i := range links {
+		userIDs = append(userIDs, links[i].UserID)
+	}
+	var users []api.User
+	err := orm.QueryGetUsers(orm.DB(), &users).
+		Where("\"user\".id IN (?)", bun.In(userIDs)).
+		Scan(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	evaluated := make(map[int64]api.VerificationStatus, len(users))
+	for i := range users {
+		evaluated[users[i].Id] = e.verification.VerificationStatus(serviceUUID, world, &users[i])
+	}
+	for i := range links {
+		status, ok := evaluated[links[i].UserID]
+		if !ok {
+			continue
+		}
+		status.PlatformLink = &links[i].PlatformLink
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
 // (POST /v1/verification/platform/{platform_id}/users/{platform_user_id}/refresh)
 func (e *VerificationEndpoint) PostVerificationPlatformUserRefresh(c *gin.Context, platformId api.PlatformId, platformUserId api.PlatformUserId, params api.PostVerificationPlatformUserRefreshParams) {
 	tx, err := orm.DB().BeginTx(c, nil)
@@ -94,13 +225,22 @@ func (e *VerificationEndpoint) PostVerificationPlatformUserRefresh(c *gin.Contex
 		return
 	}
 
-	gw2API := gw2api.New()
+	entry := platformUserAudit(tx, api.AuditActionRefresh, platformId, platformUserId)
+	entry.Before = auditAccounts(tx, &link.UserID)
+
+	gw2API := ratelimit.NewSession(api.GW2APIBudgetInteractive)
 	err = e.syncher.SynchronizeUser(tx, gw2API, link.UserID)
 	if err != nil {
 		ThrowReqError(c, err.Error(), err, http.StatusInternalServerError)
 		return
 	}
 
+	entry.After = auditAccounts(tx, &link.UserID)
+	if err = recordAudit(c, tx, entry); err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
@@ -158,6 +298,18 @@ func (e *VerificationEndpoint) PutVerificationPlatformUserTemporary(c *gin.Conte
 				ThrowReqError(c, "Currently not linked with any other servers", nil, http.StatusBadRequest)
 				return
 			}
+		} else if *reqBody.AccessType == api.WVW_TEAM {
+			// Grant WvW team temporary access
+			team, err := e.worlds.GetWorldTeam(params.World)
+			if err != nil {
+				ThrowReqError(c, "unable to get world team", nil, http.StatusInternalServerError)
+				return
+			}
+			if team == 0 {
+				ThrowReqError(c, "Currently not able to determine the WvW team of the world", nil, http.StatusBadRequest)
+				return
+			}
+			world = team
 		} else {
 			ThrowReqError(c, "Invalid AccessType", nil, http.StatusBadRequest)
 			return
@@ -191,6 +343,22 @@ func (e *VerificationEndpoint) PutVerificationPlatformUserTemporary(c *gin.Conte
 		return
 	}
 
+	err = recordAudit(c, tx, api.AuditEntry{
+		Action:         api.AuditActionTemporaryAccess,
+		UserID:         &user.Id,
+		PlatformID:     &platformId,
+		PlatformUserID: &platformUserId,
+		After: api.EphemeralAssociation{
+			AccessType: reqBody.AccessType,
+			World:      &world,
+			Until:      &until,
+		},
+	})
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
 	err = tx.Commit()
 	if err != nil {
 		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
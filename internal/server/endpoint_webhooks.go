@@ -0,0 +1,132 @@
+package server
+
+import (
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+// (GET /v1/services/{service_uuid}/webhooks)
+func (e *Endpoints) GetServiceWebhooks(c *gin.Context, serviceUuid api.ServiceUuid) {
+	webhooks, err := verify.GetServiceWebhooks(orm.DB(), serviceUuid)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &webhooks)
+}
+
+// (POST /v1/services/{service_uuid}/webhooks)
+func (e *Endpoints) PostServiceWebhook(c *gin.Context, serviceUuid api.ServiceUuid) {
+	var reqBody api.Webhook
+	// decode request
+	err := c.Bind(&reqBody)
+	if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	if err = verify.ValidateWebhook(&reqBody); err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadRequest)
+		return
+	}
+
+	err = auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		if err = verify.CreateWebhook(tx, serviceUuid, &reqBody); err != nil {
+			return entry, err
+		}
+		entry = subjectAudit(api.AuditActionWebhookCreate, idSubject(reqBody.ID))
+		entry.After = auditWebhook(reqBody)
+		return entry, nil
+	})
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusCreated, &reqBody)
+}
+
+// (DELETE /v1/services/{service_uuid}/webhooks/{webhook_id})
+func (e *Endpoints) DeleteServiceWebhook(c *gin.Context, serviceUuid api.ServiceUuid, webhookId api.WebhookId) {
+	err := auditedTx(c, func(tx bun.Tx) (entry api.AuditEntry, err error) {
+		before, err := verify.GetServiceWebhook(tx, serviceUuid, webhookId)
+		if err != nil {
+			return entry, err
+		}
+		if err = verify.DeleteServiceWebhook(tx, serviceUuid, webhookId); err != nil {
+			return entry, err
+		}
+		entry = subjectAudit(api.AuditActionWebhookDelete, idSubject(webhookId))
+		entry.Before = auditWebhook(before.Webhook)
+		return entry, nil
+	})
+	if err == verify.ErrWebhookNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.Status(http.StatusNoContent)
+}
+
+// (POST /v1/services/{service_uuid}/webhooks/{webhook_id}/ping)
+func (e *Endpoints) PostServiceWebhookPing(c *gin.Context, serviceUuid api.ServiceUuid, webhookId api.WebhookId) {
+	err := e.webhooks.Ping(serviceUuid, webhookId)
+	if err == verify.ErrWebhookNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadGateway)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// (GET /v1/services/{service_uuid}/webhooks/{webhook_id}/dead-letters)
+func (e *Endpoints) GetServiceWebhookDeadLetters(c *gin.Context, serviceUuid api.ServiceUuid, webhookId api.WebhookId) {
+	webhook, err := verify.GetServiceWebhook(orm.DB(), serviceUuid, webhookId)
+	if err == verify.ErrWebhookNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	deadLetters, err := verify.GetDeadLetters(orm.DB(), webhook.ID)
+	if err != nil {
+		ThrowReqError(c, err.Error(), nil, http.StatusInternalServerError)
+		return
+	}
+
+	c.JSON(http.StatusOK, &deadLetters)
+}
+
+// (POST /v1/services/{service_uuid}/webhooks/{webhook_id}/dead-letters/{delivery_id}/retry)
+func (e *Endpoints) PostServiceWebhookDeadLetterRetry(c *gin.Context, serviceUuid api.ServiceUuid, webhookId api.WebhookId, deliveryId api.DeliveryId) {
+	err := e.webhooks.RetryDeadLetter(serviceUuid, webhookId, deliveryId)
+	if err == verify.ErrWebhookNotFound || err == verify.ErrDeadLetterNotFound {
+		c.Status(http.StatusNotFound)
+		return
+	} else if err != nil {
+		ThrowReqError(c, err.Error(), err, http.StatusBadGateway)
+		return
+	}
+
+	c.Status(http.StatusOK)
+}
+
+// auditWebhook returns the webhook as recorded in the audit log, without its secret
+func auditWebhook(webhook api.Webhook) api.Webhook {
+	webhook.Secret = nil
+	return webhook
+}
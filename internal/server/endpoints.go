@@ -7,6 +7,7 @@ import (
 	"github.com/gin-gonic/gin"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/pkg/privacy"
 	"github.com/vennekilde/gw2verify/v2/pkg/verify"
 	"go.uber.org/zap"
 )
@@ -14,14 +15,37 @@ import (
 // Endpoints is responsible for handling all REST requests related to the service
 type Endpoints struct {
 	*VerificationEndpoint
+	webhooks *verify.WebhookDispatcher
+	privacy  *privacy.Service
 }
 
-func NewEndpoints(verificationEndpoint *VerificationEndpoint) *Endpoints {
+func NewEndpoints(verificationEndpoint *VerificationEndpoint, webhooks *verify.WebhookDispatcher, privacy *privacy.Service) *Endpoints {
 	return &Endpoints{
 		VerificationEndpoint: verificationEndpoint,
+		webhooks:             webhooks,
+		privacy:              privacy,
 	}
 }
 
+// maxPageLimit is the largest page size of paginated endpoints, as documented in the api spec
+const maxPageLimit = 1000
+
+// pageLimit returns the requested page size clamped to between 1 and maxPageLimit, or the default if none was requested
+func pageLimit(limit *int, defaultLimit int) int {
+	if limit == nil {
+		return defaultLimit
+	}
+	return min(max(*limit, 1), maxPageLimit)
+}
+
+// pageOffset returns the requested page offset, which is never negative
+func pageOffset(offset *int) int {
+	if offset == nil {
+		return 0
+	}
+	return max(*offset, 0)
+}
+
 func ThrowReqError(c *gin.Context, errorMsg string, userErr error, statusCode int) {
 	jsonErr := make(map[string]string)
 	jsonErr["error"] = errorMsg
@@ -0,0 +1,46 @@
+package server
+
+import "testing"
+
+func TestPageLimit(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name  string
+		limit *int
+		want  int
+	}{
+		{"default", nil, 100},
+		{"requested", intPtr(50), 50},
+		{"maximum", intPtr(maxPageLimit), maxPageLimit},
+		{"above maximum", intPtr(maxPageLimit + 1), maxPageLimit},
+		{"zero", intPtr(0), 1},
+		{"negative", intPtr(-10), 1},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := pageLimit(test.limit, 100); got != test.want {
+				t.Errorf("pageLimit() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestPageOffset(t *testing.T) {
+	intPtr := func(i int) *int { return &i }
+	tests := []struct {
+		name   string
+		offset *int
+		want   int
+	}{
+		{"default", nil, 0},
+		{"requested", intPtr(200), 200},
+		{"negative", intPtr(-1), 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := pageOffset(test.offset); got != test.want {
+				t.Errorf("pageOffset() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
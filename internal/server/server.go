@@ -24,6 +24,7 @@ func NewRESTServer(endpoints *Endpoints) *RESTServer {
 
 	// AuthN middleware for handling JWT tokens
 	authNMiddleware := NewTokenMiddleware()
+	go authNMiddleware.ListenForRevocations()
 
 	// Openapi middleware for ensuring requests conform to the openapi spec
 	swagger, err := api.GetSwagger()
@@ -2,6 +2,8 @@ package server
 
 import (
 	"context"
+	"crypto/sha256"
+	"encoding/hex"
 	"errors"
 	"net/http"
 	"time"
@@ -10,15 +12,18 @@ import (
 	"github.com/getkin/kin-openapi/openapi3filter"
 	"github.com/gin-gonic/gin"
 	"github.com/patrickmn/go-cache"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/services"
 	"go.uber.org/zap"
 )
 
-type Service struct {
-	Uuid   string
-	Name   string
-	ApiKey string
-}
+// Errors raised.
+var (
+	ErrMissingScope   = errors.New("service is missing the scope required by the operation")
+	ErrForeignService = errors.New("service is not permitted to access another service")
+)
 
 type TokenMiddleware struct {
 	serviceCache *cache.Cache
@@ -35,64 +40,118 @@ func NewTokenMiddleware() *TokenMiddleware {
 // TokenRequestValidator validates that the Token provided in the request Authorization header is valid
 func (m *TokenMiddleware) TokenRequestValidator(c *gin.Context) {
 	bearer := c.GetHeader("Authorization")
-	authenticated, serviceID := m.checkBearer(bearer)
-	if !authenticated {
+	service := m.checkBearer(bearer)
+	if service == nil {
 		zap.L().Warn("unable to verify token from request",
 			zap.String("request uri", c.Request.RequestURI),
-			zap.String("remote addr", c.Request.RemoteAddr),
-			zap.String("bearer", bearer))
+			zap.String("remote addr", c.Request.RemoteAddr))
 		c.AbortWithStatus(http.StatusForbidden)
+		return
 	}
 	gctx := c.Value(middleware.GinContextKey).(*gin.Context)
-	gctx.Set("service_id", serviceID)
+	setService(gctx, service)
 }
 
 // TokenRequestValidator validates that the Token provided in the request Authorization header is valid
 func (m *TokenMiddleware) OpenapiAuthenticator(c context.Context, input *openapi3filter.AuthenticationInput) error {
 	bearer := input.RequestValidationInput.Request.Header.Get("Authorization")
-	authenticated, serviceID := m.checkBearer(bearer)
-	if !authenticated {
+	service := m.checkBearer(bearer)
+	if service == nil {
 		zap.L().Warn("unable to verify token from request",
 			zap.String("request uri", input.RequestValidationInput.Request.RequestURI),
-			zap.String("remote addr", input.RequestValidationInput.Request.RemoteAddr),
-			zap.String("bearer", bearer))
+			zap.String("remote addr", input.RequestValidationInput.Request.RemoteAddr))
 		return errors.New("unauthorized")
 	}
+
+	// Ensure the service has been granted the scopes the operation requires
+	for _, scope := range input.Scopes {
+		if !services.HasScope(service.Scopes, api.Scope(scope)) {
+			zap.L().Warn("service is missing scope",
+				zap.String("request uri", input.RequestValidationInput.Request.RequestURI),
+				zap.String("service", service.UUID),
+				zap.String("scope", scope))
+			return ErrMissingScope
+		}
+	}
+	// Services may only manage their own resources
+	if serviceUUID, ok := input.RequestValidationInput.PathParams["service_uuid"]; ok && serviceUUID != service.UUID && !services.HasScope(service.Scopes, api.ScopeAdmin) {
+		zap.L().Warn("service is not permitted to access another service",
+			zap.String("request uri", input.RequestValidationInput.Request.RequestURI),
+			zap.String("service", service.UUID))
+		return ErrForeignService
+	}
+
 	gctx := c.Value(middleware.GinContextKey).(*gin.Context)
-	gctx.Set("service_id", serviceID)
+	setService(gctx, service)
 	return nil
 }
 
-// TokenRequestValidator validates that the Token provided in the request Authorization header is valid
-func (m *TokenMiddleware) checkBearer(bearer string) (permitted bool, serviceID string) {
-	ctx := context.TODO()
+// setService stores the authenticated service in the request context
+func setService(c *gin.Context, service *api.Service) {
+	c.Set("service_id", service.UUID)
+	c.Set("service_scopes", service.Scopes)
+}
 
+// HasScope checks if the service of the request has been granted the scope
+func HasScope(c *gin.Context, scope api.Scope) bool {
+	scopes, _ := c.Value("service_scopes").([]api.Scope)
+	return services.HasScope(scopes, scope)
+}
+
+// checkBearer returns the service the bearer belongs to, or nil if the bearer is not valid
+func (m *TokenMiddleware) checkBearer(bearer string) *api.Service {
 	// Remove bearer prefix
 	if len(bearer) <= 7 {
-		return false, serviceID
+		return nil
 	}
 	bearer = bearer[7:]
 
-	// Check if we have cached the bearer
-	serviceIDInt, ok := m.serviceCache.Get(bearer)
+	// Check if we have cached the bearer. The cache is keyed by a hash, so keys are not kept in memory
+	cacheKey := bearerCacheKey(bearer)
+	cached, ok := m.serviceCache.Get(cacheKey)
 	if ok {
-		serviceID = serviceIDInt.(string)
-		return true, serviceID
+		return cached.(*api.Service)
 	}
 
-	var service Service
-	err := orm.DB().NewSelect().
-		Model(&service).
-		Where("api_key = ?", bearer).
-		Scan(ctx)
-	if err != nil {
-		zap.L().Error("unable to verify token", zap.String("bearer", bearer), zap.Error(err))
-		return false, serviceID
+	service, err := services.Authenticate(orm.DB(), bearer)
+	if err == services.ErrInvalidAPIKey {
+		return nil
+	} else if err != nil {
+		zap.L().Error("unable to verify token", zap.String("prefix", services.LookupPrefix(bearer)), zap.Error(err))
+		return nil
 	}
 
-	if service.Uuid != "" {
-		m.serviceCache.Add(bearer, service.Uuid, cache.DefaultExpiration)
+	m.serviceCache.Add(cacheKey, service, cache.DefaultExpiration)
+	return service
+}
+
+// bearerCacheKey returns the key a bearer is cached under
+func bearerCacheKey(bearer string) string {
+	hash := sha256.Sum256([]byte(bearer))
+	return hex.EncodeToString(hash[:])
+}
+
+// ListenForRevocations evicts cached API keys of services as soon as the service is changed, has its key rotated or is disabled
+// Note: this method will block the calling goroutine indefinitely
+func (m *TokenMiddleware) ListenForRevocations() {
+	ctx := context.Background()
+	ln := pgdriver.NewListener(orm.DB())
+	defer ln.Close()
+	if err := ln.Listen(ctx, services.RevocationChannel); err != nil {
+		zap.L().Panic("unable to listen for revoked service keys", zap.Error(err))
 	}
 
-	return service.ApiKey == bearer, service.Uuid
+	for notification := range ln.Channel() {
+		m.EvictService(notification.Payload)
+	}
+}
+
+// EvictService removes all cached API keys belonging to the service
+func (m *TokenMiddleware) EvictService(serviceUUID string) {
+	for cacheKey, item := range m.serviceCache.Items() {
+		if item.Object.(*api.Service).UUID == serviceUUID {
+			m.serviceCache.Delete(cacheKey)
+		}
+	}
+	zap.L().Info("evicted cached service keys", zap.String("service", serviceUUID))
 }
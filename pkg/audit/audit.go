@@ -0,0 +1,80 @@
+package audit
+
+import (
+	"context"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// AdminCLI is recorded as the service of calls made with the gw2verify-admin command line tool
+const AdminCLI = "gw2verify-admin"
+
+// Filter limits which audit entries are returned
+type Filter struct {
+	ServiceUUID    *string
+	UserID         *int64
+	PlatformID     *int
+	PlatformUserID *string
+	Action         *api.AuditAction
+	Limit          int
+	Offset         int
+}
+
+// Record persists an audit entry of a mutating call
+func Record(idb bun.IDB, entry *api.AuditEntry) error {
+	ctx := context.Background()
+	_, err := idb.NewInsert().
+		Model(entry).
+		Returning("id, db_created").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// ServiceEntry returns the audit entry of a change to a service. API keys are never recorded
+func ServiceEntry(action api.AuditAction, before *api.Service, after *api.Service) api.AuditEntry {
+	entry := api.AuditEntry{
+		Action:  action,
+		Subject: &after.UUID,
+	}
+	if before != nil {
+		beforeCopy := *before
+		beforeCopy.APIKey = nil
+		entry.Before = beforeCopy
+	}
+	afterCopy := *after
+	afterCopy.APIKey = nil
+	entry.After = afterCopy
+	return entry
+}
+
+// GetEntries returns the audit entries matching the filter, newest first
+func GetEntries(idb bun.IDB, filter Filter) (entries []api.AuditEntry, err error) {
+	ctx := context.Background()
+	query := idb.NewSelect().
+		Model(&entries).
+		Order("id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+	if filter.ServiceUUID != nil {
+		query.Where("service_uuid = ?", *filter.ServiceUUID)
+	}
+	if filter.UserID != nil {
+		query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.PlatformID != nil {
+		query.Where("platform_id = ?", *filter.PlatformID)
+	}
+	if filter.PlatformUserID != nil {
+		query.Where("platform_user_id = ?", *filter.PlatformUserID)
+	}
+	if filter.Action != nil {
+		query.Where("action = ?", *filter.Action)
+	}
+	err = query.Scan(ctx)
+	if entries == nil {
+		entries = []api.AuditEntry{}
+	}
+	return entries, errors.WithStack(err)
+}
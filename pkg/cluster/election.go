@@ -0,0 +1,108 @@
+package cluster
+
+import (
+	"context"
+	"hash/fnv"
+	"sync/atomic"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"go.uber.org/zap"
+)
+
+// electionInterval is how often a candidate tries to become leader, and how often the leader checks it still holds the lock
+const electionInterval = 10 * time.Second
+
+// Election elects a single leader among the instances sharing the database, so jobs that must only run once are not run by every instance.
+// Leadership is held as a postgres advisory lock on a dedicated connection, and is released if the connection is lost
+type Election struct {
+	name   string
+	key    int64
+	leader atomic.Bool
+}
+
+func NewElection(name string) *Election {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return &Election{
+		name: name,
+		key:  int64(hash.Sum64()),
+	}
+}
+
+// IsLeader reports whether this instance is currently the leader
+func (e *Election) IsLeader() bool {
+	return e.leader.Load()
+}
+
+// SleepWhileLeader sleeps for the duration, checking leadership every election interval.
+// It returns false as soon as leadership is lost, so long sleeps do not outlive the leadership
+func (e *Election) SleepWhileLeader(d time.Duration) bool {
+	deadline := time.Now().Add(d)
+	for {
+		if !e.IsLeader() {
+			return false
+		}
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return true
+		}
+		time.Sleep(min(remaining, electionInterval))
+	}
+}
+
+// Start campaigns for leadership and holds it for as long as the connection is alive
+// Note: this method will block the calling goroutine indefinitely
+func (e *Election) Start() {
+	for {
+		if err := e.campaign(); err != nil {
+			zap.L().Error("unable to campaign for leadership", zap.String("election", e.name), zap.Error(err))
+		}
+		time.Sleep(electionInterval)
+	}
+}
+
+// campaign waits until the lock is acquired and returns once leadership is lost
+func (e *Election) campaign() error {
+	ctx := context.Background()
+	conn, err := orm.DB().Conn(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	defer conn.Close()
+
+	for {
+		var acquired bool
+		err = conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock(?)", e.key).Scan(&acquired)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		if acquired {
+			break
+		}
+		time.Sleep(electionInterval)
+	}
+
+	e.leader.Store(true)
+	zap.L().Info("elected leader", zap.String("election", e.name))
+	defer func() {
+		e.leader.Store(false)
+		zap.L().Warn("lost leadership", zap.String("election", e.name))
+	}()
+
+	// The lock is held for as long as the session lives
+	for {
+		time.Sleep(electionInterval)
+		if err = ping(ctx, conn); err != nil {
+			return err
+		}
+	}
+}
+
+func ping(ctx context.Context, conn bun.Conn) error {
+	pingCtx, cancel := context.WithTimeout(ctx, electionInterval)
+	defer cancel()
+	return errors.WithStack(conn.PingContext(pingCtx))
+}
@@ -0,0 +1,288 @@
+package privacy
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+	"go.uber.org/zap"
+)
+
+// BanPolicy decides which bans are kept when a user is erased
+type BanPolicy string
+
+const (
+	// BanPolicyDelete erases every ban of the user
+	BanPolicyDelete BanPolicy = "delete"
+	// BanPolicyKeepActive keeps the active bans, so erasure cannot be used to evade a ban.
+	// The kept bans stay attached to the gw2 accounts, and are copied to whoever links one of the accounts again
+	BanPolicyKeepActive BanPolicy = "keep_active"
+	// BanPolicyKeepAll keeps every ban, so the ban history of the gw2 accounts is intact if they are linked again
+	BanPolicyKeepAll BanPolicy = "keep_all"
+)
+
+// erasedPlatformUserID replaces the platform user id of anonymized records
+const erasedPlatformUserID = "erased"
+
+// Errors raised.
+var (
+	ErrInvalidBanPolicy = errors.New("erasure ban policy must be one of delete, keep_active or keep_all")
+)
+
+// Service erases users on request
+type Service struct {
+	em        *verify.EventEmitter
+	banPolicy BanPolicy
+}
+
+// NewService returns a new erasure service using the configured ban policy
+func NewService(em *verify.EventEmitter) (*Service, error) {
+	banPolicy := BanPolicy(config.Config().ErasureBanPolicy)
+	switch banPolicy {
+	case BanPolicyDelete, BanPolicyKeepActive, BanPolicyKeepAll:
+	default:
+		return nil, ErrInvalidBanPolicy
+	}
+	return &Service{
+		em:        em,
+		banPolicy: banPolicy,
+	}, nil
+}
+
+// Erase erases everything stored about the user the platform user is linked to, including the user's other platform identities.
+// Voice statistics and audit entries are anonymized instead, so aggregated statistics and the audit trail stay intact.
+// Properties stored without a platform are only erased if they belong to the service requesting the erasure.
+// Bans are kept according to the ban policy. If any bans are kept, the user itself is kept as an empty user holding the bans
+func (s *Service) Erase(idb bun.IDB, serviceUUID string, platformID int, platformUserID string) (*api.ErasureReport, error) {
+	ctx := context.Background()
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	userID, err := linkedUserID(tx, platformID, platformUserID)
+	if err != nil {
+		return nil, err
+	}
+	report := api.ErasureReport{
+		UserID: userID,
+	}
+
+	var links []orm.PlatformLink
+	err = tx.NewSelect().
+		Model(&links).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	report.PlatformLinks = len(links)
+
+	if err = anonymizePlatformUsers(tx, serviceUUID, userID, links); err != nil {
+		return nil, err
+	}
+
+	// Accounts cascade to their api keys, histories and achievements
+	res, err := tx.NewDelete().
+		Model((*api.Account)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	accounts, err := res.RowsAffected()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	report.Accounts = int(accounts)
+
+	// Event snapshots, webhook deliveries and merge records contain copies of the user
+	if err = deleteWebhookDeliveries(tx, userID, links); err != nil {
+		return nil, err
+	}
+	_, err = tx.NewDelete().
+		Model((*verify.Event)(nil)).
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	_, err = tx.NewDelete().
+		Model((*api.UserMerge)(nil)).
+		Where("source_user_id = ? OR target_user_id = ?", userID, userID).
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if err = s.eraseUser(tx, userID, &report); err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed = true
+
+	zap.L().Info("erased user",
+		zap.Int64("user id", userID),
+		zap.String("ban policy", string(s.banPolicy)),
+		zap.Int("platform links", report.PlatformLinks),
+		zap.Int("accounts", report.Accounts),
+		zap.Int("bans erased", report.BansErased),
+		zap.Int("bans kept", report.BansKept))
+
+	// Each platform identity is emitted on its own without an id, accounts or bans, so consumers revoke their access.
+	// The revocations are not persisted, as that would store the erased identities again
+	for _, link := range links {
+		s.em.EmitTransient(&api.User{
+			PlatformLinks: []api.PlatformLink{link.PlatformLink},
+		})
+	}
+
+	return &report, nil
+}
+
+// deleteWebhookDeliveries deletes the stored webhook deliveries of the user's events, along with any delivery whose body refers to the user or its platform identities
+func deleteWebhookDeliveries(idb bun.IDB, userID int64, links []orm.PlatformLink) error {
+	ctx := context.Background()
+	query := idb.NewDelete().
+		Model((*api.WebhookDelivery)(nil)).
+		WhereOr("event_id IN (?)", idb.NewSelect().
+			Model((*verify.Event)(nil)).
+			Column("seq").
+			Where("user_id = ?", userID)).
+		WhereOr("body @> ?::jsonb", fmt.Sprintf(`{"id":%d}`, userID)).
+		WhereOr("body @> ?::jsonb", fmt.Sprintf(`{"ban":{"user_id":%d}}`, userID))
+	for _, link := range links {
+		identity, err := json.Marshal(map[string]any{
+			"platform_id":      link.PlatformID,
+			"platform_user_id": link.PlatformUserID,
+		})
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		// User payloads list every platform link, verification status payloads hold a single one
+		query.WhereOr("body->'platform_links' @> ?::jsonb", "["+string(identity)+"]").
+			WhereOr("body->'platform_link' @> ?::jsonb", string(identity))
+	}
+	_, err := query.Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// anonymizePlatformUsers removes the platform user ids from voice statistics, properties and audit entries
+func anonymizePlatformUsers(idb bun.IDB, serviceUUID string, userID int64, links []orm.PlatformLink) error {
+	ctx := context.Background()
+	for _, link := range links {
+		_, err := idb.NewUpdate().
+			Table("voice_user_states").
+			Set("platform_user_id = ?", erasedPlatformUserID).
+			Where("platform_id = ? AND platform_user_id = ?", link.PlatformID, link.PlatformUserID).
+			Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		_, err = idb.NewDelete().
+			Model((*api.Property)(nil)).
+			Where(platformUserProperties, link.PlatformUserID, link.PlatformID, serviceUUID).
+			Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		_, err = idb.NewUpdate().
+			Model((*api.AuditEntry)(nil)).
+			Set("platform_user_id = ?", erasedPlatformUserID).
+			Where("platform_id = ? AND platform_user_id = ?", link.PlatformID, link.PlatformUserID).
+			Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// The state recorded before and after each call may contain the user's accounts and identities
+	_, err := idb.NewUpdate().
+		Model((*api.AuditEntry)(nil)).
+		Set("before = NULL, after = NULL").
+		Where("user_id = ?", userID).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// eraseUser removes the bans of the user that are not kept by the ban policy, along with the user itself if no bans are kept
+func (s *Service) eraseUser(idb bun.IDB, userID int64, report *api.ErasureReport) error {
+	ctx := context.Background()
+	if s.banPolicy != BanPolicyKeepAll {
+		query := idb.NewDelete().
+			Model((*orm.Ban)(nil)).
+			Where("user_id = ?", userID)
+		if s.banPolicy == BanPolicyKeepActive {
+			query.Where("until <= NOW() OR lifted_at IS NOT NULL")
+		}
+		res, err := query.Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		erased, err := res.RowsAffected()
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		report.BansErased = int(erased)
+	}
+
+	kept, err := idb.NewSelect().
+		Model((*orm.Ban)(nil)).
+		Where("user_id = ?", userID).
+		Count(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	report.BansKept = kept
+
+	if kept == 0 {
+		// Platform links, ephemeral associations and account links cascade
+		_, err = idb.NewDelete().
+			Model((*api.User)(nil)).
+			Where("id = ?", userID).
+			Exec(ctx)
+		return errors.WithStack(err)
+	}
+
+	// Keep the user as an empty user holding the bans. The accounts it has been linked to are kept only if they are banned,
+	// so the bans can still reach every identity of the accounts
+	for _, model := range []any{
+		(*orm.PlatformLink)(nil),
+		(*api.EphemeralAssociation)(nil),
+	} {
+		_, err = idb.NewDelete().
+			Model(model).
+			Where("user_id = ?", userID).
+			Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+	_, err = idb.NewDelete().
+		Model((*orm.AccountUser)(nil)).
+		Where("user_id = ?", userID).
+		Where("account_id NOT IN (?)", idb.NewSelect().
+			Model((*orm.Ban)(nil)).
+			Column("account_id").
+			Where("user_id = ? AND account_id IS NOT NULL", userID)).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
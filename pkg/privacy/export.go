@@ -0,0 +1,130 @@
+package privacy
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/audit"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+)
+
+// Export returns everything stored about the user the platform user is linked to
+// Unlike the regular user queries, expired accounts and associations are included as well.
+// Properties stored without a platform are only included if they belong to the service requesting the export
+func Export(idb bun.IDB, serviceUUID string, platformID int, platformUserID string) (*api.DataExport, error) {
+	ctx := context.Background()
+	userID, err := linkedUserID(idb, platformID, platformUserID)
+	if err != nil {
+		return nil, err
+	}
+
+	export := api.DataExport{
+		ExportedAt: time.Now(),
+	}
+	err = idb.NewSelect().
+		Model(&export.User).
+		Relation("Bans").
+		Relation("Accounts").
+		Relation("Accounts.ApiKeys").
+		Relation("PlatformLinks").
+		Relation("EphemeralAssociations").
+		Where(`"user".id = ?`, userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	accountIDs := userAccountIDs(&export.User)
+
+	export.Histories = []api.ExportedHistory{}
+	export.Achievements = []api.ExportedAchievement{}
+	if len(accountIDs) > 0 {
+		err = idb.NewSelect().
+			Model(&export.Histories).
+			ModelTableExpr("histories AS exported_history").
+			Where("account_id IN (?)", bun.In(accountIDs)).
+			Order("timestamp").
+			Scan(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		err = idb.NewSelect().
+			Model(&export.Achievements).
+			ModelTableExpr("achievements AS exported_achievement").
+			Where("account_id IN (?)", bun.In(accountIDs)).
+			Order("timestamp").
+			Scan(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+	}
+
+	export.VoiceStates = []api.ExportedVoiceState{}
+	export.Properties = []api.Property{}
+	for _, link := range export.User.PlatformLinks {
+		var voiceStates []api.ExportedVoiceState
+		err = idb.NewSelect().
+			Model(&voiceStates).
+			ModelTableExpr("voice_user_states AS exported_voice_state").
+			Where("platform_id = ? AND platform_user_id = ?", link.PlatformID, link.PlatformUserID).
+			Order("timestamp").
+			Scan(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		export.VoiceStates = append(export.VoiceStates, voiceStates...)
+
+		var properties []api.Property
+		err = idb.NewSelect().
+			Model(&properties).
+			Where(platformUserProperties, link.PlatformUserID, link.PlatformID, serviceUUID).
+			Scan(ctx)
+		if err != nil {
+			return nil, errors.WithStack(err)
+		}
+		export.Properties = append(export.Properties, properties...)
+	}
+
+	export.Merges, err = verify.GetUserMerges(idb, userID)
+	if err != nil {
+		return nil, err
+	}
+	export.AuditEntries, err = audit.GetEntries(idb, audit.Filter{UserID: &userID})
+	if err != nil {
+		return nil, err
+	}
+
+	return &export, nil
+}
+
+// platformUserProperties matches the properties of a platform user, given the platform user id, platform id and service uuid.
+// The subject of a property stored without a platform may be the id of a user on another platform,
+// so those are only matched for the service making the request
+const platformUserProperties = "subject = ? AND (platform_id = ? OR (platform_id IS NULL AND service_uuid = ?))"
+
+// linkedUserID returns the id of the user the platform user is linked to
+func linkedUserID(idb bun.IDB, platformID int, platformUserID string) (int64, error) {
+	ctx := context.Background()
+	var link orm.PlatformLink
+	err := idb.NewSelect().
+		Model(&link).
+		Where("platform_id = ? AND platform_user_id = ?", platformID, platformUserID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return 0, verify.ErrUserNotFound
+	}
+	return link.UserID, errors.WithStack(err)
+}
+
+func userAccountIDs(user *api.User) []string {
+	ids := make([]string, len(user.Accounts))
+	for i := range user.Accounts {
+		ids[i] = user.Accounts[i].ID
+	}
+	return ids
+}
@@ -0,0 +1,102 @@
+package ratelimit
+
+import (
+	"sync"
+	"time"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// Limiter is a token bucket shared by every gw2 api request of the process.
+// Part of the bucket is reserved for interactive requests, so background synchronization cannot starve them
+type Limiter struct {
+	mu      sync.Mutex
+	rate    float64
+	burst   float64
+	reserve float64
+	tokens  float64
+	last    time.Time
+	// pausedUntil is set when the gw2 api has rate limited a request, as its limit applies to every request
+	pausedUntil time.Time
+}
+
+// NewLimiter returns a full bucket refilled with rate tokens per second. Requests are not limited if the rate is zero
+func NewLimiter(rate float64, burst int, interactiveReserve int) *Limiter {
+	burst = max(burst, 1)
+	interactiveReserve = max(min(interactiveReserve, burst-1), 0)
+	return &Limiter{
+		rate:    rate,
+		burst:   float64(burst),
+		reserve: float64(interactiveReserve),
+		tokens:  float64(burst),
+		last:    time.Now(),
+	}
+}
+
+// Wait blocks until a request of the budget may be made and returns how long it waited
+func (l *Limiter) Wait(budget api.GW2APIBudget) time.Duration {
+	start := time.Now()
+	for {
+		delay := l.take(budget)
+		if delay <= 0 {
+			return time.Since(start)
+		}
+		time.Sleep(delay)
+	}
+}
+
+// take takes a token if available, otherwise it returns how long to wait before trying again
+func (l *Limiter) take(budget api.GW2APIBudget) time.Duration {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return l.pausedUntil.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+	l.refill(now)
+
+	floor := 0.0
+	if budget != api.GW2APIBudgetInteractive {
+		floor = l.reserve
+	}
+	if l.tokens-1 >= floor {
+		l.tokens--
+		return 0
+	}
+	return time.Duration((floor + 1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// Pause stops all requests until the time, and empties the bucket so requests resume gradually
+func (l *Limiter) Pause(until time.Time) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if until.After(l.pausedUntil) {
+		l.pausedUntil = until
+	}
+	l.tokens = 0
+	l.last = l.pausedUntil
+}
+
+// Available returns the amount of tokens currently in the bucket
+func (l *Limiter) Available() float64 {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Before(l.pausedUntil) {
+		return 0
+	}
+	l.refill(now)
+	return l.tokens
+}
+
+// refill adds the tokens accumulated since the last refill. Must be called while holding the lock
+func (l *Limiter) refill(now time.Time) {
+	if now.After(l.last) {
+		l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+		l.last = now
+	}
+}
@@ -0,0 +1,196 @@
+package ratelimit
+
+import (
+	"io"
+	mrand "math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/MrGunflame/gw2api"
+	"github.com/pkg/errors"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"go.uber.org/zap"
+)
+
+// gw2APIHost is the host of the gw2 api
+const gw2APIHost = "api.guildwars2.com"
+
+// budgetHostSuffix marks the budget of a session in the host of its endpoint, e.g. sync.gw2api.invalid.
+// The gw2api library offers no way to pass information along with a request, so the transport reads the budget from the host
+// and sends the request to the gw2 api instead
+const budgetHostSuffix = ".gw2api.invalid"
+
+// Errors raised.
+var (
+	ErrRateLimited = errors.New("the gw2 api is rate limiting requests")
+)
+
+// RateLimitedError is returned when a request is still rate limited after all retries
+type RateLimitedError struct {
+	// RetryAfter is how long the gw2 api asked to wait before retrying
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitedError) Error() string {
+	return ErrRateLimited.Error()
+}
+
+func (e *RateLimitedError) Is(target error) bool {
+	return target == ErrRateLimited
+}
+
+// Transport limits the rate of gw2 api requests, and retries requests that are rate limited or fail.
+// Requests to other hosts are passed through as is
+type Transport struct {
+	next       http.RoundTripper
+	limiter    *Limiter
+	usage      *Usage
+	maxRetries int
+	backoff    time.Duration
+}
+
+func NewTransport(next http.RoundTripper, limiter *Limiter) *Transport {
+	return &Transport{
+		next:       next,
+		limiter:    limiter,
+		usage:      NewUsage(),
+		maxRetries: max(config.Config().GW2APIMaxRetries, 0),
+		backoff:    config.Config().GW2APIRetryBackoff,
+	}
+}
+
+var defaultTransport *Transport
+
+// Install routes every gw2 api request made through http.DefaultClient, which the gw2api library uses, through a shared rate limiter
+func Install() {
+	conf := config.Config()
+	limiter := NewLimiter(conf.GW2APIRateLimit, conf.GW2APIBurst, conf.GW2APIInteractiveReserve)
+	next := http.DefaultClient.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	defaultTransport = NewTransport(next, limiter)
+	http.DefaultClient.Transport = defaultTransport
+}
+
+// NewSession returns a gw2api session whose requests are counted towards the budget
+func NewSession(budget api.GW2APIBudget) *gw2api.Session {
+	return gw2api.New().WithEndpointAPI("https://" + string(budget) + budgetHostSuffix)
+}
+
+// GetUsage returns the usage of the installed transport
+func GetUsage() *api.GW2APIUsage {
+	if defaultTransport == nil {
+		return &api.GW2APIUsage{Budgets: []api.GW2APIBudgetUsage{}}
+	}
+	return defaultTransport.Usage()
+}
+
+// Usage returns how much of the rate limit each budget has used
+func (t *Transport) Usage() *api.GW2APIUsage {
+	usage := t.usage.Snapshot()
+	usage.AvailableTokens = t.limiter.Available()
+	return usage
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	budget, ok := requestBudget(req)
+	if !ok {
+		return t.next.RoundTrip(req)
+	}
+	req = req.Clone(req.Context())
+	req.URL.Host = gw2APIHost
+	req.Host = gw2APIHost
+	// Only requests without a body can be resent
+	retryable := req.Body == nil || req.Body == http.NoBody
+
+	counters := t.usage.budget(budget)
+	for attempt := 0; ; attempt++ {
+		counters.addWait(t.limiter.Wait(budget))
+		counters.requests.Add(1)
+		resp, err := t.next.RoundTrip(req)
+
+		var retryAfter time.Duration
+		switch {
+		case err != nil:
+		case resp.StatusCode == http.StatusTooManyRequests:
+			counters.rateLimited.Add(1)
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		case resp.StatusCode == http.StatusBadGateway, resp.StatusCode == http.StatusServiceUnavailable, resp.StatusCode == http.StatusGatewayTimeout:
+			retryAfter = parseRetryAfter(resp.Header.Get("Retry-After"))
+		default:
+			return resp, nil
+		}
+
+		delay := t.retryDelay(attempt, retryAfter)
+		if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+			// The rate limit of the gw2 api applies to every request
+			t.limiter.Pause(time.Now().Add(delay))
+		}
+
+		if attempt >= t.maxRetries || !retryable {
+			counters.failures.Add(1)
+			if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+				drain(resp)
+				zap.L().Warn("gw2 api request rate limited", zap.String("budget", string(budget)), zap.Int("attempts", attempt+1))
+				return nil, &RateLimitedError{RetryAfter: delay}
+			}
+			return resp, err
+		}
+		if resp != nil {
+			drain(resp)
+		}
+		counters.retries.Add(1)
+		time.Sleep(delay)
+	}
+}
+
+// retryDelay returns how long to wait before retrying the attempt. The gw2 api is obeyed if it said how long to wait,
+// otherwise the delay backs off exponentially with up to 50% jitter, so requests do not retry in lockstep
+func (t *Transport) retryDelay(attempt int, retryAfter time.Duration) time.Duration {
+	if retryAfter > 0 {
+		return retryAfter
+	}
+	delay := t.backoff << attempt
+	return delay + time.Duration(mrand.Int64N(int64(delay)/2+1))
+}
+
+// requestBudget returns the budget of a gw2 api request. Requests made directly to the gw2 api count as sync requests
+func requestBudget(req *http.Request) (api.GW2APIBudget, bool) {
+	host := req.URL.Hostname()
+	if host == gw2APIHost {
+		return api.GW2APIBudgetSync, true
+	}
+	budget, ok := strings.CutSuffix(host, budgetHostSuffix)
+	if !ok {
+		return "", false
+	}
+	switch api.GW2APIBudget(budget) {
+	case api.GW2APIBudgetInteractive, api.GW2APIBudgetSync:
+		return api.GW2APIBudget(budget), true
+	}
+	return api.GW2APIBudgetSync, true
+}
+
+// parseRetryAfter parses a Retry-After header given either in seconds or as a date. Zero if absent or invalid
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(max(seconds, 0)) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0)
+	}
+	return 0
+}
+
+// drain discards the body of a response that is not returned, so the connection can be reused
+func drain(resp *http.Response) {
+	_, _ = io.Copy(io.Discard, resp.Body)
+	resp.Body.Close()
+}
@@ -0,0 +1,130 @@
+package ratelimit
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+func TestParseRetryAfter(t *testing.T) {
+	tests := []struct {
+		name  string
+		value string
+		min   time.Duration
+		max   time.Duration
+	}{
+		{"absent", "", 0, 0},
+		{"seconds", "5", 5 * time.Second, 5 * time.Second},
+		{"zero seconds", "0", 0, 0},
+		{"negative seconds", "-5", 0, 0},
+		{"date", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat), 58 * time.Second, time.Minute},
+		{"past date", time.Now().Add(-time.Minute).UTC().Format(http.TimeFormat), 0, 0},
+		{"invalid", "soon", 0, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := parseRetryAfter(test.value); got < test.min || got > test.max {
+				t.Errorf("parseRetryAfter(%q) = %s, want between %s and %s", test.value, got, test.min, test.max)
+			}
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	transport := &Transport{backoff: 100 * time.Millisecond}
+	tests := []struct {
+		name       string
+		attempt    int
+		retryAfter time.Duration
+		min        time.Duration
+		max        time.Duration
+	}{
+		{"first attempt", 0, 0, 100 * time.Millisecond, 150 * time.Millisecond},
+		{"second attempt", 1, 0, 200 * time.Millisecond, 300 * time.Millisecond},
+		{"fourth attempt", 3, 0, 800 * time.Millisecond, 1200 * time.Millisecond},
+		{"retry after", 3, 2 * time.Second, 2 * time.Second, 2 * time.Second},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			for range 100 {
+				if got := transport.retryDelay(test.attempt, test.retryAfter); got < test.min || got > test.max {
+					t.Fatalf("retryDelay() = %s, want between %s and %s", got, test.min, test.max)
+				}
+			}
+		})
+	}
+}
+
+// statusTransport replies to requests with the given status codes in order, repeating the last
+type statusTransport struct {
+	statuses []int
+	requests int
+	hosts    []string
+}
+
+func (st *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	status := st.statuses[min(st.requests, len(st.statuses)-1)]
+	st.requests++
+	st.hosts = append(st.hosts, req.URL.Host)
+	return &http.Response{
+		StatusCode: status,
+		Header:     http.Header{},
+		Body:       io.NopCloser(strings.NewReader("")),
+	}, nil
+}
+
+func TestTransportRoundTrip(t *testing.T) {
+	tests := []struct {
+		name         string
+		url          string
+		body         io.Reader
+		statuses     []int
+		wantStatus   int
+		wantErr      error
+		wantRequests int
+		wantHost     string
+	}{
+		{"success", "https://api.guildwars2.com/v2/account", nil, []int{http.StatusOK}, http.StatusOK, nil, 1, gw2APIHost},
+		{"budget host", "https://" + string(api.GW2APIBudgetInteractive) + budgetHostSuffix + "/v2/account", nil, []int{http.StatusOK}, http.StatusOK, nil, 1, gw2APIHost},
+		{"other host", "https://example.com", nil, []int{http.StatusServiceUnavailable}, http.StatusServiceUnavailable, nil, 1, "example.com"},
+		{"retried until success", "https://api.guildwars2.com/v2/account", nil, []int{http.StatusServiceUnavailable, http.StatusTooManyRequests, http.StatusOK}, http.StatusOK, nil, 3, gw2APIHost},
+		{"client errors are not retried", "https://api.guildwars2.com/v2/account", nil, []int{http.StatusUnauthorized}, http.StatusUnauthorized, nil, 1, gw2APIHost},
+		{"retries exhausted", "https://api.guildwars2.com/v2/account", nil, []int{http.StatusBadGateway}, http.StatusBadGateway, nil, 3, gw2APIHost},
+		{"rate limited", "https://api.guildwars2.com/v2/account", nil, []int{http.StatusTooManyRequests}, 0, ErrRateLimited, 3, gw2APIHost},
+		{"requests with a body are not retried", "https://api.guildwars2.com/v2/account", strings.NewReader("body"), []int{http.StatusServiceUnavailable}, http.StatusServiceUnavailable, nil, 1, gw2APIHost},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			next := &statusTransport{statuses: test.statuses}
+			transport := &Transport{
+				next:       next,
+				limiter:    NewLimiter(0, 1, 0),
+				usage:      NewUsage(),
+				maxRetries: 2,
+				backoff:    time.Millisecond,
+			}
+			req, err := http.NewRequest(http.MethodGet, test.url, test.body)
+			if err != nil {
+				t.Fatal(err)
+			}
+			resp, err := transport.RoundTrip(req)
+			if !errors.Is(err, test.wantErr) {
+				t.Errorf("RoundTrip() error = %v, want %v", err, test.wantErr)
+			}
+			if resp != nil && resp.StatusCode != test.wantStatus {
+				t.Errorf("RoundTrip() status = %d, want %d", resp.StatusCode, test.wantStatus)
+			}
+			if next.requests != test.wantRequests {
+				t.Errorf("requests = %d, want %d", next.requests, test.wantRequests)
+			}
+			if next.hosts[0] != test.wantHost {
+				t.Errorf("host = %s, want %s", next.hosts[0], test.wantHost)
+			}
+		})
+	}
+}
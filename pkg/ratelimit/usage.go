@@ -0,0 +1,65 @@
+package ratelimit
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// budgets are the budgets usage is always reported for
+var budgets = []api.GW2APIBudget{api.GW2APIBudgetInteractive, api.GW2APIBudgetSync}
+
+// Usage counts the gw2 api requests of each budget
+type Usage struct {
+	since    time.Time
+	counters map[api.GW2APIBudget]*budgetCounters
+}
+
+type budgetCounters struct {
+	requests    atomic.Int64
+	rateLimited atomic.Int64
+	retries     atomic.Int64
+	failures    atomic.Int64
+	waitNanos   atomic.Int64
+}
+
+func (c *budgetCounters) addWait(wait time.Duration) {
+	c.waitNanos.Add(int64(wait))
+}
+
+func NewUsage() *Usage {
+	usage := &Usage{
+		since:    time.Now(),
+		counters: make(map[api.GW2APIBudget]*budgetCounters),
+	}
+	for _, budget := range budgets {
+		usage.counters[budget] = &budgetCounters{}
+	}
+	return usage
+}
+
+// budget returns the counters of one of the budgets
+func (u *Usage) budget(budget api.GW2APIBudget) *budgetCounters {
+	return u.counters[budget]
+}
+
+// Snapshot returns the current counts of every budget
+func (u *Usage) Snapshot() *api.GW2APIUsage {
+	usage := &api.GW2APIUsage{
+		Since:   u.since,
+		Budgets: make([]api.GW2APIBudgetUsage, 0, len(u.counters)),
+	}
+	for _, budget := range budgets {
+		counters := u.counters[budget]
+		usage.Budgets = append(usage.Budgets, api.GW2APIBudgetUsage{
+			Budget:      budget,
+			Requests:    counters.requests.Load(),
+			RateLimited: counters.rateLimited.Load(),
+			Retries:     counters.retries.Load(),
+			Failures:    counters.failures.Load(),
+			WaitSeconds: time.Duration(counters.waitNanos.Load()).Seconds(),
+		})
+	}
+	return usage
+}
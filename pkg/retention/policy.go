@@ -0,0 +1,113 @@
+package retention
+
+import (
+	"encoding/json"
+	"os"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+)
+
+// Duration is a time.Duration that is read from json as a duration string, e.g. "720h"
+type Duration time.Duration
+
+// UnmarshalJSON parses a duration string
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return errors.WithStack(err)
+	}
+	duration, err := time.ParseDuration(s)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	*d = Duration(duration)
+	return nil
+}
+
+// MarshalJSON formats the duration as a duration string
+func (d Duration) MarshalJSON() ([]byte, error) {
+	return json.Marshal(time.Duration(d).String())
+}
+
+// TablePolicy decides how long the rows of a table are kept
+type TablePolicy struct {
+	// Table is one of the tables in Tables
+	Table string `json:"table"`
+	// MaxAge is how long rows are kept. Rows are kept forever if zero
+	MaxAge Duration `json:"max_age,omitempty"`
+	// DownsampleAfter is the age after which only the newest row per DownsampleInterval is kept of each time series.
+	// Only time series tables can be downsampled
+	DownsampleAfter    Duration `json:"downsample_after,omitempty"`
+	DownsampleInterval Duration `json:"downsample_interval,omitempty"`
+}
+
+// Policy is the set of table policies the retention job enforces
+type Policy struct {
+	Tables []TablePolicy `json:"tables"`
+}
+
+// DefaultPolicy is used if no retention policy file is configured
+// Only api keys and accounts are removed, and only if DeleteDataAfter is configured
+func DefaultPolicy() *Policy {
+	policy := &Policy{}
+	if deleteDataAfter := config.Config().DeleteDataAfter; deleteDataAfter != nil {
+		policy.Tables = append(policy.Tables, TablePolicy{
+			Table:  TableTokenInfos,
+			MaxAge: Duration(*deleteDataAfter),
+		})
+	}
+	return policy
+}
+
+// LoadPolicy reads a retention policy from a json file
+func LoadPolicy(path string) (*Policy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var policy Policy
+	if err = json.Unmarshal(data, &policy); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := range policy.Tables {
+		if err = policy.Tables[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &policy, nil
+}
+
+// ConfiguredPolicy returns the policy from the configured retention policy file, or the default policy if none is configured
+func ConfiguredPolicy() (*Policy, error) {
+	path := config.Config().RetentionPolicyFile
+	if path == "" {
+		return DefaultPolicy(), nil
+	}
+	return LoadPolicy(path)
+}
+
+func (p *TablePolicy) validate() error {
+	table, ok := tables[p.Table]
+	if !ok {
+		return errors.Errorf("retention policy has unknown table %s", p.Table)
+	}
+	if p.MaxAge < 0 || p.DownsampleAfter < 0 || p.DownsampleInterval < 0 {
+		return errors.Errorf("retention policy for %s has a negative duration", p.Table)
+	}
+	if p.DownsampleAfter > 0 {
+		if len(table.series) == 0 {
+			return errors.Errorf("%s is not a time series and cannot be downsampled", p.Table)
+		}
+		if p.DownsampleInterval < Duration(time.Second) {
+			return errors.Errorf("retention policy for %s requires a downsample interval of at least a second", p.Table)
+		}
+		if p.MaxAge > 0 && p.DownsampleAfter >= p.MaxAge {
+			return errors.Errorf("retention policy for %s downsamples after rows have been deleted", p.Table)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,407 @@
+package retention
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/cluster"
+	"github.com/vennekilde/gw2verify/v2/pkg/verify"
+	"go.uber.org/zap"
+)
+
+// Tables retention policies can be configured for
+const (
+	TableVoiceUserStates = "voice_user_states"
+	TableAchievements    = "achievements"
+	TableHistories       = "histories"
+	TableEvents          = "events"
+	TableAuditEntries    = "audit_entries"
+	// TableTokenInfos removes api keys that have not been synchronized successfully within the max age,
+	// followed by the accounts that are left without api keys
+	TableTokenInfos = "token_infos"
+)
+
+// Retention removes rows in bounded batches, so no single statement holds locks on a table for long
+const (
+	// deleteBatchSize is the most rows removed by a single delete
+	deleteBatchSize = 10000
+	// downsampleWindow is the period of rows downsampled by a single delete. It is rounded up to whole intervals
+	downsampleWindow = 24 * time.Hour
+)
+
+// Rows belonging to users or accounts with an active ban are never removed
+const (
+	bannedUserIDs    = `SELECT user_id FROM bans WHERE until > NOW() AND lifted_at IS NULL AND user_id IS NOT NULL`
+	bannedAccountIDs = `SELECT account_id FROM bans WHERE until > NOW() AND lifted_at IS NULL AND account_id IS NOT NULL
+		UNION SELECT id FROM accounts WHERE user_id IN (` + bannedUserIDs + `)`
+)
+
+type table struct {
+	// timeColumn is the column the age of a row is determined by
+	timeColumn string
+	// series are the columns identifying a time series. Only tables with series can be downsampled
+	series []string
+	// notBanned is a condition excluding the rows of banned users
+	notBanned string
+}
+
+var tables = map[string]table{
+	TableVoiceUserStates: {
+		timeColumn: "timestamp",
+		series:     []string{"platform_id", "platform_user_id", "channel_id"},
+		notBanned: `NOT EXISTS (SELECT 1 FROM platform_links WHERE platform_links.platform_id = voice_user_states.platform_id
+			AND platform_links.platform_user_id = voice_user_states.platform_user_id
+			AND platform_links.user_id IN (` + bannedUserIDs + `))`,
+	},
+	TableAchievements: {
+		timeColumn: "timestamp",
+		series:     []string{"account_id", "achievement"},
+		notBanned:  `account_id NOT IN (` + bannedAccountIDs + `)`,
+	},
+	TableHistories: {
+		timeColumn: "timestamp",
+		notBanned:  `account_id NOT IN (` + bannedAccountIDs + `)`,
+	},
+	TableEvents: {
+		timeColumn: "db_created",
+		notBanned:  `user_id NOT IN (` + bannedUserIDs + `)`,
+	},
+	TableAuditEntries: {
+		timeColumn: "db_created",
+		notBanned:  `user_id IS NULL OR user_id NOT IN (` + bannedUserIDs + `)`,
+	},
+	TableTokenInfos: {
+		timeColumn: "last_success",
+		notBanned:  `account_id NOT IN (` + bannedAccountIDs + `)`,
+	},
+}
+
+// TableReport is the number of rows removed from a table
+type TableReport struct {
+	Table       string
+	Downsampled int64
+	Deleted     int64
+}
+
+// Report describes what a retention run removed, or would have removed if it was a dry run
+type Report struct {
+	DryRun bool
+	Tables []TableReport
+}
+
+// Service removes data that is older than the retention policy allows
+type Service struct {
+	policy   *Policy
+	election *cluster.Election
+	em       *verify.EventEmitter
+}
+
+func NewService(policy *Policy, election *cluster.Election, em *verify.EventEmitter) *Service {
+	return &Service{
+		policy:   policy,
+		election: election,
+		em:       em,
+	}
+}
+
+// Start enforces the retention policy every retention interval while this instance is the leader of the election.
+// Nothing is done if the interval is zero
+// Note: this method will block the calling goroutine indefinitely
+func (s *Service) Start() {
+	interval := config.Config().RetentionInterval
+	if interval <= 0 || len(s.policy.Tables) == 0 {
+		return
+	}
+	for {
+		time.Sleep(interval)
+		if !s.election.IsLeader() {
+			continue
+		}
+		if _, err := s.Run(config.Config().RetentionDryRun); err != nil {
+			zap.L().Error("unable to enforce retention policy", zap.Error(err))
+		}
+	}
+}
+
+// Run enforces the retention policy. Rows are removed in bounded batches that are committed on their own,
+// so no long running transaction holds locks on the tables. If dryRun is set, the rows that would be removed
+// are only counted with the same filters
+func (s *Service) Run(dryRun bool) (*Report, error) {
+	report := Report{
+		DryRun: dryRun,
+	}
+	now := time.Now()
+	for _, tablePolicy := range s.policy.Tables {
+		tableReports, err := s.enforce(orm.DB(), &tablePolicy, now, dryRun)
+		if err != nil {
+			return nil, err
+		}
+		report.Tables = append(report.Tables, tableReports...)
+	}
+
+	for _, tableReport := range report.Tables {
+		zap.L().Info("enforced retention policy",
+			zap.Bool("dry run", dryRun),
+			zap.String("table", tableReport.Table),
+			zap.Int64("downsampled", tableReport.Downsampled),
+			zap.Int64("deleted", tableReport.Deleted))
+	}
+	return &report, nil
+}
+
+func (s *Service) enforce(idb bun.IDB, policy *TablePolicy, now time.Time, dryRun bool) ([]TableReport, error) {
+	if policy.Table == TableTokenInfos {
+		return s.enforceTokenInfos(idb, policy, now, dryRun)
+	}
+
+	var err error
+	t := tables[policy.Table]
+	report := TableReport{
+		Table: policy.Table,
+	}
+	if policy.DownsampleAfter > 0 {
+		report.Downsampled, err = downsample(idb, policy.Table, t, now.Add(-time.Duration(policy.DownsampleAfter)), time.Duration(policy.DownsampleInterval), dryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if policy.MaxAge > 0 {
+		report.Deleted, err = deleteOlderThan(idb, policy.Table, t, now.Add(-time.Duration(policy.MaxAge)), dryRun)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return []TableReport{report}, nil
+}
+
+// enforceTokenInfos removes the api keys that have not been synchronized successfully since the max age,
+// followed by the accounts that have no api keys left and have not been updated since the max age
+func (s *Service) enforceTokenInfos(idb bun.IDB, policy *TablePolicy, now time.Time, dryRun bool) ([]TableReport, error) {
+	if policy.MaxAge <= 0 {
+		return nil, nil
+	}
+	cutoff := now.Add(-time.Duration(policy.MaxAge))
+	keys, err := deleteOlderThan(idb, TableTokenInfos, tables[TableTokenInfos], cutoff, dryRun)
+	if err != nil {
+		return nil, err
+	}
+
+	// Only the api keys that are kept are considered, so a dry run counts the accounts left without api keys as well
+	filter := func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("db_updated < ?", cutoff).
+			Where("NOT EXISTS (SELECT 1 FROM token_infos WHERE token_infos.account_id = accounts.id AND (token_infos.last_success >= ? OR token_infos.last_success IS NULL))", cutoff).
+			Where("id NOT IN (" + bannedAccountIDs + ")")
+	}
+	var accounts int64
+	if dryRun {
+		accounts, err = count(idb, "accounts", filter)
+	} else {
+		accounts, err = s.deleteAccounts(idb, filter)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return []TableReport{
+		{Table: TableTokenInfos, Deleted: keys},
+		{Table: "accounts", Deleted: accounts},
+	}, nil
+}
+
+// deleteAccounts deletes the accounts matched by the filter, at most deleteBatchSize accounts per transaction.
+// The users of the deleted accounts are emitted in the same transaction, as they may no longer have access through them
+func (s *Service) deleteAccounts(idb bun.IDB, filter func(q *bun.SelectQuery) *bun.SelectQuery) (int64, error) {
+	var deleted int64
+	for {
+		rows, err := s.deleteAccountBatch(idb, filter)
+		if err != nil {
+			return deleted, err
+		}
+		deleted += rows
+		if rows < deleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+func (s *Service) deleteAccountBatch(idb bun.IDB, filter func(q *bun.SelectQuery) *bun.SelectQuery) (int64, error) {
+	ctx := context.Background()
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	// Accounts cascade to their histories and achievements
+	var userIDs []int64
+	_, err = tx.NewDelete().
+		Table("accounts").
+		Where("ctid IN (?)", filter(tx.NewSelect().
+			Table("accounts").
+			Column("ctid")).
+			Limit(deleteBatchSize)).
+		Returning("user_id").
+		Exec(ctx, &userIDs)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	emitted := make(map[int64]bool, len(userIDs))
+	for _, userID := range userIDs {
+		if emitted[userID] {
+			continue
+		}
+		emitted[userID] = true
+		var user api.User
+		err = orm.QueryGetUser(tx, &user, userID).
+			Scan(ctx)
+		if err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			return 0, errors.WithStack(err)
+		}
+		if err = s.em.Emit(tx, &user); err != nil {
+			return 0, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	committed = true
+	return int64(len(userIDs)), nil
+}
+
+func deleteOlderThan(idb bun.IDB, name string, t table, cutoff time.Time, dryRun bool) (int64, error) {
+	filter := func(q *bun.SelectQuery) *bun.SelectQuery {
+		return q.Where("? < ?", bun.Ident(t.timeColumn), cutoff).
+			Where(t.notBanned)
+	}
+	if dryRun {
+		return count(idb, name, filter)
+	}
+	return deleteInBatches(idb, name, filter)
+}
+
+// count counts the rows of the table matched by the filter
+func count(idb bun.IDB, name string, filter func(q *bun.SelectQuery) *bun.SelectQuery) (int64, error) {
+	rows, err := filter(idb.NewSelect().
+		Table(name)).
+		Count(context.Background())
+	return int64(rows), errors.WithStack(err)
+}
+
+// deleteInBatches deletes the rows of the table matched by the filter, at most deleteBatchSize rows per statement
+func deleteInBatches(idb bun.IDB, name string, filter func(q *bun.SelectQuery) *bun.SelectQuery) (int64, error) {
+	var deleted int64
+	for {
+		res, err := idb.NewDelete().
+			Table(name).
+			Where("ctid IN (?)", filter(idb.NewSelect().
+				Table(name).
+				Column("ctid")).
+				Limit(deleteBatchSize)).
+			Exec(context.Background())
+		if err != nil {
+			return deleted, errors.WithStack(err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return deleted, errors.WithStack(err)
+		}
+		deleted += rows
+		if rows < deleteBatchSize {
+			return deleted, nil
+		}
+	}
+}
+
+// downsample keeps only the newest row of each time series within every interval for rows older than the cutoff.
+// The rows are downsampled one window of whole intervals at a time, starting from the oldest row.
+// If dryRun is set, the rows that would be removed are only counted
+func downsample(idb bun.IDB, name string, t table, cutoff time.Time, interval time.Duration, dryRun bool) (int64, error) {
+	ctx := context.Background()
+	series := make([]string, len(t.series))
+	for i, column := range t.series {
+		series[i] = `"` + column + `"`
+	}
+	seconds := int64(interval / time.Second)
+	if seconds <= 0 {
+		return 0, nil
+	}
+
+	var oldest sql.NullTime
+	err := idb.NewSelect().
+		Table(name).
+		ColumnExpr("MIN(?)", bun.Ident(t.timeColumn)).
+		Where("? < ?", bun.Ident(t.timeColumn), cutoff).
+		Where(t.notBanned).
+		Scan(ctx, &oldest)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	if !oldest.Valid {
+		return 0, nil
+	}
+
+	// The windows are whole intervals, so ranking every row at once matches downsampling them a window at a time
+	if dryRun {
+		redundant, err := idb.NewSelect().
+			TableExpr("(?) AS ranked", rankSeries(idb, name, t, series, seconds, oldest.Time, cutoff)).
+			Where("row_rank > 1").
+			Count(ctx)
+		return int64(redundant), errors.WithStack(err)
+	}
+
+	// Windows are aligned with the intervals, so every interval is downsampled as a whole
+	window := interval * time.Duration((downsampleWindow+interval-1)/interval)
+	from := time.Unix(oldest.Time.Unix()/seconds*seconds, 0)
+	var downsampled int64
+	for from.Before(cutoff) {
+		to := from.Add(window)
+		if to.After(cutoff) {
+			to = cutoff
+		}
+		res, err := idb.NewDelete().
+			Table(name).
+			Where("ctid IN (?)", idb.NewSelect().
+				TableExpr("(?) AS ranked", rankSeries(idb, name, t, series, seconds, from, to)).
+				Column("ctid").
+				Where("row_rank > 1")).
+			Exec(ctx)
+		if err != nil {
+			return downsampled, errors.WithStack(err)
+		}
+		rows, err := res.RowsAffected()
+		if err != nil {
+			return downsampled, errors.WithStack(err)
+		}
+		downsampled += rows
+		from = to
+	}
+	return downsampled, nil
+}
+
+// rankSeries ranks the rows between from and to within each interval of their time series, newest first
+func rankSeries(idb bun.IDB, name string, t table, series []string, seconds int64, from time.Time, to time.Time) *bun.SelectQuery {
+	return idb.NewSelect().
+		Table(name).
+		Column("ctid").
+		ColumnExpr("ROW_NUMBER() OVER (PARTITION BY ?, to_timestamp(floor(extract(epoch FROM ?) / ?) * ?) ORDER BY ? DESC) AS row_rank",
+			bun.Safe(strings.Join(series, ", ")), bun.Ident(t.timeColumn), seconds, seconds, bun.Ident(t.timeColumn)).
+		Where("? >= ? AND ? < ?", bun.Ident(t.timeColumn), from, bun.Ident(t.timeColumn), to).
+		Where(t.notBanned)
+}
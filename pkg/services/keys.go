@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// apiKeyLength is the amount of random bytes in an API key
+const apiKeyLength = 32
+
+// saltLength is the amount of random bytes in the salt of a hashed API key
+const saltLength = 16
+
+// lookupPrefixLength is the amount of leading characters of an API key that are stored in plaintext to look up the service
+const lookupPrefixLength = 8
+
+// Errors raised.
+var (
+	ErrInvalidAPIKey = errors.New("invalid api key")
+)
+
+// serviceCredentials is a service along with its hashed API key, as stored in the database
+type serviceCredentials struct {
+	bun.BaseModel `bun:"table:services,alias:service"`
+	api.Service
+
+	KeyPrefix string
+	KeySalt   string
+	KeyHash   string
+}
+
+// GenerateAPIKey returns a new random API key
+func GenerateAPIKey() (string, error) {
+	key := make([]byte, apiKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return "", errors.WithStack(err)
+	}
+	return hex.EncodeToString(key), nil
+}
+
+// LookupPrefix returns the leading part of the API key used to look up the service it belongs to
+// Short keys only reveal half their length, so the prefix never gives away the whole key
+func LookupPrefix(apiKey string) string {
+	return apiKey[:min(lookupPrefixLength, len(apiKey)/2)]
+}
+
+// HashAPIKey hashes the API key with the salt
+// Must match the hashing used when migrating plaintext keys: hex(sha256(salt || key))
+func HashAPIKey(salt string, apiKey string) string {
+	hash := sha256.Sum256([]byte(salt + apiKey))
+	return hex.EncodeToString(hash[:])
+}
+
+// newCredentials returns the lookup prefix, a new salt and the salted hash of the API key
+func newCredentials(apiKey string) (prefix string, salt string, hash string, err error) {
+	saltBytes := make([]byte, saltLength)
+	if _, err = rand.Read(saltBytes); err != nil {
+		return "", "", "", errors.WithStack(err)
+	}
+	salt = hex.EncodeToString(saltBytes)
+	return LookupPrefix(apiKey), salt, HashAPIKey(salt, apiKey), nil
+}
+
+// Authenticate returns the enabled service the API key belongs to. ErrInvalidAPIKey is returned if there is none
+func Authenticate(idb bun.IDB, apiKey string) (*api.Service, error) {
+	ctx := context.Background()
+	if apiKey == "" {
+		return nil, ErrInvalidAPIKey
+	}
+
+	// Prefixes are not unique, so every candidate has to be checked
+	var candidates []serviceCredentials
+	err := idb.NewSelect().
+		Model(&candidates).
+		Where("key_prefix = ? AND disabled_at IS NULL", LookupPrefix(apiKey)).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := range candidates {
+		candidate := &candidates[i]
+		hash := HashAPIKey(candidate.KeySalt, apiKey)
+		if subtle.ConstantTimeCompare([]byte(hash), []byte(candidate.KeyHash)) == 1 {
+			return &candidate.Service, nil
+		}
+	}
+	return nil, ErrInvalidAPIKey
+}
@@ -0,0 +1,75 @@
+package services
+
+import "testing"
+
+func TestHashAPIKey(t *testing.T) {
+	// Expected hashes are hex(sha256(salt || key)), as computed by the migration hashing plaintext keys
+	tests := []struct {
+		name   string
+		salt   string
+		apiKey string
+		want   string
+	}{
+		{"hash", "salt", "key", "4a466ea0657e479545b1d6c2d994824f80d8eecd7030f3092ff42a9bcad751d8"},
+		{"hex salt", "00112233445566778899aabbccddeeff", "0123456789abcdef", "61879010f5457f4a82da1cf1cd4d8031ff9259694053d92da072a3c86a05974e"},
+		{"no salt", "", "key", "2c70e12b7a0646f92279f427c7b38e7334d8e5389cff167a1dc30e73f826b683"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := HashAPIKey(test.salt, test.apiKey); got != test.want {
+				t.Errorf("HashAPIKey() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestLookupPrefix(t *testing.T) {
+	tests := []struct {
+		name   string
+		apiKey string
+		want   string
+	}{
+		{"long key", "0123456789abcdef", "01234567"},
+		{"short key", "012345", "012"},
+		{"empty key", "", ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := LookupPrefix(test.apiKey); got != test.want {
+				t.Errorf("LookupPrefix() = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestNewCredentials(t *testing.T) {
+	apiKey, err := GenerateAPIKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(apiKey) != apiKeyLength*2 {
+		t.Fatalf("GenerateAPIKey() length = %d, want %d", len(apiKey), apiKeyLength*2)
+	}
+
+	prefix, salt, hash, err := newCredentials(apiKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if prefix != apiKey[:lookupPrefixLength] {
+		t.Errorf("prefix = %s, want %s", prefix, apiKey[:lookupPrefixLength])
+	}
+	if len(salt) != saltLength*2 {
+		t.Errorf("salt length = %d, want %d", len(salt), saltLength*2)
+	}
+	if hash != HashAPIKey(salt, apiKey) {
+		t.Errorf("hash = %s, want %s", hash, HashAPIKey(salt, apiKey))
+	}
+
+	_, otherSalt, otherHash, err := newCredentials(apiKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if otherSalt == salt || otherHash == hash {
+		t.Errorf("credentials of the same key share salt %s or hash %s", salt, hash)
+	}
+}
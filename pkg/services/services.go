@@ -0,0 +1,184 @@
+package services
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/dialect/pgdialect"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// RevocationChannel is the postgres notification channel the uuid of a service is sent on when its API key or scopes change
+const RevocationChannel = "service_keys_revoked"
+
+// Errors raised.
+var (
+	ErrServiceNotFound = errors.New("service not found")
+)
+
+// HasScope checks if the granted scopes include the scope. The admin scope includes every scope
+func HasScope(granted []api.Scope, scope api.Scope) bool {
+	return slices.Contains(granted, api.ScopeAdmin) || slices.Contains(granted, scope)
+}
+
+// ValidateScopes ensures all the scopes are known scopes
+func ValidateScopes(scopes []api.Scope) error {
+	for _, scope := range scopes {
+		switch scope {
+		case api.ScopeStatusRead, api.ScopeAPIKeyWrite, api.ScopeRequirementsSkip, api.ScopeBanWrite, api.ScopeTemporaryWrite,
+			api.ScopeStatisticsWrite, api.ScopePropertiesOwn, api.ScopeWebhooksOwn, api.ScopeAuditRead, api.ScopePrivacyRead, api.ScopePrivacyErase, api.ScopeAdmin:
+		default:
+			return errors.Errorf("unknown scope \"%s\"", scope)
+		}
+	}
+	return nil
+}
+
+// CreateService creates a new service with the given scopes and a generated API key
+// The returned service is the only time the API key is available
+func CreateService(idb bun.IDB, name string, scopes []api.Scope) (*api.Service, error) {
+	ctx := context.Background()
+	if err := ValidateScopes(scopes); err != nil {
+		return nil, err
+	}
+	apiKey, err := GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	prefix, salt, hash, err := newCredentials(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	if scopes == nil {
+		scopes = []api.Scope{}
+	}
+	service := serviceCredentials{
+		Service: api.Service{
+			UUID:   uuid.NewString(),
+			Name:   name,
+			Scopes: scopes,
+		},
+		KeyPrefix: prefix,
+		KeySalt:   salt,
+		KeyHash:   hash,
+	}
+	_, err = idb.NewInsert().
+		Model(&service).
+		Returning("db_created").
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	service.APIKey = &apiKey
+	return &service.Service, nil
+}
+
+// GetServices returns all services
+func GetServices(idb bun.IDB) (services []api.Service, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model(&services).
+		Order("db_created").
+		Scan(ctx)
+	if services == nil {
+		services = []api.Service{}
+	}
+	return services, errors.WithStack(err)
+}
+
+// GetService returns a service. ErrServiceNotFound is returned if it does not exist
+func GetService(idb bun.IDB, serviceUUID string) (*api.Service, error) {
+	ctx := context.Background()
+	var service api.Service
+	err := idb.NewSelect().
+		Model(&service).
+		Where("uuid = ?", serviceUUID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return nil, ErrServiceNotFound
+	}
+	return &service, errors.WithStack(err)
+}
+
+// UpdateService changes the name and/or replaces the scopes of a service
+func UpdateService(idb bun.IDB, serviceUUID string, update api.ServiceUpdate) (*api.Service, error) {
+	if update.Scopes != nil {
+		if err := ValidateScopes(*update.Scopes); err != nil {
+			return nil, err
+		}
+	}
+	if update.Name == nil && update.Scopes == nil {
+		return GetService(idb, serviceUUID)
+	}
+
+	set := []string{}
+	args := []any{}
+	if update.Name != nil {
+		set = append(set, "name = ?")
+		args = append(args, *update.Name)
+	}
+	if update.Scopes != nil {
+		set = append(set, "scopes = ?")
+		args = append(args, pgdialect.Array(*update.Scopes))
+	}
+	return updateService(idb, serviceUUID, strings.Join(set, ", "), args...)
+}
+
+// RotateServiceKey replaces the API key of a service and revokes the previous key
+// The returned service is the only time the new API key is available
+func RotateServiceKey(idb bun.IDB, serviceUUID string) (*api.Service, error) {
+	apiKey, err := GenerateAPIKey()
+	if err != nil {
+		return nil, err
+	}
+	prefix, salt, hash, err := newCredentials(apiKey)
+	if err != nil {
+		return nil, err
+	}
+	service, err := updateService(idb, serviceUUID, "key_prefix = ?, key_salt = ?, key_hash = ?", prefix, salt, hash)
+	if err != nil {
+		return nil, err
+	}
+	service.APIKey = &apiKey
+	return service, nil
+}
+
+// DisableService revokes access to the API for a service
+func DisableService(idb bun.IDB, serviceUUID string) (*api.Service, error) {
+	return updateService(idb, serviceUUID, "disabled_at = COALESCE(disabled_at, NOW())")
+}
+
+// updateService applies the set expression to a service and notifies listeners that the cached access of the service is outdated
+func updateService(idb bun.IDB, serviceUUID string, set string, args ...any) (*api.Service, error) {
+	ctx := context.Background()
+	var service api.Service
+	res, err := idb.NewUpdate().
+		Model(&service).
+		Set(set, args...).
+		Where("uuid = ?", serviceUUID).
+		Returning("uuid, name, scopes, db_created, disabled_at").
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return nil, ErrServiceNotFound
+	}
+
+	if err = NotifyRevocation(idb, serviceUUID); err != nil {
+		return nil, err
+	}
+	return &service, nil
+}
+
+// NotifyRevocation notifies every server that cached API keys of the service must no longer be trusted
+func NotifyRevocation(idb bun.IDB, serviceUUID string) error {
+	ctx := context.Background()
+	_, err := idb.ExecContext(ctx, "SELECT pg_notify(?, ?)", RevocationChannel, serviceUUID)
+	return errors.WithStack(err)
+}
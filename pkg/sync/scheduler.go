@@ -0,0 +1,228 @@
+package sync
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"go.uber.org/zap"
+)
+
+// scheduleBatchSize is how many of the highest priority api keys are fetched at a time
+const scheduleBatchSize = 30
+
+// minClaimInterval is the shortest time between starting two synchronizations, regardless of how many api keys there are
+const minClaimInterval = 10 * time.Millisecond
+
+// priorityExpr ranks api keys by how urgently they need to be synchronized. A key is due once its priority reaches 1.
+// The priority grows linearly with the time since the key was last attempted, reaching 1 after the refresh period.
+// Each of the following adds 1:
+//   - The account expires within the refresh period
+//   - The key was last attempted before the most recent matchup reset
+//   - The user has been active in voice recently and the key was not attempted within the active refresh period
+const priorityExpr = `EXTRACT(EPOCH FROM ?::timestamptz - token_info.db_updated) / ?
+	+ CASE WHEN token_info.last_success < ? THEN 1 ELSE 0 END
+	+ CASE WHEN token_info.db_updated < ? THEN 1 ELSE 0 END
+	+ CASE WHEN token_info.db_updated < ? AND EXISTS (
+		SELECT 1 FROM voice_user_states
+		INNER JOIN platform_links ON platform_links.platform_id = voice_user_states.platform_id
+			AND platform_links.platform_user_id = voice_user_states.platform_user_id
+		INNER JOIN accounts ON accounts.user_id = platform_links.user_id
+		WHERE accounts.id = token_info.account_id AND voice_user_states.timestamp > ?
+	) THEN 1 ELSE 0 END`
+
+// scheduledPriority joins the priority of each api key as scheduled.priority, so it is computed once even though it is both filtered and sorted on.
+// OFFSET 0 keeps the planner from inlining the expression into every reference of it
+const scheduledPriority = "LATERAL (SELECT " + priorityExpr + " AS priority OFFSET 0) AS scheduled"
+
+// priority holds the arguments of priorityExpr at a point in time
+type priority struct {
+	now                time.Time
+	refreshPeriod      time.Duration
+	expiresBefore      time.Time
+	lastReset          time.Time
+	activeAttemptAfter time.Time
+	activeSince        time.Time
+}
+
+func newPriority(now time.Time, lastReset time.Time) priority {
+	conf := config.Config()
+	refreshPeriod := RefreshPeriod()
+	return priority{
+		now:                now,
+		refreshPeriod:      refreshPeriod,
+		expiresBefore:      now.Add(refreshPeriod - time.Duration(conf.ExpirationTime)*time.Second),
+		lastReset:          lastReset,
+		activeAttemptAfter: now.Add(-conf.SyncActiveRefreshPeriod),
+		activeSince:        now.Add(-conf.SyncActivityWindow),
+	}
+}
+
+func (p priority) args() []any {
+	return []any{p.now, p.refreshPeriod.Seconds(), p.expiresBefore, p.lastReset, p.activeAttemptAfter, p.activeSince}
+}
+
+// RefreshPeriod is how often every api key is synchronized
+func RefreshPeriod() time.Duration {
+	conf := config.Config()
+	if conf.SyncRefreshPeriod > 0 {
+		return conf.SyncRefreshPeriod
+	}
+	if conf.ExpirationTime > 0 {
+		return time.Duration(conf.ExpirationTime) * time.Second / 4
+	}
+	return 24 * time.Hour
+}
+
+// querySchedulableAPIKeys selects the api keys that are synchronized by the scheduler.
+// Keys that have been failing for longer than the expiration time are left alone,
+// and keys that failed recently are skipped for a backoff that doubles with every consecutive failure, up to the refresh period
+func querySchedulableAPIKeys(idb bun.IDB, model any, p priority) *bun.SelectQuery {
+	window := config.Config().ExpirationTime
+	return idb.NewSelect().
+		Model(model).
+		Where("token_info.last_success >= token_info.db_updated - interval '"+strconv.Itoa(window)+" seconds' OR token_info.last_success IS NULL").
+		Where("token_info.failures = 0 OR token_info.db_updated < ?::timestamptz - LEAST(? * POWER(2, LEAST(token_info.failures, 16) - 1), ?) * interval '1 second'",
+			p.now, config.Config().SyncFailureBackoff.Seconds(), p.refreshPeriod.Seconds())
+}
+
+// ClaimDueAPIKeys claims up to limit api keys that are due for synchronization, highest priority first.
+// The keys are leased to the caller for the lease duration, so neither other instances nor other goroutines claim them meanwhile.
+// The lease is released once the synchronization succeeds or fails
+func ClaimDueAPIKeys(lastReset time.Time, limit int) (tokens []orm.TokenInfo, err error) {
+	ctx := context.Background()
+	tx, err := orm.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	p := newPriority(time.Now(), lastReset)
+	// Keys locked by a concurrent claim are skipped rather than waited for
+	err = querySchedulableAPIKeys(tx, &tokens, p).
+		TableExpr(scheduledPriority, p.args()...).
+		Where("token_info.leased_until IS NULL OR token_info.leased_until < ?", p.now).
+		Where("scheduled.priority >= 1").
+		OrderExpr("scheduled.priority DESC").
+		OrderExpr("token_info.failures").
+		Limit(limit).
+		For("UPDATE OF token_info SKIP LOCKED").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(tokens) == 0 {
+		return tokens, nil
+	}
+
+	ids := make([]string, len(tokens))
+	for i := range tokens {
+		ids[i] = tokens[i].ID
+	}
+	leasedUntil := p.now.Add(config.Config().SyncLeaseDuration)
+	// Claiming a key counts as an attempt
+	_, err = tx.NewUpdate().
+		Table("token_infos").
+		Set("leased_until = ?", leasedUntil).
+		Set("db_updated = ?", p.now).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed = true
+
+	for i := range tokens {
+		previousAttempt := tokens[i].DbUpdated
+		tokens[i].PreviousAttempt = &previousAttempt
+		tokens[i].LeasedUntil = &leasedUntil
+		tokens[i].DbUpdated = p.now
+	}
+	return tokens, nil
+}
+
+// SyncPlan compares the throughput needed to synchronize every api key within the refresh period to the configured throughput
+type SyncPlan struct {
+	APIKeys       int
+	DueAPIKeys    int
+	RefreshPeriod time.Duration
+	// RequiredInterval is the longest time between synchronizations that refreshes every api key within the refresh period
+	RequiredInterval time.Duration
+	// ClaimInterval is the time between starting two synchronizations. It is the sync interval,
+	// shortened to the required interval if the sync interval is too long to keep up with the refresh period
+	ClaimInterval time.Duration
+}
+
+// Sufficient reports whether the sync interval is short enough to keep up with the refresh period
+func (plan *SyncPlan) Sufficient() bool {
+	return plan.APIKeys == 0 || config.Config().SyncInterval <= plan.RequiredInterval
+}
+
+// Plan calculates the sync plan for the current api keys
+func Plan(idb bun.IDB, lastReset time.Time) (*SyncPlan, error) {
+	ctx := context.Background()
+	p := newPriority(time.Now(), lastReset)
+	apiKeys, err := querySchedulableAPIKeys(idb, (*orm.TokenInfo)(nil), p).
+		Count(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	dueAPIKeys, err := querySchedulableAPIKeys(idb, (*orm.TokenInfo)(nil), p).
+		TableExpr(scheduledPriority, p.args()...).
+		Where("scheduled.priority >= 1").
+		Count(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	plan := &SyncPlan{
+		APIKeys:       apiKeys,
+		DueAPIKeys:    dueAPIKeys,
+		RefreshPeriod: p.refreshPeriod,
+		ClaimInterval: config.Config().SyncInterval,
+	}
+	if apiKeys > 0 {
+		// The gw2 api rate limiter still bounds the requests, so synchronizations can be started more often than configured
+		plan.RequiredInterval = p.refreshPeriod / time.Duration(apiKeys)
+		plan.ClaimInterval = max(min(plan.ClaimInterval, plan.RequiredInterval), minClaimInterval)
+	}
+	return plan, nil
+}
+
+// updatePlan calculates the sync plan, adopts its claim interval and logs it
+// Returns the previous claim interval if the plan cannot be calculated
+func (s *Service) updatePlan(claimInterval time.Duration) time.Duration {
+	plan, err := Plan(orm.DB(), s.worlds.LastMatchupReset())
+	if err != nil {
+		zap.L().Error("unable to plan synchronization", zap.Error(err))
+		return claimInterval
+	}
+	fields := []zap.Field{
+		zap.Int("api keys", plan.APIKeys),
+		zap.Int("due api keys", plan.DueAPIKeys),
+		zap.Duration("refresh period", plan.RefreshPeriod),
+		zap.Duration("required interval", plan.RequiredInterval),
+		zap.Duration("sync interval", config.Config().SyncInterval),
+		zap.Duration("claim interval", plan.ClaimInterval),
+	}
+	if !plan.Sufficient() {
+		zap.L().Warn("sync interval is too long to synchronize every api key within the refresh period, shortening it to the required interval", fields...)
+	} else {
+		zap.L().Info("sync plan", fields...)
+	}
+	return plan.ClaimInterval
+}
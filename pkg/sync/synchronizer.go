@@ -4,7 +4,6 @@ import (
 	"context"
 	"database/sql"
 	"slices"
-	"strconv"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -16,6 +15,7 @@ import (
 	"github.com/vennekilde/gw2verify/v2/internal/config"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
 	"github.com/vennekilde/gw2verify/v2/pkg/history"
+	"github.com/vennekilde/gw2verify/v2/pkg/ratelimit"
 	"github.com/vennekilde/gw2verify/v2/pkg/verify"
 	"go.uber.org/zap"
 
@@ -64,18 +64,22 @@ var achievements = []int{
 }
 
 type Service struct {
-	pool sync.Pool
-	em   *verify.EventEmitter
+	pool         sync.Pool
+	verification *verify.Verification
+	worlds       *verify.Worlds
+	em           *verify.EventEmitter
 }
 
-func NewService(em *verify.EventEmitter) *Service {
+func NewService(verification *verify.Verification, worlds *verify.Worlds, em *verify.EventEmitter) *Service {
 	return &Service{
 		pool: sync.Pool{
 			New: func() interface{} {
-				return gw2api.New()
+				return ratelimit.NewSession(api.GW2APIBudgetSync)
 			},
 		},
-		em: em,
+		verification: verification,
+		worlds:       worlds,
+		em:           em,
 	}
 }
 
@@ -87,17 +91,20 @@ func (s *Service) putGW2API(gw2API *gw2api.Session) {
 	s.pool.Put(gw2API)
 }
 
-// Start starts a synchronization loop that will continuously fetch the oldest updated API key
-// and synchronize it with the gw2 api
+// Start starts a synchronization loop that will continuously claim and synchronize the api keys that are due, highest priority first.
+// Keys are started once per claim interval, which is the sync interval unless it is too long to synchronize every key within the refresh period. Several instances can run the loop at once, as each key is only claimed by one of them
 func (s *Service) Start() {
 	var consecutiveFailureCount atomic.Int32
 	var failureCount atomic.Int32
+	var rateLimitedCount atomic.Int32
 	var successCount atomic.Int32
 	var successTimestamp = time.Now()
 
 	var activeJobs atomic.Int32
 	var syncJobsSkipped atomic.Int32
+	var queue []orm.TokenInfo
 	conf := config.Config()
+	claimInterval := s.updatePlan(conf.SyncInterval)
 	for {
 		func() {
 			defer func() {
@@ -106,73 +113,85 @@ func (s *Service) Start() {
 				}
 			}()
 
-			// Throttle if we experience excessive failures
-			if consecutiveFailureCount.Load() >= 10 {
-				zap.L().Warn("10 consecutive failures, sleeping for 10 seconds")
-				time.Sleep(10 * time.Second)
-			}
-
-			if activeJobs.Load() < conf.MaxConcurrentSyncs {
-				activeJobs.Add(1)
-				go func() {
-					err := s.SynchronizeNextAPIKey(orm.DB())
-					if err != nil {
-						zap.L().Error("unable to sync api key", zap.Error(err))
-						consecutiveFailureCount.Add(1)
-						failureCount.Add(1)
-						return
-					}
-					consecutiveFailureCount.Store(0)
-					successCount.Add(1)
-					activeJobs.Add(-1)
-				}()
-			} else {
-				syncJobsSkipped.Add(1)
-			}
-
-			// Wait for next sync interval
-			time.Sleep(conf.SyncInterval)
-
 			// Print basic performance number every 10th minute
 			if time.Since(successTimestamp).Minutes() >= 10 {
 				zap.L().Info("statistics past 10 minutes",
 					zap.Int32("successes", successCount.Load()),
-					zap.Int32("failures", consecutiveFailureCount.Load()),
+					zap.Int32("failures", failureCount.Load()),
+					zap.Int32("rate limited", rateLimitedCount.Load()),
 					zap.Int32("skips", syncJobsSkipped.Load()),
 					zap.Int32("active jobs", activeJobs.Load()),
 					zap.Int32("current consecutive failures", consecutiveFailureCount.Load()))
+				claimInterval = s.updatePlan(claimInterval)
 				successTimestamp = time.Now()
 				successCount.Store(0)
 				failureCount.Store(0)
+				rateLimitedCount.Store(0)
 				syncJobsSkipped.Store(0)
 			}
-		}()
-	}
-}
 
-func (s *Service) SynchronizeNextAPIKey(tx bun.IDB) error {
-	// Find next token to sync
-	window := config.Config().ExpirationTime
-	token, err := orm.FindLastUpdatedAPIKey(window)
-	if err != nil {
-		if errors.Is(err, sql.ErrNoRows) {
-			return nil
-		}
-		return err
-	}
+			// Throttle if we experience excessive failures
+			if consecutiveFailureCount.Load() >= 10 {
+				zap.L().Warn("10 consecutive failures, sleeping for 10 seconds")
+				time.Sleep(10 * time.Second)
+			}
 
-	err = token.UpdateLastAttemptedUpdate()
-	if err != nil {
-		return err
+			if activeJobs.Load() >= conf.MaxConcurrentSyncs {
+				syncJobsSkipped.Add(1)
+				time.Sleep(claimInterval)
+				return
+			}
+
+			if len(queue) == 0 {
+				var err error
+				queue, err = ClaimDueAPIKeys(s.worlds.LastMatchupReset(), scheduleBatchSize)
+				if err != nil {
+					zap.L().Error("unable to find api keys to sync", zap.Error(err))
+					time.Sleep(claimInterval)
+					return
+				}
+				if len(queue) == 0 {
+					// Nothing is due, wait for api keys to become due
+					time.Sleep(scheduleBatchSize * claimInterval)
+					return
+				}
+			}
+			token := queue[0]
+			queue = queue[1:]
+
+			activeJobs.Add(1)
+			go func() {
+				defer activeJobs.Add(-1)
+				err := s.SynchronizeScheduledAPIKey(orm.DB(), &token)
+				if errors.Is(err, ratelimit.ErrRateLimited) {
+					// The rate limiter already slows down every request, so this is not treated as a failure of the synchronization
+					rateLimitedCount.Add(1)
+					return
+				} else if err != nil {
+					zap.L().Error("unable to sync api key", zap.Error(err))
+					consecutiveFailureCount.Add(1)
+					failureCount.Add(1)
+					return
+				}
+				consecutiveFailureCount.Store(0)
+				successCount.Add(1)
+			}()
+
+			// Wait for next claim interval
+			time.Sleep(claimInterval)
+		}()
 	}
+}
 
+// SynchronizeScheduledAPIKey synchronizes an api key picked by the scheduler and records the failure if it fails
+func (s *Service) SynchronizeScheduledAPIKey(tx bun.IDB, token *orm.TokenInfo) error {
 	gw2API := s.getGW2API()
 	defer s.putGW2API(gw2API)
 	// Synchronize all data available with the api key
-	acc, err := s.SynchronizeAPIKey(tx, gw2API, &token)
+	acc, err := s.SynchronizeAPIKey(tx, gw2API, token)
 	if err != nil {
 		// Handle failed token
-		err = s.HandleFailedTokenInfo(&token, acc, err)
+		err = s.HandleFailedTokenInfo(token, acc, err)
 		return err
 	}
 
@@ -180,11 +199,17 @@ func (s *Service) SynchronizeNextAPIKey(tx bun.IDB) error {
 }
 
 func (s *Service) HandleFailedTokenInfo(token *orm.TokenInfo, acc *api.Account, err error) error {
-	ctx := context.Background()
+	// Being rate limited is not the fault of the api key, so the attempt is undone and the key is claimed again once the rate limit allows it
+	if errors.Is(err, ratelimit.ErrRateLimited) {
+		if releaseErr := token.ReleaseLease(); releaseErr != nil {
+			return releaseErr
+		}
+		return err
+	}
 
 	if acc != nil {
 		zap.L().Error("could not synchronize apikey",
-			zap.String("apikey", token.APIKey),
+			zap.String("account id", token.AccountID),
 			zap.String("account name", acc.Name),
 			zap.Error(err))
 	} else {
@@ -192,48 +217,13 @@ func (s *Service) HandleFailedTokenInfo(token *orm.TokenInfo, acc *api.Account,
 		showErr := !strings.Contains(err.Error(), "invalid key") || config.Config().Debug
 		if showErr {
 			zap.L().Error("could not synchronize apikey",
-				zap.String("apikey", token.APIKey),
+				zap.String("account id", token.AccountID),
 				zap.Error(err))
 		}
 	}
 
-	// Should we delete old data?
-	if config.Config().DeleteDataAfter != nil {
-		// Is the data old?
-		expTime := *config.Config().DeleteDataAfter
-		if time.Since(token.LastSuccess) > expTime {
-			var isBanned bool
-			var storedAcc api.Account
-			// Do we have acc data for the user?
-			if acc != nil {
-				// Check if user is banned before deleting data
-				if token.AccountID != "" {
-					_ = orm.DB().NewSelect().
-						Model(&storedAcc).
-						Where(`"id" = ?`, acc.ID).
-						Scan(ctx)
-					if storedAcc.ID != "" {
-						isBanned = verify.GetBan(&storedAcc) != nil
-					}
-				}
-			}
-
-			count, err := orm.DB().NewSelect().
-				Model(&orm.TokenInfo{}).
-				Where("account_id = ?, last_success >= db_updated - interval '"+strconv.Itoa(config.Config().ExpirationTime)+" seconds' OR last_success IS NULL", acc.ID).
-				Count(ctx)
-			if err != nil {
-				return err
-			}
-
-			if count == 0 && !isBanned {
-				// delete expired data
-				orm.DB().NewDelete().Model(&token).Exec(ctx)
-				orm.DB().NewDelete().Model(&storedAcc).Exec(ctx)
-			}
-		}
-	}
-	return nil
+	// Consecutive failures back off the next attempt
+	return token.UpdateFailedUpdate()
 }
 
 func (s *Service) SynchronizeUser(tx bun.IDB, gw2API *gw2api.Session, userID int64) error {
@@ -248,7 +238,7 @@ func (s *Service) SynchronizeUser(tx bun.IDB, gw2API *gw2api.Session, userID int
 		if err != nil {
 			zap.L().Error("unable to synchronize user account",
 				zap.Any("account", acc),
-				zap.Any("token", token),
+				zap.String("key name", token.Name),
 				zap.Error(err))
 		}
 	}
@@ -274,33 +264,45 @@ func (s *Service) SynchronizeAPIKey(tx bun.IDB, gw2API *gw2api.Session, token *o
 		return newAcc, errors.WithStack(err)
 	}
 	newAcc.UserID = oldAcc.UserID
+	newAcc.MaxCharacterLevel = oldAcc.MaxCharacterLevel
+	newAcc.CharacterLevelSynced = oldAcc.CharacterLevelSynced
+	newAcc.CharacterLevelSyncedAt = oldAcc.CharacterLevelSyncedAt
 
 	// Store any account changes in the history
 	history.CollectAccount(oldAcc, gw2Acc)
 
-	// Notify listeners if needed of verification changes (if any)
-	if s.em.ShouldEmitAccount(&oldAcc, newAcc) {
-		var user api.User
-		err = orm.QueryGetUser(tx, &user, newAcc.UserID).
-			Scan(ctx)
-		if err != nil {
-			return newAcc, errors.WithStack(err)
-		}
-		s.em.Emit(&user)
-	}
-
 	// Synchronize WvW data
 	err = synchronizeAccountWvW(gw2API, newAcc, token.Permissions)
 	if err != nil {
 		return newAcc, errors.WithStack(err)
 	}
 
+	// Synchronize character levels
+	err = synchronizeAccountCharacters(gw2API, newAcc, token.Permissions)
+	if err != nil {
+		return newAcc, errors.WithStack(err)
+	}
+
 	// persist any changes made to the account data
 	err = newAcc.Persist(tx)
 	if err != nil {
 		return newAcc, err
 	}
 
+	// Notify listeners if needed of verification changes (if any)
+	// The WvW team and alliance affects the verification status, so this has to happen after the WvW data is synchronized
+	if s.em.ShouldEmitAccount(&oldAcc, newAcc) {
+		var user api.User
+		err = orm.QueryGetUser(tx, &user, newAcc.UserID).
+			Scan(ctx)
+		if err != nil {
+			return newAcc, errors.WithStack(err)
+		}
+		if err = s.em.Emit(tx, &user); err != nil {
+			return newAcc, err
+		}
+	}
+
 	//Check if token metadata is missing
 	if token.AccountID == "" || len(token.Permissions) <= 0 {
 		token.AccountID = newAcc.ID
@@ -359,6 +361,44 @@ func synchronizeAccountWvW(gw2API *gw2api.Session, acc *api.Account, permissions
 	return nil
 }
 
+// characterLevelSyncInterval is how often the characters of accounts without a max level character are fetched again
+const characterLevelSyncInterval = 24 * time.Hour
+
+// synchronizeAccountCharacters updates the highest character level of the account
+// Character levels never decrease, so characters are only fetched until one of them has reached max level,
+// and at most once per characterLevelSyncInterval while none has
+func synchronizeAccountCharacters(gw2API *gw2api.Session, acc *api.Account, permissions []string) error {
+	if acc.MaxCharacterLevel >= verify.MaxLevel || !slices.Contains(permissions, "characters") {
+		return nil
+	}
+	if acc.CharacterLevelSynced && acc.CharacterLevelSyncedAt != nil && time.Since(*acc.CharacterLevelSyncedAt) < characterLevelSyncInterval {
+		return nil
+	}
+
+	charNames, err := gw2API.Characters()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, name := range charNames {
+		char, err := gw2API.CharacterCore(name)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+
+		if char.Level > acc.MaxCharacterLevel {
+			acc.MaxCharacterLevel = char.Level
+		}
+		if acc.MaxCharacterLevel >= verify.MaxLevel {
+			break
+		}
+	}
+	now := time.Now()
+	acc.CharacterLevelSynced = true
+	acc.CharacterLevelSyncedAt = &now
+	return nil
+}
+
 func (s *Service) synchronizeAccountAchievements(tx bun.IDB, gw2API *gw2api.Session, token *orm.TokenInfo, acc *api.Account) error {
 	// Synchronize achivements
 	if slices.ContainsFunc(token.Permissions, func(val string) bool { return strings.Contains(val, "progression") }) {
@@ -11,6 +11,7 @@ import (
 	"go.uber.org/zap"
 
 	"github.com/MrGunflame/gw2api"
+	"github.com/uptrace/bun"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/config"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
@@ -18,13 +19,10 @@ import (
 	"github.com/vennekilde/gw2verify/v2/pkg/verify"
 )
 
-// FreeToPlayWvWRankRestriction restricts the minimum required wvw rank before free to play users can verify
-var FreeToPlayWvWRankRestriction = 0
-
 // SetAPIKeyByUserService sets an apikey from a user of a specific service
-func (s *Service) SetAPIKeyByUserService(gw2API *gw2api.Session, worldPerspective *int, platformID int, platformUserID string, primary bool, apikey string, ignoreRestrictions bool) (err error, userErr error) {
+func (s *Service) SetAPIKeyByUserService(idb bun.IDB, gw2API *gw2api.Session, worldPerspective *int, platformID int, platformUserID string, primary bool, apikey string, ignoreRestrictions bool) (err error, userErr error) {
 	ctx := context.Background()
-	tx, err := orm.DB().Begin()
+	tx, err := idb.BeginTx(ctx, nil)
 	if err != nil {
 		return errors.WithStack(err), nil
 	}
@@ -74,7 +72,7 @@ func (s *Service) SetAPIKeyByUserService(gw2API *gw2api.Session, worldPerspectiv
 		//}
 
 		// Additional restrictions
-		err = s.processRestrictions(gw2API, worldPerspective, acc, gw2Token, platformID, platformUserID)
+		err = s.processRestrictions(worldPerspective, acc, gw2Token, platformID, platformUserID)
 		if err != nil {
 			return err, err
 		}
@@ -90,21 +88,61 @@ func (s *Service) SetAPIKeyByUserService(gw2API *gw2api.Session, worldPerspectiv
 	}
 
 	// Synchronize WvW data
+	newAcc.FromGW2API(acc)
+	newAcc.MaxCharacterLevel = oldAcc.MaxCharacterLevel
+	// The sync time is not carried over, so the characters of a submitted account are always fetched again
+	newAcc.CharacterLevelSynced = oldAcc.CharacterLevelSynced
 	err = synchronizeAccountWvW(gw2API, &newAcc, gw2Token.Permissions)
 	if err != nil {
 		return err, nil
 	}
 
-	// Notify listeners if needed of verification changes (if any)
-	newAcc.FromGW2API(acc)
-	if s.em.ShouldEmitAccount(&oldAcc, &newAcc) {
-		err = orm.QueryGetUser(tx, user, user.Id).
-			Model(user).
-			Scan(ctx)
+	// Synchronize character levels
+	err = synchronizeAccountCharacters(gw2API, &newAcc, gw2Token.Permissions)
+	if err != nil {
+		return err, nil
+	}
+
+	// Check if the account meets the access policy. The account has just been fetched, so unknown data does not meet it
+	if !ignoreRestrictions {
+		if rule := s.verification.UnmetSubmissionRequirement(worldPerspective, &newAcc); rule != nil {
+			userErr = errors.New(rule.Requirement().Description)
+			return fmt.Errorf("account %s does not meet access requirement %s", acc.ID, rule.Name), userErr
+		}
+	}
+
+	// Both users have linked the account, so they are folded into a single user if configured to do so.
+	// Users holding other accounts are never merged automatically, as anyone with the api key would gain those accounts as well
+	accountMoved := oldAcc.ID != "" && oldAcc.UserID != user.Id
+	var merge *api.UserMerge
+	if accountMoved && config.Config().MergeUsersOnSharedAccount {
+		otherAccounts, err := tx.NewSelect().
+			Model((*api.Account)(nil)).
+			Where("user_id = ? AND id != ?", oldAcc.UserID, acc.ID).
+			Count(ctx)
+		if err != nil {
+			return errors.WithStack(err), nil
+		}
+		if otherAccounts == 0 {
+			merge = &api.UserMerge{
+				SourceUserID: oldAcc.UserID,
+				TargetUserID: user.Id,
+				Automatic:    true,
+				AccountID:    &acc.ID,
+			}
+		} else {
+			zap.L().Info("not merging users automatically, as the previous user has other accounts",
+				zap.String("account id", acc.ID),
+				zap.Int64("previous user id", oldAcc.UserID),
+				zap.Int64("user id", user.Id),
+				zap.Int("other accounts", otherAccounts))
+		}
+	}
+	if merge != nil {
+		err = verify.MergeUsers(tx, merge)
 		if err != nil {
 			return err, nil
 		}
-		s.em.Emit(user)
 	}
 
 	// Persist account info
@@ -114,6 +152,64 @@ func (s *Service) SetAPIKeyByUserService(gw2API *gw2api.Session, worldPerspectiv
 		return errors.WithStack(err), nil
 	}
 
+	// Record the link between the account and the user, so bans can reach every identity ever linked to the account
+	err = orm.RecordAccountUser(tx, acc.ID, user.Id)
+	if err != nil {
+		return err, nil
+	}
+
+	// Bans attached to the account follow it to every user it is linked to. This includes bans of users the account
+	// has been removed from, so removing or erasing an account and linking it from another identity does not evade a ban
+	copied, err := verify.CopyAccountBans(tx, acc.ID, user.Id)
+	if err != nil {
+		return err, nil
+	}
+	if copied > 0 {
+		zap.L().Info("copied account bans to new user",
+			zap.String("account id", acc.ID),
+			zap.Int64("user id", user.Id),
+			zap.Int("bans", copied))
+	}
+
+	if merge != nil {
+		// The previous user no longer exists
+		var target api.User
+		err = orm.QueryGetUser(tx, &target, merge.TargetUserID).
+			Scan(ctx)
+		if err != nil {
+			return err, nil
+		}
+		if err = s.em.Emit(tx, verify.MergedUser(merge, &target)); err != nil {
+			return err, nil
+		}
+	} else if accountMoved {
+		// The previous user no longer has access through the account
+		var oldUser api.User
+		err = orm.QueryGetUser(tx, &oldUser, oldAcc.UserID).
+			Scan(ctx)
+		if err != nil && err != sql.ErrNoRows {
+			return err, nil
+		}
+		if err == nil {
+			if err = s.em.Emit(tx, &oldUser); err != nil {
+				return err, nil
+			}
+		}
+	}
+
+	// Notify listeners if needed of verification changes (if any)
+	if accountMoved || copied > 0 || s.em.ShouldEmitAccount(&oldAcc, &newAcc) {
+		err = orm.QueryGetUser(tx, user, user.Id).
+			Model(user).
+			Scan(ctx)
+		if err != nil {
+			return err, nil
+		}
+		if err = s.em.Emit(tx, user); err != nil {
+			return err, nil
+		}
+	}
+
 	// Persist token info
 	token := orm.TokenInfo{
 		TokenInfo:   gw2Token,
@@ -133,23 +229,17 @@ func (s *Service) SetAPIKeyByUserService(gw2API *gw2api.Session, worldPerspectiv
 	}
 	committed = true
 
-	zap.L().Info("stored APIKey", zap.String("APIKey", apikey), zap.String("Account ID", acc.ID))
+	zap.L().Info("stored APIKey", zap.String("Account ID", acc.ID))
 	return nil, userErr
 }
 
-func (s *Service) processRestrictions(gw2api *gw2api.Session, worldPerspective *int, acc gw2api.Account, token gw2api.TokenInfo, platformID int, platformUserID string) (err error) {
+func (s *Service) processRestrictions(worldPerspective *int, acc gw2api.Account, token gw2api.TokenInfo, platformID int, platformUserID string) (err error) {
 	if config.Config().SkipRestrictions {
 		return nil
 	}
 	if err := s.processAPIKeyRestrictions(worldPerspective, acc, token, platformID, platformUserID); err != nil {
 		return err
 	}
-	if err := s.processAccountRestrictions(acc.Access, acc.World); err != nil {
-		return err
-	}
-	if err := s.processCharacterRestrictions(gw2api, acc); err != nil {
-		return err
-	}
 	return nil
 }
 
@@ -175,49 +265,3 @@ func (s *Service) processAPIKeyRestrictions(worldPerspective *int, acc gw2api.Ac
 
 	return err
 }
-
-func (s *Service) processAccountRestrictions(access []string, rank int) (err error) {
-	freeToPlay := IsFreeToPlay(access)
-
-	//FreeToPlay restrictions
-	if freeToPlay {
-		//Check if FreeToPlay player meets WvW rank requirements
-		if rank < FreeToPlayWvWRankRestriction {
-			return fmt.Errorf("You need to have WvW rank %d to verify yourself. This is required for all FreeToPlay accounts\nCurrent WvW rank: %d", FreeToPlayWvWRankRestriction, rank)
-		}
-	}
-	return err
-}
-
-func (s *Service) processCharacterRestrictions(gw2api *gw2api.Session, acc gw2api.Account) (err error) {
-	freeToPlay := IsFreeToPlay(acc.Access)
-
-	//FreeToPlay restrictions
-	if freeToPlay {
-		//Fetch character names
-		charNames, err := gw2api.Characters()
-		if err != nil {
-			return err
-		}
-
-		highestLevel := 0
-		for _, name := range charNames {
-			//Fetch characters
-			char, err := gw2api.CharacterCore(name)
-			if err != nil {
-				return err
-			}
-
-			if char.Level > highestLevel {
-				highestLevel = char.Level
-			}
-		}
-
-		//FreeToPlay level 80 retrictions
-		if highestLevel < 80 {
-			return fmt.Errorf("You need to be level 80 to verify yourself. This is required for all FreeToPlay accounts\nHighest character level found: %d", highestLevel)
-		}
-
-	}
-	return err
-}
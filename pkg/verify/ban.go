@@ -1,13 +1,24 @@
 package verify
 
 import (
+	"cmp"
 	"context"
+	"database/sql"
+	"slices"
 	"time"
 
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
 	"github.com/vennekilde/gw2verify/v2/internal/orm"
 )
 
+// Errors raised.
+var (
+	ErrBanNotFound  = errors.New("ban not found")
+	ErrUserNotFound = errors.New("user not found")
+)
+
 type BanService struct {
 	em *EventEmitter
 }
@@ -36,7 +47,7 @@ func GetBan(acc *api.Account) *orm.Ban {
 	ban := orm.Ban{}
 	result := orm.DB().NewSelect().
 		Model(&ban).
-		Where("user_id = ? AND until > NOW()", acc.UserID).
+		Where("(user_id = ? OR account_id = ?) AND until > NOW()", acc.UserID, acc.ID).
 		// Limit to longest lasting ban
 		Order("until desc").
 		Limit(1).
@@ -47,47 +58,346 @@ func GetBan(acc *api.Account) *orm.Ban {
 	return &ban
 }
 
-// BanServiceUser bans a user's gw2 account for the given duration
-func (bs *BanService) BanServiceUser(expiration time.Time, reason string, platformID int, platformUserId string) error {
+// BanServiceUser bans a user's gw2 accounts for the given duration
+func (bs *BanService) BanServiceUser(idb bun.IDB, expiration time.Time, reason string, platformID int, platformUserId string, serviceID string, allIdentities bool) ([]api.Ban, error) {
 	// Extract user id from service user information
 	link, err := orm.GetPlatformLink(platformID, platformUserId)
 	if err != nil {
-		return err
+		return nil, err
+	}
+	if link.UserID == 0 {
+		return nil, ErrUserNotFound
 	}
 
-	return bs.BanUser(expiration, reason, link.UserID)
+	return bs.BanUser(idb, expiration, reason, link.UserID, serviceID, allIdentities)
 }
 
-// BanServiceUser bans a user's gw2 account for the given duration
-func (bs *BanService) BanUser(expiration time.Time, reason string, userID int64) error {
+// BanUser bans a user's gw2 accounts for the given duration
+// A ban is issued per gw2 account, so the ban follows the account if it is linked to another user.
+// If allIdentities is set, every user that has ever been linked to one of the accounts is banned as well.
+// The bans share a group, so they are changed and lifted together
+func (bs *BanService) BanUser(idb bun.IDB, expiration time.Time, reason string, userID int64, serviceID string, allIdentities bool) ([]api.Ban, error) {
 	ctx := context.Background()
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
 
-	// Format ban
-	ban := orm.Ban{
-		Ban: api.Ban{
-			UserID: userID,
-			Until:  expiration,
-			Reason: reason,
-		},
+	var accounts []api.Account
+	err = tx.NewSelect().
+		Model(&accounts).
+		Where("user_id = ?", userID).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
 	}
-	// Persist ban
-	_, err := orm.DB().NewInsert().
-		Model(&ban).
+
+	var groupID int64
+	err = tx.NewSelect().
+		ColumnExpr("nextval('bans_group_id_seq')").
+		Scan(ctx, &groupID)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	// Format bans
+	bans := []orm.Ban{}
+	addBan := func(userID int64, accountID *string) {
+		bans = append(bans, orm.Ban{
+			Ban: api.Ban{
+				UserID:    userID,
+				AccountID: accountID,
+				GroupID:   groupID,
+				Until:     expiration,
+				Reason:    reason,
+				CreatedBy: &serviceID,
+			},
+		})
+	}
+	if len(accounts) == 0 {
+		addBan(userID, nil)
+	}
+	bannedUserIDs := []int64{userID}
+	for i := range accounts {
+		accountID := &accounts[i].ID
+		addBan(userID, accountID)
+
+		if allIdentities {
+			accountUserIDs, err := orm.GetAccountUserIDs(tx, *accountID)
+			if err != nil {
+				return nil, err
+			}
+			for _, accountUserID := range accountUserIDs {
+				if accountUserID != userID {
+					addBan(accountUserID, accountID)
+					if !slices.Contains(bannedUserIDs, accountUserID) {
+						bannedUserIDs = append(bannedUserIDs, accountUserID)
+					}
+				}
+			}
+		}
+	}
+
+	// Persist bans
+	_, err = tx.NewInsert().
+		Model(&bans).
+		Returning("*").
 		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for _, bannedUserID := range bannedUserIDs {
+		if err = bs.emitUser(tx, bannedUserID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed = true
 
+	apiBans := make([]api.Ban, len(bans))
+	for i := range bans {
+		apiBans[i] = bans[i].Ban
+	}
+	return apiBans, nil
+}
+
+// CopyAccountBans copies the active bans attached to a gw2 account to the user the account is now linked to.
+// The original bans are kept, so the ban follows the account without lifting it from the users the account was linked to before.
+// Bans stay attached to the account after the account itself is removed, so they are copied when it is linked again.
+// The copies join the group of the ban they are copied from
+func CopyAccountBans(idb bun.IDB, accountID string, userID int64) (copied int, err error) {
+	ctx := context.Background()
+	var bans []orm.Ban
+	err = idb.NewSelect().
+		Model(&bans).
+		Where("account_id = ? AND user_id != ? AND until > NOW() AND lifted_at IS NULL", accountID, userID).
+		// Skip bans the user already holds a copy of
+		Where("NOT EXISTS (?)", idb.NewSelect().
+			TableExpr("bans AS copy").
+			ColumnExpr("1").
+			Where("copy.account_id = bans.account_id AND copy.group_id = bans.group_id AND copy.user_id = ?", userID)).
+		Scan(ctx)
 	if err != nil {
-		return err
+		return 0, errors.WithStack(err)
+	}
+
+	// The same ban may have been copied to several previous users
+	seen := make(map[int64]bool, len(bans))
+	copies := make([]orm.Ban, 0, len(bans))
+	for i := range bans {
+		if seen[bans[i].GroupID] {
+			continue
+		}
+		seen[bans[i].GroupID] = true
+		copies = append(copies, orm.Ban{
+			Ban: api.Ban{
+				AccountID: bans[i].AccountID,
+				GroupID:   bans[i].GroupID,
+				CreatedBy: bans[i].CreatedBy,
+				Reason:    bans[i].Reason,
+				Until:     bans[i].Until,
+				UserID:    userID,
+			},
+		})
+	}
+	if len(copies) == 0 {
+		return 0, nil
 	}
 
-	// Gather all accounts associated with the now banned user
+	_, err = idb.NewInsert().
+		Model(&copies).
+		Exec(ctx)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	return len(copies), nil
+}
+
+// BanFilter narrows down the bans returned by GetBans
+type BanFilter struct {
+	Active *bool
+	UserID *int64
+	Reason *string
+	Limit  int
+	Offset int
+}
+
+// GetBans returns the bans matching the filter, newest first
+func GetBans(idb bun.IDB, filter BanFilter) (bans []api.Ban, err error) {
+	ctx := context.Background()
+	query := idb.NewSelect().
+		Model(&bans).
+		Order("db_created DESC", "id DESC").
+		Limit(filter.Limit).
+		Offset(filter.Offset)
+	if filter.Active != nil {
+		if *filter.Active {
+			query.Where("until > NOW()")
+		} else {
+			query.Where("until <= NOW()")
+		}
+	}
+	if filter.UserID != nil {
+		query.Where("user_id = ?", *filter.UserID)
+	}
+	if filter.Reason != nil {
+		query.Where("reason ILIKE ?", "%"+*filter.Reason+"%")
+	}
+	err = query.Scan(ctx)
+	if bans == nil {
+		bans = []api.Ban{}
+	}
+	return bans, errors.WithStack(err)
+}
+
+// GetUserBans returns every ban issued to the user, newest first
+func GetUserBans(idb bun.IDB, userID int64) ([]api.Ban, error) {
+	return GetBans(idb, BanFilter{UserID: &userID})
+}
+
+// GetBanByID returns the ban with the given id. ErrBanNotFound is returned if it does not exist
+func GetBanByID(idb bun.IDB, banID int64) (*api.Ban, error) {
+	ctx := context.Background()
+	ban := orm.Ban{}
+	err := idb.NewSelect().
+		Model(&ban).
+		Where("id = ?", banID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return nil, ErrBanNotFound
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	return &ban.Ban, nil
+}
+
+// GetBanGroup returns every ban of the group the ban with the given id belongs to. ErrBanNotFound is returned if it does not exist
+func GetBanGroup(idb bun.IDB, banID int64) ([]api.Ban, error) {
+	ctx := context.Background()
+	var bans []api.Ban
+	err := idb.NewSelect().
+		Model(&bans).
+		Where("group_id = (?)", banGroupID(idb, banID)).
+		Order("id").
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(bans) == 0 {
+		return nil, ErrBanNotFound
+	}
+	return bans, nil
+}
+
+// banGroupID returns a query selecting the group of the ban with the given id
+func banGroupID(idb bun.IDB, banID int64) *bun.SelectQuery {
+	return idb.NewSelect().
+		Model((*orm.Ban)(nil)).
+		Column("group_id").
+		Where("id = ?", banID)
+}
+
+// UpdateBan changes the expiration and/or the reason of every ban in the group of the ban
+func (bs *BanService) UpdateBan(idb bun.IDB, banID int64, until *time.Time, reason *string, serviceID string) ([]api.Ban, error) {
+	return bs.updateBanGroup(idb, banID, func(query *bun.UpdateQuery) {
+		query.Set("updated_by = ?", serviceID)
+		if until != nil {
+			query.Set("until = ?", *until)
+		}
+		if reason != nil {
+			query.Set("reason = ?", *reason)
+		}
+	})
+}
+
+// LiftBan ends every ban in the group of the ban immediately. Lifted bans are kept as part of the users' ban history
+func (bs *BanService) LiftBan(idb bun.IDB, banID int64, serviceID string) ([]api.Ban, error) {
+	return bs.updateBanGroup(idb, banID, func(query *bun.UpdateQuery) {
+		// Bans of the group that have already been lifted keep who lifted them and when
+		query.Set("lifted_by = COALESCE(lifted_by, ?)", serviceID).
+			Set("lifted_at = COALESCE(lifted_at, NOW())").
+			// Do not extend bans that have already expired
+			Set("until = LEAST(until, NOW())")
+	})
+}
+
+// updateBanGroup applies the changes to every ban in the group of the ban in a single transaction,
+// and emits every user holding one of the bans
+func (bs *BanService) updateBanGroup(idb bun.IDB, banID int64, changes func(query *bun.UpdateQuery)) ([]api.Ban, error) {
+	ctx := context.Background()
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	var bans []orm.Ban
+	query := tx.NewUpdate().
+		Model((*orm.Ban)(nil)).
+		Set("db_updated = NOW()").
+		Where("group_id = (?)", banGroupID(tx, banID)).
+		Returning("*")
+	changes(query)
+	err = query.Scan(ctx, &bans)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if len(bans) == 0 {
+		return nil, ErrBanNotFound
+	}
+
+	apiBans := make([]api.Ban, len(bans))
+	userIDs := make([]int64, 0, len(bans))
+	for i := range bans {
+		apiBans[i] = bans[i].Ban
+		if !slices.Contains(userIDs, bans[i].UserID) {
+			userIDs = append(userIDs, bans[i].UserID)
+		}
+	}
+	slices.SortFunc(apiBans, func(a, b api.Ban) int {
+		return cmp.Compare(a.ID, b.ID)
+	})
+	for _, userID := range userIDs {
+		if err = bs.emitUser(tx, userID); err != nil {
+			return nil, err
+		}
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed = true
+	return apiBans, nil
+}
+
+// emitUser emits the current state of the user, so listeners can react to ban changes
+func (bs *BanService) emitUser(idb bun.IDB, userID int64) error {
+	ctx := context.Background()
+
+	// Gather all accounts associated with the user
 	var user api.User
-	err = orm.QueryGetUser(orm.DB(), &user, userID).
-		Model(&user).
+	err := orm.QueryGetUser(idb, &user, userID).
 		Scan(ctx)
 	if err != nil {
-		return err
+		return errors.WithStack(err)
 	}
 
-	bs.em.Process(nil, &user)
-	return nil
+	return bs.em.Process(idb, nil, &user)
 }
@@ -0,0 +1,157 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// eventSequenceLock is the advisory lock held while sequence numbers are assigned to events
+const eventSequenceLock = 7301
+
+// Event is a user update persisted in the event log, allowing consumers to replay events they have missed
+type Event struct {
+	bun.BaseModel `bun:"table:events,alias:event"`
+
+	ID int64 `bun:",pk,autoincrement"`
+	// Seq is the position of the event in the event log, used as the cursor of consumers. It is assigned once the event has committed
+	Seq       *int64
+	DbCreated time.Time `bun:",nullzero,notnull,default:current_timestamp"`
+	UserID    int64
+	// PlatformIDs are the platforms the user is linked to, so events can be filtered by platform without inspecting the snapshot
+	PlatformIDs []int    `bun:"platform_ids,array"`
+	User        api.User `bun:"type:jsonb"`
+}
+
+func newEvent(user *api.User) Event {
+	platformIDs := []int{}
+	for _, link := range user.PlatformLinks {
+		if !slices.Contains(platformIDs, link.PlatformID) {
+			platformIDs = append(platformIDs, link.PlatformID)
+		}
+	}
+	return Event{
+		UserID:      user.Id,
+		PlatformIDs: platformIDs,
+		User:        *user,
+	}
+}
+
+// AppendEvent persists a snapshot of the user in the event log and returns the id of the event
+func AppendEvent(idb bun.IDB, user *api.User) (int64, error) {
+	ctx := context.Background()
+	event := newEvent(user)
+	_, err := idb.NewInsert().
+		Model(&event).
+		Returning("id").
+		Exec(ctx)
+	return event.ID, errors.WithStack(err)
+}
+
+// AppendEvents persists a snapshot of each of the users in the event log and returns the ids of the events in the same order
+func AppendEvents(idb bun.IDB, users []*api.User) ([]int64, error) {
+	ctx := context.Background()
+	if len(users) == 0 {
+		return nil, nil
+	}
+	events := make([]Event, len(users))
+	for i, user := range users {
+		events[i] = newEvent(user)
+	}
+	_, err := idb.NewInsert().
+		Model(&events).
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	eventIDs := make([]int64, len(events))
+	for i := range events {
+		eventIDs[i] = events[i].ID
+	}
+	return eventIDs, nil
+}
+
+// SequenceEvents assigns sequence numbers to the committed events that have none yet, in the order they were appended.
+// Sequence numbers are only assigned to committed events, one instance at a time, so a consumer that has seen a sequence number
+// has seen every lower one as well
+func SequenceEvents(db bun.IDB) error {
+	ctx := context.Background()
+	return db.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		_, err := tx.ExecContext(ctx, "SELECT pg_advisory_xact_lock(?)", eventSequenceLock)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+		// Volatile expressions are evaluated after sorting, so the sequence numbers follow the ids
+		_, err = tx.NewUpdate().
+			Model((*Event)(nil)).
+			TableExpr("(?) AS pending", tx.NewSelect().
+				Model((*Event)(nil)).
+				Column("id").
+				ColumnExpr("nextval('events_seq_seq') AS seq").
+				Where("seq IS NULL").
+				Order("id")).
+			Set("seq = pending.seq").
+			Where("event.id = pending.id").
+			Exec(ctx)
+		return errors.WithStack(err)
+	})
+}
+
+// LatestEventSeq returns the highest sequence number assigned to an event, or 0 if there is none
+func LatestEventSeq(idb bun.IDB) (int64, error) {
+	ctx := context.Background()
+	var seq int64
+	err := idb.NewSelect().
+		Model((*Event)(nil)).
+		ColumnExpr("COALESCE(MAX(seq), 0)").
+		Scan(ctx, &seq)
+	return seq, errors.WithStack(err)
+}
+
+// GetEventsAfter returns up to limit events with a sequence number higher than the cursor, in order
+func GetEventsAfter(idb bun.IDB, since int64, limit int) ([]Event, error) {
+	ctx := context.Background()
+	var events []Event
+	err := idb.NewSelect().
+		Model(&events).
+		Where("seq > ?", since).
+		Order("seq").
+		Limit(limit).
+		Scan(ctx)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	for i := range events {
+		events[i].User.EventID = events[i].Seq
+	}
+	return events, nil
+}
+
+// GetNextEvent returns the oldest event with a sequence number higher than the cursor, or nil if there is none
+// If platformID is provided, only events for users on the platform are considered
+func GetNextEvent(idb bun.IDB, since int64, platformID *int) (*Event, error) {
+	ctx := context.Background()
+	var event Event
+	query := idb.NewSelect().
+		Model(&event).
+		Where("seq > ?", since).
+		Order("seq").
+		Limit(1)
+	if platformID != nil {
+		query.Where("platform_ids @> ARRAY[?]::integer[]", *platformID)
+	}
+	err := query.Scan(ctx)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	event.User.EventID = event.Seq
+	return &event, nil
+}
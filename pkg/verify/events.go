@@ -1,30 +1,92 @@
 package verify
 
 import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
 	"go.uber.org/zap"
 )
 
+// listenerBufferSize is the amount of events buffered per listener before events are dropped
+const listenerBufferSize = 100
+
+// EventChannel is the postgres notification channel the id of every persisted event is sent on,
+// so the listeners of every instance receive the event regardless of which instance emitted it.
+// Transient events are sent as the encoded user instead of an id
+const EventChannel = "user_events"
+
+// eventPollInterval is how often the event log is checked for events whose notification was lost
+const eventPollInterval = 5 * time.Second
+
+// eventPublishBatchSize is the amount of events loaded at a time when sending events to the local listeners
+const eventPublishBatchSize = 100
+
+// maxNotificationPayload is the largest payload postgres accepts in a notification
+const maxNotificationPayload = 8000
+
+// localEventTTL is how long an event emitted by this instance waits for its notification, before it is assumed its transaction rolled back
+const localEventTTL = time.Hour
+
+// VerificationStatusListener receives verification updates for a single consumer connection
 type VerificationStatusListener struct {
-	ID               string
+	ID string
+	// ServiceID is the service the listener belongs to, as guilds may grant access to a specific service
+	ServiceID        string
 	WorldPerspective int
 	PlatformID       *int
-	ch               chan *api.VerificationStatus
+	// Statuses limits the listener to the given statuses. All statuses are emitted if empty
+	Statuses []api.Status
+	ch       chan *api.VerificationStatus
 }
 
+// C returns the channel the verification updates are sent on
+func (l *VerificationStatusListener) C() <-chan *api.VerificationStatus {
+	return l.ch
+}
+
+// Matches checks if the verification status passes the filters of the listener
+func (l *VerificationStatusListener) Matches(status *api.VerificationStatus) bool {
+	return len(l.Statuses) == 0 || slices.Contains(l.Statuses, status.Status)
+}
+
+// UserEventListener receives user updates for a single consumer connection
 type UserEventListener struct {
 	ID         string
 	PlatformID *int
 	ch         chan *api.User
 }
 
+// C returns the channel the user updates are sent on
+func (l *UserEventListener) C() <-chan *api.User {
+	return l.ch
+}
+
 var PlatformPollListeners map[int]VerificationStatusListener = make(map[int]VerificationStatusListener)
 
 type EventEmitter struct {
 	verification *Verification
 
+	mu              sync.RWMutex
+	listenerCount   int64
 	userListeners   map[string]*UserEventListener
 	statusListeners map[string]*VerificationStatusListener
+	hooks           []func(user *api.User, transient bool)
+
+	// localEvents holds the events emitted by this instance that have not been notified yet, so hooks are only called on this instance
+	localMu          sync.Mutex
+	localEvents      map[int64]time.Time
+	localEventsPrune time.Time
 }
 
 func NewEventEmitter(verification *Verification) *EventEmitter {
@@ -32,91 +94,378 @@ func NewEventEmitter(verification *Verification) *EventEmitter {
 		verification:    verification,
 		userListeners:   make(map[string]*UserEventListener),
 		statusListeners: make(map[string]*VerificationStatusListener),
+		localEvents:     make(map[int64]time.Time),
+	}
+}
+
+// OnEmit registers a hook that is called with every user emitted by this instance, once the event has been committed
+// Transient events are not persisted, so hooks must not persist them either
+func (em *EventEmitter) OnEmit(hook func(user *api.User, transient bool)) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	em.hooks = append(em.hooks, hook)
+}
+
+// nextListenerID returns a unique listener id for the service. Must be called while holding the lock
+func (em *EventEmitter) nextListenerID(serviceID string) string {
+	em.listenerCount++
+	return fmt.Sprintf("%s/%d", serviceID, em.listenerCount)
+}
+
+// SubscribeUsers registers a user listener for a single consumer connection
+// Every listener receives every event matching its filters. The listener must be unsubscribed once the consumer is done
+func (em *EventEmitter) SubscribeUsers(serviceID string, platformID *int) *UserEventListener {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	listener := &UserEventListener{
+		ID:         em.nextListenerID(serviceID),
+		PlatformID: platformID,
+		ch:         make(chan *api.User, listenerBufferSize),
+	}
+	em.userListeners[listener.ID] = listener
+	return listener
+}
+
+// UnsubscribeUsers stops emitting user updates to the listener
+func (em *EventEmitter) UnsubscribeUsers(listener *UserEventListener) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	delete(em.userListeners, listener.ID)
+}
+
+// SubscribeStatuses registers a verification listener for a single consumer connection
+// Every listener receives every event matching its filters. The listener must be unsubscribed once the consumer is done
+func (em *EventEmitter) SubscribeStatuses(serviceID string, platformID *int, worldPerspective int, statuses []api.Status) *VerificationStatusListener {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	listener := &VerificationStatusListener{
+		ID:               em.nextListenerID(serviceID),
+		ServiceID:        serviceID,
+		PlatformID:       platformID,
+		WorldPerspective: worldPerspective,
+		Statuses:         statuses,
+		ch:               make(chan *api.VerificationStatus, listenerBufferSize),
+	}
+	em.statusListeners[listener.ID] = listener
+	return listener
+}
+
+// UnsubscribeStatuses stops emitting verification updates to the listener
+func (em *EventEmitter) UnsubscribeStatuses(listener *VerificationStatusListener) {
+	em.mu.Lock()
+	defer em.mu.Unlock()
+	delete(em.statusListeners, listener.ID)
+}
+
+// Emit persists the event with idb and sends it to the listeners of every instance. Hooks are only called on this instance.
+// If idb is a transaction, the event is only persisted and sent once the transaction commits, and never if it rolls back.
+// A failure within a transaction aborts the transaction, so the error is returned for the caller to roll back
+func (em *EventEmitter) Emit(idb bun.IDB, user *api.User) error {
+	// Persist the event, so consumers can replay it if they miss it
+	eventID, err := AppendEvent(idb, user)
+	if err != nil {
+		if inTransaction(idb) {
+			return err
+		}
+		zap.L().Error("unable to persist event", zap.Int64("user id", user.Id), zap.Error(err))
+		em.publish(user)
+		em.callHooks(user, false)
+		return nil
 	}
+
+	return em.notify(idb, eventID)
+}
+
+// EmitTransient sends the event to the listeners of every instance without persisting it.
+// Used for events that must not be stored, such as the revocation of an erased identity
+func (em *EventEmitter) EmitTransient(user *api.User) {
+	em.notifyTransient(user)
+	em.callHooks(user, true)
 }
 
-func (em *EventEmitter) GetUserListener(id string, platformID *int) chan *api.User {
-	listener, ok := em.userListeners[id]
-	if !ok {
-		listener = &UserEventListener{
-			ID:         id,
-			PlatformID: platformID,
-			ch:         make(chan *api.User, 10),
+// EmitBatch emits a batch of users that changed together, persisting their events in a single insert
+func (em *EventEmitter) EmitBatch(users []*api.User) {
+	eventIDs, err := AppendEvents(orm.DB(), users)
+	if err != nil {
+		zap.L().Error("unable to persist batch of events", zap.Int("users", len(users)), zap.Error(err))
+		for _, user := range users {
+			em.publish(user)
+			em.callHooks(user, false)
 		}
-		em.userListeners[id] = listener
+		return
+	}
+	if len(eventIDs) == 0 {
+		return
 	}
 
-	return listener.ch
+	// A single notification is enough for the instances to pick up every event of the batch
+	for _, eventID := range eventIDs[:len(eventIDs)-1] {
+		em.trackLocalEvent(eventID)
+	}
+	em.notify(orm.DB(), eventIDs[len(eventIDs)-1])
 }
 
-func (em *EventEmitter) GetStatusListener(id string, platformID *int, worldPerspective int) chan *api.VerificationStatus {
-	listener, ok := em.statusListeners[id]
-	if !ok {
-		listener = &VerificationStatusListener{
-			ID:               id,
-			PlatformID:       platformID,
-			WorldPerspective: worldPerspective,
-			ch:               make(chan *api.VerificationStatus, 10),
+// notify announces a persisted event to every instance. Postgres delivers the notification once the transaction of idb commits,
+// at which point every instance sends the events committed since, and this instance calls the hooks.
+// Outside of a transaction, an event whose notification cannot be sent is picked up by the next notification or poll instead
+func (em *EventEmitter) notify(idb bun.IDB, eventID int64) error {
+	ctx := context.Background()
+	em.trackLocalEvent(eventID)
+	_, err := idb.ExecContext(ctx, "SELECT pg_notify(?, ?)", EventChannel, strconv.FormatInt(eventID, 10))
+	if err != nil {
+		if inTransaction(idb) {
+			em.untrackLocalEvent(eventID)
+			return errors.WithStack(err)
 		}
-		em.statusListeners[id] = listener
+		zap.L().Error("unable to notify instances of event", zap.Int64("event id", eventID), zap.Error(err))
 	}
+	return nil
+}
 
-	return listener.ch
+// trackLocalEvent remembers that the event was emitted by this instance. Events whose notification never arrived are forgotten after a while
+func (em *EventEmitter) trackLocalEvent(eventID int64) {
+	em.localMu.Lock()
+	defer em.localMu.Unlock()
+	now := time.Now()
+	em.localEvents[eventID] = now
+	if now.Sub(em.localEventsPrune) < localEventTTL {
+		return
+	}
+	em.localEventsPrune = now
+	for id, emitted := range em.localEvents {
+		if now.Sub(emitted) >= localEventTTL {
+			delete(em.localEvents, id)
+		}
+	}
 }
 
-func (em *EventEmitter) Emit(user *api.User) {
-	// Emit user updates
-	for _, listener := range em.userListeners {
-		if listener.PlatformID != nil {
-			// Check if user is on the platform
-			for _, link := range user.PlatformLinks {
-				if link.PlatformID == *listener.PlatformID {
-					goto exitLinkCheck
+// untrackLocalEvent forgets the event and reports whether it was emitted by this instance
+func (em *EventEmitter) untrackLocalEvent(eventID int64) bool {
+	em.localMu.Lock()
+	defer em.localMu.Unlock()
+	_, ok := em.localEvents[eventID]
+	delete(em.localEvents, eventID)
+	return ok
+}
+
+// inTransaction reports whether idb is a transaction
+func inTransaction(idb bun.IDB) bool {
+	_, ok := idb.(bun.Tx)
+	return ok
+}
+
+// notifyTransient sends a transient event to every instance. If the notification cannot be sent, the event is only sent to the local listeners
+func (em *EventEmitter) notifyTransient(user *api.User) {
+	ctx := context.Background()
+	payload, err := json.Marshal(user)
+	if err == nil && len(payload) > maxNotificationPayload {
+		err = fmt.Errorf("payload of %d bytes exceeds the notification limit", len(payload))
+	}
+	if err == nil {
+		_, err = orm.DB().ExecContext(ctx, "SELECT pg_notify(?, ?)", EventChannel, string(payload))
+	}
+	if err != nil {
+		zap.L().Error("unable to notify instances of transient event", zap.Error(err))
+		em.publish(user)
+	}
+}
+
+// ListenForEvents sends the events emitted by any instance, including this one, to the local listeners in the order of the event log
+// Note: this method will block the calling goroutine indefinitely
+func (em *EventEmitter) ListenForEvents() {
+	ctx := context.Background()
+	ln := pgdriver.NewListener(orm.DB())
+	defer ln.Close()
+	if err := ln.Listen(ctx, EventChannel); err != nil {
+		zap.L().Panic("unable to listen for events", zap.Error(err))
+	}
+	cursor, err := LatestEventSeq(orm.DB())
+	if err != nil {
+		zap.L().Panic("unable to find the end of the event log", zap.Error(err))
+	}
+
+	// Events whose notification was lost are picked up by polling
+	ticker := time.NewTicker(eventPollInterval)
+	defer ticker.Stop()
+	notifications := ln.Channel()
+	for {
+		select {
+		case notification, ok := <-notifications:
+			if !ok {
+				return
+			}
+			if strings.HasPrefix(notification.Payload, "{") {
+				var user api.User
+				if err := json.Unmarshal([]byte(notification.Payload), &user); err != nil {
+					zap.L().Error("received invalid transient event notification", zap.Error(err))
+					continue
 				}
+				em.publish(&user)
+				continue
+			}
+		case <-ticker.C:
+		}
+		cursor = em.publishEvents(cursor)
+	}
+}
+
+// publishEvents assigns sequence numbers to the committed events, and sends the events after the cursor to the local listeners in order.
+// Hooks are called for the events emitted by this instance. Returns the sequence number of the last event sent
+func (em *EventEmitter) publishEvents(cursor int64) int64 {
+	if err := SequenceEvents(orm.DB()); err != nil {
+		zap.L().Error("unable to sequence events", zap.Error(err))
+		return cursor
+	}
+	for {
+		events, err := GetEventsAfter(orm.DB(), cursor, eventPublishBatchSize)
+		if err != nil {
+			zap.L().Error("unable to load events", zap.Int64("cursor", cursor), zap.Error(err))
+			return cursor
+		}
+		for i := range events {
+			event := &events[i]
+			em.publish(&event.User)
+			if em.untrackLocalEvent(event.ID) {
+				em.callHooks(&event.User, false)
 			}
-			// If we got here, it means the user is not on the platform
+			cursor = *event.Seq
+		}
+		if len(events) < eventPublishBatchSize {
+			return cursor
+		}
+	}
+}
+
+// callHooks calls the hooks registered on this instance with the emitted user
+func (em *EventEmitter) callHooks(user *api.User, transient bool) {
+	em.mu.RLock()
+	hooks := slices.Clone(em.hooks)
+	em.mu.RUnlock()
+
+	for _, hook := range hooks {
+		hook(user, transient)
+	}
+}
+
+// publish sends a user event to the local listeners
+func (em *EventEmitter) publish(user *api.User) {
+	// Copy the listeners, so listeners can (un)subscribe while the event is emitted
+	em.mu.RLock()
+	userListeners := make([]*UserEventListener, 0, len(em.userListeners))
+	for _, listener := range em.userListeners {
+		userListeners = append(userListeners, listener)
+	}
+	em.mu.RUnlock()
+
+	// Emit user updates
+	for _, listener := range userListeners {
+		if !isOnPlatform(user, listener.PlatformID) {
 			continue
 		}
-	exitLinkCheck:
 
 		select {
 		case listener.ch <- user:
 		default:
-			zap.L().Error("unable to send verification update to listener", zap.Any("listener", listener))
+			zap.L().Error("unable to send user update to listener", zap.String("listener", listener.ID))
 		}
 	}
 
-	// Emit verification updates
-	var platformLink *api.PlatformLink
+	em.publishStatuses(user)
+}
+
+// publishStatuses sends the verification status of the user to the local verification listeners
+func (em *EventEmitter) publishStatuses(user *api.User) {
+	em.mu.RLock()
+	statusListeners := make([]*VerificationStatusListener, 0, len(em.statusListeners))
 	for _, listener := range em.statusListeners {
-		if listener.PlatformID != nil {
-			// Check if user is on the platform
-			for _, link := range user.PlatformLinks {
-				if link.PlatformID == *listener.PlatformID {
-					platformLink = &link
-					goto exitLinkCheck2
-				}
-			}
-			// If we got here, it means the user is not on the platform
+		statusListeners = append(statusListeners, listener)
+	}
+	em.mu.RUnlock()
+
+	// The status only depends on the service, world perspective and platform, so it is only computed once per combination
+	type statusKey struct {
+		service  string
+		world    int
+		platform int
+	}
+	statuses := make(map[statusKey]*api.VerificationStatus)
+	for _, listener := range statusListeners {
+		if !isOnPlatform(user, listener.PlatformID) {
 			continue
 		}
-	exitLinkCheck2:
 
-		status := api.VerificationStatus{
-			Status:       em.verification.Status(listener.WorldPerspective, user),
-			Ban:          GetActiveBan(user.Bans),
-			PlatformLink: platformLink,
+		key := statusKey{service: listener.ServiceID, world: listener.WorldPerspective, platform: -1}
+		if listener.PlatformID != nil {
+			key.platform = *listener.PlatformID
+		}
+		status, ok := statuses[key]
+		if !ok {
+			status = em.UserStatus(listener.ServiceID, user, listener.WorldPerspective, listener.PlatformID)
+			statuses[key] = status
+		}
+		if !listener.Matches(status) {
+			continue
 		}
 
 		select {
-		case listener.ch <- &status:
+		case listener.ch <- status:
 		default:
-			zap.L().Error("unable to send verification update to listener", zap.Any("listener", listener))
+			zap.L().Error("unable to send verification update to listener", zap.String("listener", listener.ID))
 		}
 	}
 }
 
-func (em *EventEmitter) Process(oldUser *api.User, newUser *api.User) {
+// StatusListenerWorlds returns the world perspectives that verification listeners are currently subscribed to
+func (em *EventEmitter) StatusListenerWorlds() []int {
+	em.mu.RLock()
+	defer em.mu.RUnlock()
+	worlds := []int{}
+	for _, listener := range em.statusListeners {
+		if !slices.Contains(worlds, listener.WorldPerspective) {
+			worlds = append(worlds, listener.WorldPerspective)
+		}
+	}
+	return worlds
+}
+
+// UserStatus returns the verification status of the user as emitted to listeners of the service on the platform
+func (em *EventEmitter) UserStatus(serviceUUID string, user *api.User, worldPerspective int, platformID *int) *api.VerificationStatus {
+	status := em.verification.VerificationStatus(serviceUUID, worldPerspective, user)
+	status.EventID = user.EventID
+	if platformID != nil {
+		for i := range user.PlatformLinks {
+			if user.PlatformLinks[i].PlatformID == *platformID {
+				status.PlatformLink = &user.PlatformLinks[i]
+				break
+			}
+		}
+	}
+	return &status
+}
+
+// NextEvent returns the oldest user event in the event log with a sequence number higher than the cursor, or nil if there is none
+func (em *EventEmitter) NextEvent(since int64, platformID *int) (*api.User, error) {
+	event, err := GetNextEvent(orm.DB(), since, platformID)
+	if err != nil || event == nil {
+		return nil, err
+	}
+	return &event.User, nil
+}
+
+// isOnPlatform checks if the user is linked to the platform. All users are on the platform if it is nil
+func isOnPlatform(user *api.User, platformID *int) bool {
+	if platformID == nil {
+		return true
+	}
+	for _, link := range user.PlatformLinks {
+		if link.PlatformID == *platformID {
+			return true
+		}
+	}
+	return false
+}
+
+func (em *EventEmitter) Process(idb bun.IDB, oldUser *api.User, newUser *api.User) error {
 	shouldEmit := false
 
 	if oldUser == nil && newUser != nil {
@@ -130,8 +479,9 @@ func (em *EventEmitter) Process(oldUser *api.User, newUser *api.User) {
 	}
 
 	if shouldEmit {
-		em.Emit(newUser)
+		return em.Emit(idb, newUser)
 	}
+	return nil
 }
 
 func (em *EventEmitter) ShouldEmitAccounts(oldAccounts []api.Account, newAccounts []api.Account) bool {
@@ -163,6 +513,16 @@ func (em *EventEmitter) ShouldEmitAccount(oldAccount *api.Account, newAccount *a
 		return true
 	} else if oldAccount.Name != newAccount.Name {
 		return true
+	} else if oldAccount.WvWTeamID != newAccount.WvWTeamID {
+		return true
+	} else if !equalPtr(oldAccount.WvWGuildID, newAccount.WvWGuildID) {
+		// Leaving the WvW guild revokes alliance access, so a guild changing to nil is a change as well
+		return true
+	} else if !slices.Equal(derefStrings(oldAccount.Guilds), derefStrings(newAccount.Guilds)) {
+		return true
+	} else if !slices.Equal(derefStrings(oldAccount.GuildLeader), derefStrings(newAccount.GuildLeader)) {
+		// Guild rules are met by leading a guild as well
+		return true
 	} else if newAccount.Expired != nil && *newAccount.Expired {
 		return true
 	}
@@ -189,3 +549,18 @@ outerLoop:
 
 	return false
 }
+
+// equalPtr compares the values of two pointers, where nil only equals nil
+func equalPtr[T comparable](a *T, b *T) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+func derefStrings(s *[]string) []string {
+	if s == nil {
+		return nil
+	}
+	return *s
+}
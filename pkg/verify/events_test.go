@@ -0,0 +1,77 @@
+package verify
+
+import (
+	"testing"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+func TestShouldEmitAccount(t *testing.T) {
+	alliance := "alliance-a"
+	otherAlliance := "alliance-b"
+	guilds := []string{"guild-a"}
+	otherGuilds := []string{"guild-b"}
+	expired := true
+	base := api.Account{
+		ID:          "account",
+		Name:        "Account.1234",
+		World:       2001,
+		WvWTeamID:   12001,
+		WvWGuildID:  &alliance,
+		Guilds:      &guilds,
+		GuildLeader: &guilds,
+	}
+	tests := []struct {
+		name   string
+		change func(acc *api.Account)
+		want   bool
+	}{
+		{"unchanged", func(acc *api.Account) {}, false},
+		{"unrelated change", func(acc *api.Account) { acc.WvWRank++ }, false},
+		{"world", func(acc *api.Account) { acc.World = 2002 }, true},
+		{"name", func(acc *api.Account) { acc.Name = "Renamed.1234" }, true},
+		{"wvw team", func(acc *api.Account) { acc.WvWTeamID = 12002 }, true},
+		{"wvw guild changed", func(acc *api.Account) { acc.WvWGuildID = &otherAlliance }, true},
+		{"wvw guild left", func(acc *api.Account) { acc.WvWGuildID = nil }, true},
+		{"guilds", func(acc *api.Account) { acc.Guilds = &otherGuilds }, true},
+		{"guild leader", func(acc *api.Account) { acc.GuildLeader = nil }, true},
+		{"expired", func(acc *api.Account) { acc.Expired = &expired }, true},
+	}
+	em := NewEventEmitter(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			oldAcc := base
+			newAcc := base
+			test.change(&newAcc)
+			if got := em.ShouldEmitAccount(&oldAcc, &newAcc); got != test.want {
+				t.Errorf("ShouldEmitAccount() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestShouldEmitAccounts(t *testing.T) {
+	a := api.Account{ID: "a", World: 2001}
+	b := api.Account{ID: "b", World: 2002}
+	movedB := api.Account{ID: "b", World: 2003}
+	tests := []struct {
+		name        string
+		oldAccounts []api.Account
+		newAccounts []api.Account
+		want        bool
+	}{
+		{"unchanged", []api.Account{a, b}, []api.Account{b, a}, false},
+		{"added", []api.Account{a}, []api.Account{a, b}, true},
+		{"removed", []api.Account{a, b}, []api.Account{a}, true},
+		{"replaced", []api.Account{a}, []api.Account{b}, true},
+		{"changed", []api.Account{a, b}, []api.Account{a, movedB}, true},
+	}
+	em := NewEventEmitter(nil)
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := em.ShouldEmitAccounts(test.oldAccounts, test.newAccounts); got != test.want {
+				t.Errorf("ShouldEmitAccounts() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
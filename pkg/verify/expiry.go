@@ -0,0 +1,133 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/cluster"
+	"go.uber.org/zap"
+)
+
+// ExpiryScheduler emits events for users whose access changes as time passes
+// This covers temporary access and bans running out, and accounts that are no longer refreshed within the expiration time
+// Only the leader of the election emits expirations, as emitted events reach the listeners of every instance
+type ExpiryScheduler struct {
+	em       *EventEmitter
+	election *cluster.Election
+	// lastCheck is the point in time expirations have been emitted up to
+	lastCheck time.Time
+}
+
+func NewExpiryScheduler(em *EventEmitter, election *cluster.Election) *ExpiryScheduler {
+	return &ExpiryScheduler{
+		em:       em,
+		election: election,
+	}
+}
+
+// Start emits expirations as they happen
+// Note: this method will block the calling goroutine indefinitely
+func (es *ExpiryScheduler) Start() {
+	es.lastCheck = time.Now()
+	for {
+		// Sleep until the next known expiration. Expirations created in the meantime are picked up after at most the max wait
+		sleepUntil := time.Now().Add(config.Config().ExpiryMaxWait)
+		next, err := NextExpiration(orm.DB())
+		if err != nil {
+			zap.L().Error("unable to find next expiration", zap.Error(err))
+		} else if next != nil && next.Before(sleepUntil) {
+			sleepUntil = *next
+		}
+		time.Sleep(time.Until(sleepUntil))
+
+		now := time.Now()
+		if !es.election.IsLeader() {
+			// The leader emits the expirations up to now, so a new leader continues from here
+			es.lastCheck = now
+			continue
+		}
+		if err = es.EmitExpirations(es.lastCheck, now); err != nil {
+			zap.L().Error("unable to emit expirations", zap.Error(err))
+			continue
+		}
+		es.lastCheck = now
+	}
+}
+
+// EmitExpirations emits the users that had anything expire in the time range (from, to]
+func (es *ExpiryScheduler) EmitExpirations(from time.Time, to time.Time) error {
+	ctx := context.Background()
+	userIDs, err := ExpiredUserIDs(orm.DB(), from, to)
+	if err != nil {
+		return err
+	}
+
+	for _, userID := range userIDs {
+		var user api.User
+		err = orm.QueryGetUser(orm.DB(), &user, userID).
+			Scan(ctx)
+		if err == sql.ErrNoRows {
+			continue
+		} else if err != nil {
+			return errors.WithStack(err)
+		}
+		if err = es.em.Emit(orm.DB(), &user); err != nil {
+			return err
+		}
+	}
+
+	if len(userIDs) > 0 {
+		zap.L().Info("emitted expirations", zap.Int("users", len(userIDs)), zap.Time("from", from), zap.Time("to", to))
+	}
+	return nil
+}
+
+// accountExpiration is how long an account is considered valid after it was last refreshed
+func accountExpiration() time.Duration {
+	return time.Duration(config.Config().ExpirationTime) * time.Second
+}
+
+// NextExpiration returns the next point in time temporary access, a ban or an account expires, if any
+func NextExpiration(idb bun.IDB) (*time.Time, error) {
+	ctx := context.Background()
+	var next sql.NullTime
+	err := idb.NewRaw(`SELECT MIN(t) FROM (
+			SELECT MIN(until) AS t FROM ephemeral_associations WHERE until > NOW()
+			UNION ALL
+			SELECT MIN(until) AS t FROM bans WHERE until > NOW()
+			UNION ALL
+			SELECT MIN(db_updated) + ? * interval '1 second' AS t FROM accounts WHERE db_updated > ?
+		) AS expirations`,
+		config.Config().ExpirationTime,
+		time.Now().Add(-accountExpiration())).
+		Scan(ctx, &next)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	if !next.Valid {
+		return nil, nil
+	}
+	return &next.Time, nil
+}
+
+// ExpiredUserIDs returns the ids of users that had temporary access, a ban or an account expire in the time range (from, to]
+// Bans that have been lifted are excluded, as lifting a ban emits an event on its own
+func ExpiredUserIDs(idb bun.IDB, from time.Time, to time.Time) (userIDs []int64, err error) {
+	ctx := context.Background()
+	err = idb.NewRaw(`SELECT user_id FROM ephemeral_associations WHERE until > ? AND until <= ?
+		UNION
+		SELECT user_id FROM bans WHERE until > ? AND until <= ? AND lifted_at IS NULL
+		UNION
+		SELECT user_id FROM accounts WHERE db_updated > ? AND db_updated <= ?`,
+		from, to,
+		from, to,
+		from.Add(-accountExpiration()), to.Add(-accountExpiration())).
+		Scan(ctx, &userIDs)
+	return userIDs, errors.WithStack(err)
+}
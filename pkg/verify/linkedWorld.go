@@ -1,28 +1,53 @@
 package verify
 
 import (
+	"context"
 	"errors"
+	"slices"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/MrGunflame/gw2api"
+	"github.com/uptrace/bun/driver/pgdriver"
 	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"github.com/vennekilde/gw2verify/v2/pkg/cluster"
 	"go.uber.org/zap"
 )
 
+// followerRefreshInterval is how often instances that are not the leader pick up the matchups synchronized by the leader,
+// if they are not notified of new matchups
+const followerRefreshInterval = time.Minute
+
+// MatchupChannel is the postgres notification channel the leader notifies the other instances on, once it has persisted new matchups
+const MatchupChannel = "matchups"
+
 type worldSyncError error
 
 type LinkedWorlds map[string]api.WorldLinks
 
+// WorldTeams maps a world id to the WvW team id it is currently playing on
+type WorldTeams map[int]int
+
+// matchColors are the colors of the three sides in a matchup
+var matchColors = []string{"red", "blue", "green"}
+
 // Errors raised.
 var (
 	ErrWorldsNotSynced worldSyncError = errors.New("worlds are not synched")
 )
 
 type Worlds struct {
+	// mu guards the world links, which are replaced while they are read by requests, the scheduler and the event emitter.
+	// The maps are never modified once installed, so they can be handed out without copying
+	mu                 sync.RWMutex
 	linkedWorlds       LinkedWorlds
+	worldTeams         WorldTeams
 	lastEndTime        time.Time
+	lastResetTime      time.Time
 	isWorldLinksSynced bool
+	hooks              []func(previous *Worlds, synchronized bool)
 
 	gw2API *gw2api.Session
 }
@@ -33,27 +58,63 @@ func NewWorlds(gw2API *gw2api.Session) *Worlds {
 	}
 }
 
-func (ws *Worlds) Start() {
+// OnMatchupChange registers a hook that is called with a snapshot of the previous world links, when new world links are installed.
+// The hook is called on every instance. synchronized is only set on the instance that synchronized the matchups from the gw2 api.
+// The hook is not called when the world links are set for the first time
+func (ws *Worlds) OnMatchupChange(hook func(previous *Worlds, synchronized bool)) {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	ws.hooks = append(ws.hooks, hook)
+}
+
+// Start synchronizes the matchups whenever a matchup ends. Only the leader of the election polls the gw2 api,
+// the other instances restore the matchups the leader has persisted as soon as the leader notifies them
+// Note: this method will block the calling goroutine indefinitely
+func (ws *Worlds) Start(election *cluster.Election) {
+	ctx := context.Background()
+	ln := pgdriver.NewListener(orm.DB())
+	defer ln.Close()
+	if err := ln.Listen(ctx, MatchupChannel); err != nil {
+		zap.L().Error("unable to listen for matchups, relying on polling", zap.Error(err))
+	}
+	notifications := ln.Channel()
+
 	for {
+		if !election.IsLeader() {
+			if err := ws.RestoreWorldLinks(); err != nil {
+				zap.L().Error("unable to restore linked worlds", zap.Error(err))
+			}
+			select {
+			case <-notifications:
+			case <-time.After(followerRefreshInterval):
+			}
+			continue
+		}
+		// The leader is notified of its own matchups as well
+		drainNotifications(notifications)
+
 		zap.L().Info("synchronizing linked worlds")
 		if err := ws.SynchronizeWorldLinks(ws.gw2API); err != nil {
 			zap.L().Error("unable to synchronize matchup", zap.Error(err))
 		}
 
-		if !ws.lastEndTime.IsZero() {
+		if lastEndTime := ws.matchupEndTime(); !lastEndTime.IsZero() {
 			// Sleep until next match
-			sleepUntil := time.Until(ws.lastEndTime)
+			sleepUntil := time.Until(lastEndTime)
 			zap.L().Info("synchronizing linked worlds once matchup is over",
 				zap.Duration("synchronizing timer", sleepUntil),
-				zap.Time("endtime", ws.lastEndTime))
+				zap.Time("endtime", lastEndTime))
 			// Sleep for at least a minute to not spam the api
 			if sleepUntil < time.Minute {
 				sleepUntil = time.Minute
 			}
-			time.Sleep(sleepUntil)
+			// Another instance takes over polling if leadership is lost while sleeping
+			if !election.SleepWhileLeader(sleepUntil) {
+				zap.L().Info("no longer leader, stopped synchronizing linked worlds")
+			}
 		} else {
 			zap.L().Info("synchronizing linked worlds in 5 minutes")
-			time.Sleep(time.Minute * 5)
+			election.SleepWhileLeader(time.Minute * 5)
 		}
 	}
 }
@@ -67,6 +128,8 @@ func (ws *Worlds) SynchronizeWorldLinks(gw2API *gw2api.Session) error {
 	// Sanity check before we go and reset world links before we actually have a new matchup
 	if len(matches) > 0 {
 		lw := createEmptyLinkedWorldsMap()
+		wt := make(WorldTeams)
+		matchups := make([]api.Matchup, 0, len(matches)*len(matchColors))
 		// reset timer to avoid it not being changed by the loop
 		lowestEndTime := time.Time{}
 		foundWorlds := 0
@@ -78,16 +141,22 @@ func (ws *Worlds) SynchronizeWorldLinks(gw2API *gw2api.Session) error {
 				zap.Any("blues", match.AllWorlds["blue"]),
 				zap.Any("greens", match.AllWorlds["green"]))
 
-			// Persist world link
-			lw.setWorldLinks(match.AllWorlds["red"])
-			lw.setWorldLinks(match.AllWorlds["blue"])
-			lw.setWorldLinks(match.AllWorlds["green"])
-			// bump found world counter
-			foundWorlds += len(match.AllWorlds["red"]) +
-				len(match.AllWorlds["blue"]) +
-				len(match.AllWorlds["green"])
+			for _, color := range matchColors {
+				team := sideTeamID(match.Worlds[color], match.AllWorlds[color])
+				// Persist world link
+				lw.setWorldLinks(match.AllWorlds[color])
+				// Persist world teams
+				wt.setWorldTeams(team, match.AllWorlds[color])
+				// bump found world counter
+				foundWorlds += len(match.AllWorlds[color])
+			}
 
-			// Parse match end time
+			// Parse match start & end time
+			matchStartTime, err := time.Parse(time.RFC3339, match.StartTime)
+			if err != nil {
+				zap.L().Error("unable to parse matchup start time", zap.Error(err))
+				continue
+			}
 			matchEndTime, err := time.Parse(time.RFC3339, match.EndTime)
 			if err != nil {
 				zap.L().Error("unable to parse matchup end time", zap.Error(err))
@@ -97,11 +166,35 @@ func (ws *Worlds) SynchronizeWorldLinks(gw2API *gw2api.Session) error {
 			if lowestEndTime.IsZero() || lowestEndTime.After(matchEndTime) {
 				lowestEndTime = matchEndTime
 			}
+
+			for _, color := range matchColors {
+				matchups = append(matchups, api.Matchup{
+					MatchID:   match.ID,
+					Color:     color,
+					StartTime: matchStartTime,
+					EndTime:   matchEndTime,
+					TeamID:    sideTeamID(match.Worlds[color], match.AllWorlds[color]),
+					Worlds:    match.AllWorlds[color],
+				})
+			}
 		}
 		// Only update if we can find all worlds
 		if foundWorlds >= len(WorldNames) {
-			ws.setMatchupLinks(lw, lowestEndTime)
-			zap.L().Info("Updated linked worlds", zap.Any("linked worlds", ws.linkedWorlds))
+			// Persist the matchups before installing them, so the world links can be restored after a restart,
+			// and the other instances can install them as soon as they are notified
+			persistErr := PersistMatchups(orm.DB(), matchups)
+			ws.setMatchupLinks(lw, wt, lowestEndTime)
+			zap.L().Info("Updated linked worlds",
+				zap.Any("linked worlds", lw),
+				zap.Any("world teams", wt))
+			if persistErr != nil {
+				return persistErr
+			}
+
+			_, err = orm.DB().ExecContext(context.Background(), "SELECT pg_notify(?, '')", MatchupChannel)
+			if err != nil {
+				zap.L().Error("unable to notify instances of new matchups", zap.Error(err))
+			}
 		} else {
 			zap.L().Warn("not updating linked worlds, did not find all worlds in matchups",
 				zap.Int("total worlds", len(WorldNames)),
@@ -112,10 +205,91 @@ func (ws *Worlds) SynchronizeWorldLinks(gw2API *gw2api.Session) error {
 	return nil
 }
 
-func (ws *Worlds) setMatchupLinks(lw LinkedWorlds, lowestEndTime time.Time) {
+// RestoreWorldLinks restores the world links from the persisted matchups that have yet to end
+// This allows verifying linked worlds before the matchups have been synchronized after a restart,
+// and lets instances that are not the leader pick up the matchups synchronized by the leader
+func (ws *Worlds) RestoreWorldLinks() error {
+	matchups, err := GetCurrentMatchups(orm.DB())
+	if err != nil {
+		return err
+	}
+	if len(matchups) == 0 {
+		zap.L().Info("no persisted matchups to restore linked worlds from")
+		return nil
+	}
+
+	lw := createEmptyLinkedWorldsMap()
+	wt := make(WorldTeams)
+	lowestEndTime := time.Time{}
+	for _, matchup := range matchups {
+		lw.setWorldLinks(matchup.Worlds)
+		wt.setWorldTeams(matchup.TeamID, matchup.Worlds)
+		if lowestEndTime.IsZero() || lowestEndTime.After(matchup.EndTime) {
+			lowestEndTime = matchup.EndTime
+		}
+	}
+
+	if ws.IsWorldLinksSynchronized() && lowestEndTime.Equal(ws.matchupEndTime()) {
+		// Already up to date
+		return nil
+	}
+	previous := ws.installMatchupLinks(lw, wt, lowestEndTime)
+	zap.L().Info("restored linked worlds from persisted matchups",
+		zap.Any("linked worlds", lw),
+		zap.Any("world teams", wt),
+		zap.Time("endtime", lowestEndTime))
+	ws.callHooks(previous, false)
+	return nil
+}
+
+func (ws *Worlds) setMatchupLinks(lw LinkedWorlds, wt WorldTeams, lowestEndTime time.Time) {
+	previous := ws.installMatchupLinks(lw, wt, lowestEndTime)
+	ws.callHooks(previous, true)
+}
+
+// callHooks calls the matchup change hooks, unless the previous world links were never set
+func (ws *Worlds) callHooks(previous *Worlds, synchronized bool) {
+	if !previous.isWorldLinksSynced {
+		return
+	}
+	ws.mu.RLock()
+	hooks := slices.Clone(ws.hooks)
+	ws.mu.RUnlock()
+	for _, hook := range hooks {
+		hook(previous, synchronized)
+	}
+}
+
+// drainNotifications discards the notifications that have been received
+func drainNotifications(notifications <-chan pgdriver.Notification) {
+	for {
+		select {
+		case <-notifications:
+		default:
+			return
+		}
+	}
+}
+
+// installMatchupLinks replaces the world links and returns a snapshot of the previous world links
+func (ws *Worlds) installMatchupLinks(lw LinkedWorlds, wt WorldTeams, lowestEndTime time.Time) *Worlds {
+	ws.mu.Lock()
+	defer ws.mu.Unlock()
+	previous := &Worlds{
+		linkedWorlds:       ws.linkedWorlds,
+		worldTeams:         ws.worldTeams,
+		lastEndTime:        ws.lastEndTime,
+		isWorldLinksSynced: ws.isWorldLinksSynced,
+	}
+
+	if !ws.lastEndTime.IsZero() && lowestEndTime.After(ws.lastEndTime) {
+		ws.lastResetTime = ws.lastEndTime
+	}
 	ws.linkedWorlds = lw
+	ws.worldTeams = wt
 	ws.lastEndTime = lowestEndTime
 	ws.isWorldLinksSynced = true
+	return previous
 }
 
 func (lw LinkedWorlds) setWorldLinks(allWorlds []int) {
@@ -130,21 +304,85 @@ func (lw LinkedWorlds) setWorldLinks(allWorlds []int) {
 	}
 }
 
+// sideTeamID finds the team id of a side of a matchup
+// The team id is either the main world of the side, or listed among all the worlds of the side. 0 if not found
+func sideTeamID(mainWorld int, allWorlds []int) int {
+	if IsTeamID(mainWorld) {
+		return mainWorld
+	}
+	for _, worldID := range allWorlds {
+		if IsTeamID(worldID) {
+			return worldID
+		}
+	}
+	return 0
+}
+
+// setWorldTeams maps every world on a side of a matchup to the team id of that side
+func (wt WorldTeams) setWorldTeams(team int, allWorlds []int) {
+	if team == 0 {
+		return
+	}
+	for _, worldID := range allWorlds {
+		if !IsTeamID(worldID) {
+			wt[worldID] = team
+		}
+	}
+}
+
+// LastMatchupReset returns when the matchup last reset, which may have happened before the new world links are synchronized.
+// Zero if no reset has been observed
+func (ws *Worlds) LastMatchupReset() time.Time {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	if !ws.lastEndTime.IsZero() && time.Now().After(ws.lastEndTime) {
+		return ws.lastEndTime
+	}
+	return ws.lastResetTime
+}
+
 func (ws *Worlds) IsWorldLinksSynchronized() bool {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
 	return ws.isWorldLinksSynced
 }
 
+// matchupEndTime returns when the earliest ending matchup of the current world links ends
+func (ws *Worlds) matchupEndTime() time.Time {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	return ws.lastEndTime
+}
+
 func (ws *Worlds) GetWorldLinks(worldPerspective int) (links []int, err error) {
-	if !ws.IsWorldLinksSynchronized() {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	if !ws.isWorldLinksSynced {
 		return links, ErrWorldsNotSynced
 	}
 	return ws.linkedWorlds[strconv.Itoa(worldPerspective)], err
 }
 
 func (ws *Worlds) GetAllWorldLinks() LinkedWorlds {
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
 	return ws.linkedWorlds
 }
 
+// GetWorldTeam returns the WvW team the world perspective is currently playing on
+// If the world perspective is a team id, it is returned as is. 0 is returned if the team is unknown
+func (ws *Worlds) GetWorldTeam(worldPerspective int) (team int, err error) {
+	if IsTeamID(worldPerspective) {
+		return worldPerspective, nil
+	}
+	ws.mu.RLock()
+	defer ws.mu.RUnlock()
+	if !ws.isWorldLinksSynced {
+		return team, ErrWorldsNotSynced
+	}
+	return ws.worldTeams[worldPerspective], err
+}
+
 func createEmptyLinkedWorldsMap() LinkedWorlds {
 	newLinkedWorlds := make(LinkedWorlds)
 	for worldID := range WorldNames {
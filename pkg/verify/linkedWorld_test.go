@@ -0,0 +1,107 @@
+package verify
+
+import (
+	"maps"
+	"testing"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+func TestSideTeamID(t *testing.T) {
+	tests := []struct {
+		name      string
+		mainWorld int
+		allWorlds []int
+		want      int
+	}{
+		{"main world is team", 11001, []int{11001, 2001}, 11001},
+		{"team among worlds", 2001, []int{2001, 12003, 2002}, 12003},
+		{"no team", 2001, []int{2001, 2002}, 0},
+		{"no worlds", 0, nil, 0},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := sideTeamID(test.mainWorld, test.allWorlds); got != test.want {
+				t.Errorf("sideTeamID() = %d, want %d", got, test.want)
+			}
+		})
+	}
+}
+
+func TestSetWorldTeams(t *testing.T) {
+	tests := []struct {
+		name      string
+		team      int
+		allWorlds []int
+		want      WorldTeams
+	}{
+		{"worlds mapped to team", 12001, []int{12001, 2001, 2002}, WorldTeams{2001: 12001, 2002: 12001}},
+		{"unknown team", 0, []int{2001, 2002}, WorldTeams{}},
+		{"only team", 12001, []int{12001}, WorldTeams{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			wt := WorldTeams{}
+			wt.setWorldTeams(test.team, test.allWorlds)
+			if !maps.Equal(wt, test.want) {
+				t.Errorf("setWorldTeams() = %v, want %v", wt, test.want)
+			}
+		})
+	}
+}
+
+func TestAccountTeamStatus(t *testing.T) {
+	v := &Verification{
+		worlds: &Worlds{
+			isWorldLinksSynced: true,
+			worldTeams:         WorldTeams{2001: 12001, 2002: 12002},
+		},
+	}
+	tests := []struct {
+		name             string
+		worldPerspective int
+		team             int
+		ephemeral        bool
+		want             api.Status
+	}{
+		{"same team", 2001, 12001, false, api.ACCESS_GRANTED_WVW_TEAM},
+		{"same team temporary", 2001, 12001, true, api.ACCESS_GRANTED_WVW_TEAM_TEMPORARY},
+		{"team perspective", 12002, 12002, false, api.ACCESS_GRANTED_WVW_TEAM},
+		{"other team", 2001, 12002, false, api.ACCESS_DENIED_INVALID_WORLD},
+		{"unknown team", 2001, 0, false, api.ACCESS_DENIED_UNKNOWN},
+		{"perspective without team", 2003, 12001, false, api.ACCESS_DENIED_UNKNOWN},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := v.AccountTeamStatus(test.worldPerspective, test.team, test.ephemeral); got != test.want {
+				t.Errorf("AccountTeamStatus() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAccountAllianceStatus(t *testing.T) {
+	alliance := "ALLIANCE-A"
+	otherAlliance := "alliance-b"
+	v := &Verification{
+		alliances: ParseWorldGuilds([]string{"2001:alliance-a", " 2002 : alliance-c "}),
+	}
+	tests := []struct {
+		name             string
+		worldPerspective int
+		wvwGuildID       *string
+		want             api.Status
+	}{
+		{"alliance", 2001, &alliance, api.ACCESS_GRANTED_WVW_ALLIANCE},
+		{"other alliance", 2001, &otherAlliance, api.ACCESS_DENIED_INVALID_WORLD},
+		{"no wvw guild", 2001, nil, api.ACCESS_DENIED_UNKNOWN},
+		{"no alliances for world", 2003, &alliance, api.ACCESS_DENIED_UNKNOWN},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := v.AccountAllianceStatus(test.worldPerspective, test.wvwGuildID); got != test.want {
+				t.Errorf("AccountAllianceStatus() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
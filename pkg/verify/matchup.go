@@ -0,0 +1,52 @@
+package verify
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// PersistMatchups stores the sides of the given matchups, overwriting sides already stored
+func PersistMatchups(idb bun.IDB, matchups []api.Matchup) error {
+	if len(matchups) == 0 {
+		return nil
+	}
+	ctx := context.Background()
+	_, err := idb.NewInsert().
+		Model(&matchups).
+		On(`CONFLICT ("match_id", "color", "start_time") DO UPDATE`).
+		Set("end_time = EXCLUDED.end_time, team_id = EXCLUDED.team_id, worlds = EXCLUDED.worlds").
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// GetCurrentMatchups returns the sides of all persisted matchups that have yet to end
+func GetCurrentMatchups(idb bun.IDB) (matchups []api.Matchup, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model(&matchups).
+		Where("end_time > NOW()").
+		Scan(ctx)
+	return matchups, errors.WithStack(err)
+}
+
+// GetMatchupHistory returns the sides of persisted matchups, newest first
+// If world is provided, only the sides the world or team was part of is returned
+func GetMatchupHistory(idb bun.IDB, world *int, since *time.Time) (matchups []api.Matchup, err error) {
+	ctx := context.Background()
+	query := idb.NewSelect().
+		Model(&matchups).
+		Order("start_time DESC", "match_id", "color")
+	if world != nil {
+		query.Where("worlds @> ?::jsonb OR team_id = ?", fmt.Sprintf("[%d]", *world), *world)
+	}
+	if since != nil {
+		query.Where("end_time > ?", *since)
+	}
+	err = query.Scan(ctx)
+	return matchups, errors.WithStack(err)
+}
@@ -0,0 +1,192 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+	"slices"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"go.uber.org/zap"
+)
+
+// Errors raised.
+var (
+	ErrMergeSameUser = errors.New("cannot merge a user into itself")
+)
+
+// MergeService merges users that belong to the same person
+type MergeService struct {
+	em *EventEmitter
+}
+
+func NewMergeService(em *EventEmitter) *MergeService {
+	return &MergeService{
+		em: em,
+	}
+}
+
+// Merge folds the source user of the merge into the target user and emits both users
+func (ms *MergeService) Merge(idb bun.IDB, merge *api.UserMerge) error {
+	ctx := context.Background()
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	if err = MergeUsers(tx, merge); err != nil {
+		return err
+	}
+
+	var target api.User
+	err = orm.QueryGetUser(tx, &target, merge.TargetUserID).
+		Scan(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if err = ms.em.Emit(tx, MergedUser(merge, &target)); err != nil {
+		return err
+	}
+	if err = ms.em.Emit(tx, &target); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed = true
+	return nil
+}
+
+// MergeUsers moves the platform links, accounts, bans and ephemeral associations of the source user to the target user,
+// records the merge and removes the source user.
+// If both users have a primary link on the same platform, the link of the target user stays primary
+func MergeUsers(idb bun.IDB, merge *api.UserMerge) error {
+	ctx := context.Background()
+	sourceID, targetID := merge.SourceUserID, merge.TargetUserID
+	if sourceID == targetID {
+		return ErrMergeSameUser
+	}
+
+	// Snapshot the source user, so the merge can be traced back
+	err := orm.QueryGetUser(idb, &merge.SourceUser, sourceID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return ErrUserNotFound
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+	exists, err := idb.NewSelect().
+		Model((*api.User)(nil)).
+		Where("id = ?", targetID).
+		Exists(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if !exists {
+		return ErrUserNotFound
+	}
+
+	// A user can only have a single primary link per platform
+	_, err = idb.NewUpdate().
+		Model((*orm.PlatformLink)(nil)).
+		Set(`"primary" = FALSE`).
+		Set("db_updated = NOW()").
+		Where(`user_id = ? AND "primary" = TRUE`, sourceID).
+		Where(`platform_id IN (?)`, idb.NewSelect().
+			Model((*orm.PlatformLink)(nil)).
+			Column("platform_id").
+			Where(`user_id = ? AND "primary" = TRUE`, targetID)).
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	for _, model := range []any{
+		(*orm.PlatformLink)(nil),
+		(*api.Account)(nil),
+		(*orm.Ban)(nil),
+		(*api.EphemeralAssociation)(nil),
+	} {
+		_, err = idb.NewUpdate().
+			Model(model).
+			Set("user_id = ?", targetID).
+			Where("user_id = ?", sourceID).
+			Exec(ctx)
+		if err != nil {
+			return errors.WithStack(err)
+		}
+	}
+
+	// Keep the accounts the source user has been linked to, so bans can still reach every identity of the accounts
+	_, err = idb.NewRaw(`INSERT INTO account_users (account_id, user_id)
+		SELECT account_id, ? FROM account_users WHERE user_id = ?
+		ON CONFLICT (account_id, user_id) DO UPDATE SET db_updated = NOW()`, targetID, sourceID).
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = idb.NewInsert().
+		Model(merge).
+		Returning("id, db_created").
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	_, err = idb.NewDelete().
+		Model((*api.User)(nil)).
+		Where("id = ?", sourceID).
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	zap.L().Info("merged users",
+		zap.Int64("source user id", sourceID),
+		zap.Int64("target user id", targetID),
+		zap.Bool("automatic", merge.Automatic))
+	return nil
+}
+
+// MergedUser returns the event emitted for the source user of the merge, which no longer exists
+// The event carries the platform links of both the source and the target user, so consumers of either platform receive it
+func MergedUser(merge *api.UserMerge, target *api.User) *api.User {
+	platformLinks := slices.Clone(merge.SourceUser.PlatformLinks)
+	for _, link := range target.PlatformLinks {
+		if !slices.ContainsFunc(platformLinks, func(l api.PlatformLink) bool {
+			return l.PlatformID == link.PlatformID && l.PlatformUserID == link.PlatformUserID
+		}) {
+			platformLinks = append(platformLinks, link)
+		}
+	}
+	return &api.User{
+		Id:            merge.SourceUserID,
+		MergedInto:    &merge.TargetUserID,
+		PlatformLinks: platformLinks,
+	}
+}
+
+// GetUserMerges returns the merges the user has been either the source or the target of, newest first
+func GetUserMerges(idb bun.IDB, userID int64) (merges []api.UserMerge, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model(&merges).
+		Where("source_user_id = ? OR target_user_id = ?", userID, userID).
+		Order("id DESC").
+		Scan(ctx)
+	if merges == nil {
+		merges = []api.UserMerge{}
+	}
+	return merges, errors.WithStack(err)
+}
@@ -0,0 +1,214 @@
+package verify
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+// MaxLevel is the highest level a character can reach
+const MaxLevel = 80
+
+type RuleType string
+
+const (
+	// RuleMinWvWRank requires the account to have at least the WvW rank given as value
+	RuleMinWvWRank RuleType = "min_wvw_rank"
+	// RuleMinPlaytime requires the account to have played for at least the hours given as value
+	RuleMinPlaytime RuleType = "min_playtime"
+	// RuleMinAccountAge requires the account to have been created at least the days given as value ago
+	RuleMinAccountAge RuleType = "min_account_age"
+	// RuleAccess requires the account to have access to at least one of the products given as access
+	RuleAccess RuleType = "access"
+	// RuleCommander requires the account to have a commander tag
+	RuleCommander RuleType = "commander"
+	// RuleMinCharacterLevel requires the account to have a character of at least the level given as value
+	RuleMinCharacterLevel RuleType = "min_character_level"
+	// RuleGuild requires the account to be a member of at least one of the guilds given as guilds
+	RuleGuild RuleType = "guild"
+)
+
+// AccessRule is a single requirement an account has to meet to be granted access
+type AccessRule struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Type        RuleType `json:"type"`
+	Value       int      `json:"value,omitempty"`
+	Access      []string `json:"access,omitempty"`
+	Guilds      []string `json:"guilds,omitempty"`
+	// Worlds limits the rule to the given world perspectives. The rule applies to all world perspectives if empty
+	Worlds []int `json:"worlds,omitempty"`
+	// FreeToPlayOnly limits the rule to free to play accounts
+	FreeToPlayOnly bool `json:"free_to_play_only,omitempty"`
+}
+
+// AccessPolicy is the set of rules every account has to meet to be granted access
+type AccessPolicy struct {
+	Rules []AccessRule `json:"rules"`
+}
+
+// DefaultAccessPolicy is used if no access policy file is configured
+var DefaultAccessPolicy = AccessPolicy{
+	Rules: []AccessRule{
+		{
+			Name:           "free-to-play-level-80",
+			Description:    "You need to be level 80 to verify yourself. This is required for all FreeToPlay accounts",
+			Type:           RuleMinCharacterLevel,
+			Value:          MaxLevel,
+			FreeToPlayOnly: true,
+		},
+	},
+}
+
+// LoadAccessPolicy reads an access policy from a json file
+func LoadAccessPolicy(path string) (*AccessPolicy, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	var policy AccessPolicy
+	if err = json.Unmarshal(data, &policy); err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	for i := range policy.Rules {
+		if err = policy.Rules[i].validate(); err != nil {
+			return nil, err
+		}
+	}
+	return &policy, nil
+}
+
+func (r *AccessRule) validate() error {
+	if r.Name == "" {
+		return errors.Errorf("access rule of type %s is missing a name", r.Type)
+	}
+	switch r.Type {
+	case RuleMinWvWRank, RuleMinPlaytime, RuleMinAccountAge, RuleCommander, RuleMinCharacterLevel:
+		return nil
+	case RuleAccess:
+		if len(r.Access) == 0 {
+			return errors.Errorf("access rule %s requires at least one access", r.Name)
+		}
+		return nil
+	case RuleGuild:
+		if len(r.Guilds) == 0 {
+			return errors.Errorf("access rule %s requires at least one guild", r.Name)
+		}
+		return nil
+	default:
+		return errors.Errorf("access rule %s has unknown type %s", r.Name, r.Type)
+	}
+}
+
+// UnmetRule returns the first rule the account does not meet from the given world perspective, if any
+// If the world perspective is nil, only rules that apply to all world perspectives are checked
+func (p *AccessPolicy) UnmetRule(worldPerspective *int, acc *api.Account) *AccessRule {
+	return p.unmetRule(worldPerspective, acc, false)
+}
+
+// UnmetRuleStrict is like UnmetRule, except rules that depend on data which is unknown for the account are not met
+func (p *AccessPolicy) UnmetRuleStrict(worldPerspective *int, acc *api.Account) *AccessRule {
+	return p.unmetRule(worldPerspective, acc, true)
+}
+
+func (p *AccessPolicy) unmetRule(worldPerspective *int, acc *api.Account, strict bool) *AccessRule {
+	if p == nil {
+		return nil
+	}
+	for i := range p.Rules {
+		rule := &p.Rules[i]
+		if rule.AppliesTo(worldPerspective, acc) && !rule.Met(acc, strict) {
+			return rule
+		}
+	}
+	return nil
+}
+
+// AppliesTo checks if the rule should be checked for the account from the given world perspective
+func (r *AccessRule) AppliesTo(worldPerspective *int, acc *api.Account) bool {
+	if len(r.Worlds) > 0 && (worldPerspective == nil || !slices.Contains(r.Worlds, *worldPerspective)) {
+		return false
+	}
+	if r.FreeToPlayOnly && (acc.Access == nil || !IsFreeToPlay(*acc.Access)) {
+		return false
+	}
+	return true
+}
+
+// Met checks if the account meets the rule. If strict, data that is unknown for the account does not meet the rule
+func (r *AccessRule) Met(acc *api.Account, strict bool) bool {
+	switch r.Type {
+	case RuleMinWvWRank:
+		return acc.WvWRank >= r.Value
+	case RuleMinPlaytime:
+		return time.Duration(acc.Age)*time.Second >= time.Duration(r.Value)*time.Hour
+	case RuleMinAccountAge:
+		return time.Since(acc.Created) >= time.Duration(r.Value)*24*time.Hour
+	case RuleAccess:
+		if acc.Access == nil {
+			return false
+		}
+		for _, access := range r.Access {
+			if slices.Contains(*acc.Access, access) {
+				return true
+			}
+		}
+		return false
+	case RuleCommander:
+		return acc.Commander
+	case RuleMinCharacterLevel:
+		// Character levels are unknown until the account has been synchronized with the characters permission
+		if !acc.CharacterLevelSynced {
+			return !strict
+		}
+		return acc.MaxCharacterLevel >= r.Value
+	case RuleGuild:
+		return IsGuildMember(acc, r.Guilds)
+	default:
+		return false
+	}
+}
+
+// Requirement returns the api representation of the rule
+func (r *AccessRule) Requirement() *api.Requirement {
+	return &api.Requirement{
+		Name:        r.Name,
+		Description: r.describe(),
+	}
+}
+
+func (r *AccessRule) describe() string {
+	if r.Description != "" {
+		return r.Description
+	}
+	switch r.Type {
+	case RuleMinWvWRank:
+		return fmt.Sprintf("Requires WvW rank %d", r.Value)
+	case RuleMinPlaytime:
+		return fmt.Sprintf("Requires at least %d hours of playtime", r.Value)
+	case RuleMinAccountAge:
+		return fmt.Sprintf("Requires the account to be at least %d days old", r.Value)
+	case RuleAccess:
+		return fmt.Sprintf("Requires access to one of: %v", r.Access)
+	case RuleCommander:
+		return "Requires a commander tag"
+	case RuleMinCharacterLevel:
+		return fmt.Sprintf("Requires a level %d character", r.Value)
+	case RuleGuild:
+		return fmt.Sprintf("Requires membership of one of the guilds: %v", r.Guilds)
+	default:
+		return string(r.Type)
+	}
+}
+
+// IsFreeToPlay returns true if the account is a free to play account
+func IsFreeToPlay(access []string) bool {
+	return slices.Contains(access, api.PlayForFree) && !slices.Contains(access, api.GuildWars2)
+}
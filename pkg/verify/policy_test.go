@@ -0,0 +1,154 @@
+package verify
+
+import (
+	"testing"
+	"time"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+func TestAccessRuleMet(t *testing.T) {
+	freeToPlay := []string{api.PlayForFree}
+	guilds := []string{"guild-a"}
+	tests := []struct {
+		name   string
+		rule   AccessRule
+		acc    api.Account
+		strict bool
+		want   bool
+	}{
+		{"wvw rank met", AccessRule{Type: RuleMinWvWRank, Value: 100}, api.Account{WvWRank: 100}, false, true},
+		{"wvw rank unmet", AccessRule{Type: RuleMinWvWRank, Value: 100}, api.Account{WvWRank: 99}, false, false},
+		{"playtime met", AccessRule{Type: RuleMinPlaytime, Value: 2}, api.Account{Age: 7200}, false, true},
+		{"playtime unmet", AccessRule{Type: RuleMinPlaytime, Value: 2}, api.Account{Age: 7199}, false, false},
+		{"account age met", AccessRule{Type: RuleMinAccountAge, Value: 30}, api.Account{Created: time.Now().Add(-31 * 24 * time.Hour)}, false, true},
+		{"account age unmet", AccessRule{Type: RuleMinAccountAge, Value: 30}, api.Account{Created: time.Now().Add(-29 * 24 * time.Hour)}, false, false},
+		{"access met", AccessRule{Type: RuleAccess, Access: []string{api.GuildWars2}}, api.Account{Access: &[]string{api.GuildWars2}}, false, true},
+		{"access unmet", AccessRule{Type: RuleAccess, Access: []string{api.GuildWars2}}, api.Account{Access: &freeToPlay}, false, false},
+		{"access unknown", AccessRule{Type: RuleAccess, Access: []string{api.GuildWars2}}, api.Account{}, false, false},
+		{"commander met", AccessRule{Type: RuleCommander}, api.Account{Commander: true}, false, true},
+		{"commander unmet", AccessRule{Type: RuleCommander}, api.Account{}, false, false},
+		{"character level met", AccessRule{Type: RuleMinCharacterLevel, Value: MaxLevel}, api.Account{CharacterLevelSynced: true, MaxCharacterLevel: MaxLevel}, true, true},
+		{"character level unmet", AccessRule{Type: RuleMinCharacterLevel, Value: MaxLevel}, api.Account{CharacterLevelSynced: true, MaxCharacterLevel: 79}, false, false},
+		{"character level unknown", AccessRule{Type: RuleMinCharacterLevel, Value: MaxLevel}, api.Account{}, false, true},
+		{"character level unknown strict", AccessRule{Type: RuleMinCharacterLevel, Value: MaxLevel}, api.Account{}, true, false},
+		{"guild member", AccessRule{Type: RuleGuild, Guilds: []string{"GUILD-A"}}, api.Account{Guilds: &guilds}, false, true},
+		{"guild leader", AccessRule{Type: RuleGuild, Guilds: []string{"guild-a"}}, api.Account{GuildLeader: &guilds}, false, true},
+		{"guild unmet", AccessRule{Type: RuleGuild, Guilds: []string{"guild-b"}}, api.Account{Guilds: &guilds}, false, false},
+		{"unknown type", AccessRule{Type: "unknown"}, api.Account{}, false, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := test.rule.Met(&test.acc, test.strict); got != test.want {
+				t.Errorf("Met() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAccessPolicyUnmetRule(t *testing.T) {
+	world := 2001
+	otherWorld := 2002
+	policy := &AccessPolicy{
+		Rules: []AccessRule{
+			{Name: "free-to-play", Type: RuleMinCharacterLevel, Value: MaxLevel, FreeToPlayOnly: true},
+			{Name: "world-rank", Type: RuleMinWvWRank, Value: 50, Worlds: []int{world}},
+			{Name: "commander", Type: RuleCommander},
+		},
+	}
+	freeToPlay := []string{api.PlayForFree}
+	paid := []string{api.PlayForFree, api.GuildWars2}
+	tests := []struct {
+		name             string
+		policy           *AccessPolicy
+		worldPerspective *int
+		acc              api.Account
+		strict           bool
+		want             string
+	}{
+		{"no policy", nil, &world, api.Account{}, true, ""},
+		{"all met", policy, &world, api.Account{Access: &paid, WvWRank: 50, Commander: true}, false, ""},
+		{"first unmet rule", policy, &world, api.Account{Access: &freeToPlay, CharacterLevelSynced: true}, false, "free-to-play"},
+		{"free to play rule skipped for paid accounts", policy, &world, api.Account{Access: &paid, CharacterLevelSynced: true, WvWRank: 50}, false, "commander"},
+		{"unknown character level", policy, &world, api.Account{Access: &freeToPlay, WvWRank: 50, Commander: true}, false, ""},
+		{"unknown character level strict", policy, &world, api.Account{Access: &freeToPlay, WvWRank: 50, Commander: true}, true, "free-to-play"},
+		{"world rule applies to its world", policy, &world, api.Account{Access: &paid, Commander: true}, false, "world-rank"},
+		{"world rule skipped for other worlds", policy, &otherWorld, api.Account{Access: &paid, Commander: true}, false, ""},
+		{"world rule skipped without world perspective", policy, nil, api.Account{Access: &paid, Commander: true}, false, ""},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			var got string
+			var rule *AccessRule
+			if test.strict {
+				rule = test.policy.UnmetRuleStrict(test.worldPerspective, &test.acc)
+			} else {
+				rule = test.policy.UnmetRule(test.worldPerspective, &test.acc)
+			}
+			if rule != nil {
+				got = rule.Name
+			}
+			if got != test.want {
+				t.Errorf("unmet rule = %q, want %q", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAccessRuleValidate(t *testing.T) {
+	tests := []struct {
+		name    string
+		rule    AccessRule
+		wantErr bool
+	}{
+		{"valid", AccessRule{Name: "rank", Type: RuleMinWvWRank, Value: 10}, false},
+		{"missing name", AccessRule{Type: RuleMinWvWRank}, true},
+		{"access without access", AccessRule{Name: "access", Type: RuleAccess}, true},
+		{"guild without guilds", AccessRule{Name: "guild", Type: RuleGuild}, true},
+		{"unknown type", AccessRule{Name: "unknown", Type: "unknown"}, true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if err := test.rule.validate(); (err != nil) != test.wantErr {
+				t.Errorf("validate() error = %v, wantErr %v", err, test.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatusRanksUnmetRequirement(t *testing.T) {
+	world := 2001
+	v := &Verification{
+		worlds: &Worlds{
+			isWorldLinksSynced: true,
+			linkedWorlds:       LinkedWorlds{"2001": {2002}},
+			worldTeams:         WorldTeams{},
+		},
+		policy: &AccessPolicy{
+			Rules: []AccessRule{{Name: "rank", Type: RuleMinWvWRank, Value: 50}},
+		},
+	}
+	tests := []struct {
+		name     string
+		accounts []api.Account
+		want     api.Status
+		wantRule bool
+	}{
+		{"requirement met", []api.Account{{ID: "a", World: world, WvWRank: 50}}, api.ACCESS_GRANTED_HOME_WORLD, false},
+		{"requirement not met", []api.Account{{ID: "a", World: world}}, api.ACCESS_DENIED_REQUIREMENT_NOT_MET, true},
+		{"requirement not met ranks above invalid world", []api.Account{{ID: "a", World: 1001, WvWRank: 50}, {ID: "b", World: 2002}}, api.ACCESS_DENIED_REQUIREMENT_NOT_MET, true},
+		{"other account grants access", []api.Account{{ID: "a", World: world}, {ID: "b", World: 2002, WvWRank: 50}}, api.ACCESS_GRANTED_LINKED_WORLD, false},
+		{"invalid world", []api.Account{{ID: "a", World: 1001, WvWRank: 50}}, api.ACCESS_DENIED_INVALID_WORLD, false},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			status, rule := v.status("", world, &api.User{Accounts: test.accounts})
+			if status != test.want {
+				t.Errorf("status = %s, want %s", status, test.want)
+			}
+			if (rule != nil) != test.wantRule {
+				t.Errorf("unmet rule = %v, want rule %v", rule, test.wantRule)
+			}
+		})
+	}
+}
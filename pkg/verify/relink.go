@@ -0,0 +1,164 @@
+package verify
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"go.uber.org/zap"
+)
+
+// relinkBatchSize is the amount of users loaded at a time when re-evaluating users
+const relinkBatchSize = 500
+
+// LinkReevaluator re-evaluates users when the world links change and emits the users whose status changed
+type LinkReevaluator struct {
+	em *EventEmitter
+}
+
+func NewLinkReevaluator(worlds *Worlds, em *EventEmitter) *LinkReevaluator {
+	r := &LinkReevaluator{
+		em: em,
+	}
+	worlds.OnMatchupChange(r.Reevaluate)
+	return r
+}
+
+// Reevaluate finds every user whose status changed between the previous and the current world links.
+// The instance that synchronized the matchups emits the users as a single batch for the world perspectives of its verification
+// listeners and the webhooks. Every other instance sends the new statuses to its own verification listeners once it has installed
+// the world links, as the listeners may use other world perspectives, and the batch may have been sent before the links were installed
+func (r *LinkReevaluator) Reevaluate(previous *Worlds, synchronized bool) {
+	perspectives := r.em.StatusListenerWorlds()
+	if synchronized {
+		var err error
+		perspectives, err = r.subscribedWorlds()
+		if err != nil {
+			zap.L().Error("unable to find subscribed world perspectives", zap.Error(err))
+			return
+		}
+	}
+
+	current := r.em.verification
+	// The previous verification is identical to the current, except it sees the previous world links
+	previousVerification := *current
+	previousVerification.worlds = previous
+
+	affected := []int{}
+	for _, perspective := range perspectives {
+		for _, world := range ChangedWorlds(perspective, previous, current.worlds) {
+			if !slices.Contains(affected, world) {
+				affected = append(affected, world)
+			}
+		}
+	}
+	if len(affected) == 0 {
+		zap.L().Info("world link change did not affect any subscribed world perspectives", zap.Ints("world perspectives", perspectives))
+		return
+	}
+
+	userIDs, err := GetWorldUserIDs(orm.DB(), affected)
+	if err != nil {
+		zap.L().Error("unable to find users on changed worlds", zap.Error(err))
+		return
+	}
+
+	changed := []*api.User{}
+	for batch := range slices.Chunk(userIDs, relinkBatchSize) {
+		users := []api.User{}
+		err = orm.QueryGetUsers(orm.DB(), &users).
+			Where("\"user\".id IN (?)", bun.In(batch)).
+			Scan(context.Background())
+		if err != nil {
+			zap.L().Error("unable to load users on changed worlds", zap.Error(errors.WithStack(err)))
+			return
+		}
+		for i := range users {
+			user := &users[i]
+			for _, perspective := range perspectives {
+				if previousVerification.Status(perspective, user) != current.Status(perspective, user) {
+					changed = append(changed, user)
+					break
+				}
+			}
+		}
+	}
+
+	zap.L().Info("re-evaluated users after world link change",
+		zap.Bool("synchronized", synchronized),
+		zap.Ints("world perspectives", perspectives),
+		zap.Ints("changed worlds", affected),
+		zap.Int("evaluated users", len(userIDs)),
+		zap.Int("changed users", len(changed)))
+	if len(changed) == 0 {
+		return
+	}
+	if synchronized {
+		r.em.EmitBatch(changed)
+		return
+	}
+	for _, user := range changed {
+		r.em.publishStatuses(user)
+	}
+}
+
+// subscribedWorlds returns the world perspectives of the active verification listeners and webhooks
+func (r *LinkReevaluator) subscribedWorlds() ([]int, error) {
+	worlds := r.em.StatusListenerWorlds()
+	webhookWorlds, err := GetWebhookWorlds(orm.DB())
+	if err != nil {
+		return nil, err
+	}
+	for _, world := range webhookWorlds {
+		if !slices.Contains(worlds, world) {
+			worlds = append(worlds, world)
+		}
+	}
+	return worlds, nil
+}
+
+// ChangedWorlds returns the worlds and teams that are linked to the world perspective in only one of the two world links
+func ChangedWorlds(worldPerspective int, previous *Worlds, current *Worlds) []int {
+	previousLinks, _ := previous.GetWorldLinks(worldPerspective)
+	currentLinks, _ := current.GetWorldLinks(worldPerspective)
+
+	changed := []int{}
+	for _, world := range previousLinks {
+		if !slices.Contains(currentLinks, world) {
+			changed = append(changed, world)
+		}
+	}
+	for _, world := range currentLinks {
+		if !slices.Contains(previousLinks, world) {
+			changed = append(changed, world)
+		}
+	}
+
+	previousTeam, _ := previous.GetWorldTeam(worldPerspective)
+	currentTeam, _ := current.GetWorldTeam(worldPerspective)
+	if previousTeam != currentTeam {
+		for _, team := range []int{previousTeam, currentTeam} {
+			if team != 0 {
+				changed = append(changed, team)
+			}
+		}
+	}
+	return changed
+}
+
+// GetWorldUserIDs returns the ids of users with an unexpired account or temporary access on any of the worlds or teams
+func GetWorldUserIDs(idb bun.IDB, worlds []int) (userIDs []int64, err error) {
+	ctx := context.Background()
+	err = idb.NewRaw(`SELECT user_id FROM accounts WHERE db_updated > ? AND (world IN (?) OR wvw_team_id IN (?))
+		UNION
+		SELECT user_id FROM ephemeral_associations WHERE until > NOW() AND world IN (?)`,
+		time.Now().Add(-accountExpiration()),
+		bun.In(worlds), bun.In(worlds),
+		bun.In(worlds)).
+		Scan(ctx, &userIDs)
+	return userIDs, errors.WithStack(err)
+}
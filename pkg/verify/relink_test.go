@@ -0,0 +1,37 @@
+package verify
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestChangedWorlds(t *testing.T) {
+	worlds := func(links []int, team int) *Worlds {
+		return &Worlds{
+			isWorldLinksSynced: true,
+			linkedWorlds:       LinkedWorlds{"2001": links},
+			worldTeams:         WorldTeams{2001: team},
+		}
+	}
+	tests := []struct {
+		name     string
+		previous *Worlds
+		current  *Worlds
+		want     []int
+	}{
+		{"unchanged", worlds([]int{2002, 2003}, 12001), worlds([]int{2003, 2002}, 12001), []int{}},
+		{"link removed", worlds([]int{2002, 2003}, 12001), worlds([]int{2002}, 12001), []int{2003}},
+		{"link added", worlds([]int{2002}, 12001), worlds([]int{2002, 2003}, 12001), []int{2003}},
+		{"links replaced", worlds([]int{2002}, 12001), worlds([]int{2003}, 12001), []int{2002, 2003}},
+		{"team changed", worlds([]int{2002}, 12001), worlds([]int{2002}, 12002), []int{12001, 12002}},
+		{"team became known", worlds([]int{2002}, 0), worlds([]int{2002}, 12002), []int{12002}},
+		{"not synchronized before", &Worlds{}, worlds([]int{2002}, 12001), []int{2002, 12001}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ChangedWorlds(2001, test.previous, test.current); !slices.Equal(got, test.want) {
+				t.Errorf("ChangedWorlds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
@@ -1,33 +1,135 @@
 package verify
 
 import (
+	"strconv"
+	"strings"
 	"time"
 
 	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
 	"go.uber.org/zap"
 )
 
 type Verification struct {
-	worlds *Worlds
+	worlds        *Worlds
+	alliances     map[int][]string
+	guilds        map[int][]string
+	serviceGuilds map[string][]string
+	policy        *AccessPolicy
 }
 
 func NewVerification(worlds *Worlds) *Verification {
 	return &Verification{
-		worlds: worlds,
+		worlds:        worlds,
+		alliances:     ParseWorldGuilds(config.Config().WvWAlliances),
+		guilds:        ParseWorldGuilds(config.Config().GuildAccess),
+		serviceGuilds: ParseServiceGuilds(config.Config().ServiceGuildAccess),
+		policy:        loadConfiguredAccessPolicy(),
 	}
 }
 
+// loadConfiguredAccessPolicy loads the access policy from the configured policy file
+// Falls back to the default policy if no file is configured, and no policy if restrictions are skipped
+func loadConfiguredAccessPolicy() *AccessPolicy {
+	if config.Config().SkipRestrictions {
+		return nil
+	}
+	path := config.Config().AccessPolicyFile
+	if path == "" {
+		return &DefaultAccessPolicy
+	}
+	policy, err := LoadAccessPolicy(path)
+	if err != nil {
+		zap.L().Panic("could not load access policy", zap.String("path", path), zap.Error(err))
+	}
+	zap.L().Info("loaded access policy", zap.String("path", path), zap.Int("rules", len(policy.Rules)))
+	return policy
+}
+
+// ParseWorldGuilds parses a list of "<world perspective>:<guild id>" pairs into a map of guild ids per world perspective
+func ParseWorldGuilds(pairs []string) map[int][]string {
+	worldGuilds := make(map[int][]string)
+	for _, pair := range pairs {
+		worldStr, guildID, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found {
+			zap.L().Warn("ignoring world guild pair without a world perspective", zap.String("pair", pair))
+			continue
+		}
+		world, err := strconv.Atoi(strings.TrimSpace(worldStr))
+		if err != nil {
+			zap.L().Warn("ignoring world guild pair with an invalid world perspective", zap.String("pair", pair), zap.Error(err))
+			continue
+		}
+		worldGuilds[world] = append(worldGuilds[world], strings.TrimSpace(guildID))
+	}
+	return worldGuilds
+}
+
+// ParseServiceGuilds parses a list of "<service uuid>:<guild id>" pairs into a map of guild ids per service
+func ParseServiceGuilds(pairs []string) map[string][]string {
+	serviceGuilds := make(map[string][]string)
+	for _, pair := range pairs {
+		serviceUUID, guildID, found := strings.Cut(strings.TrimSpace(pair), ":")
+		serviceUUID = strings.TrimSpace(serviceUUID)
+		if !found || serviceUUID == "" {
+			zap.L().Warn("ignoring service guild pair without a service", zap.String("pair", pair))
+			continue
+		}
+		serviceGuilds[serviceUUID] = append(serviceGuilds[serviceUUID], strings.TrimSpace(guildID))
+	}
+	return serviceGuilds
+}
+
+// Status returns the verification status of the user, without the guilds granting access to a specific service
 func (v *Verification) Status(worldPerspective int, user *api.User) api.Status {
+	status, _ := v.status("", worldPerspective, user)
+	return status
+}
+
+// VerificationStatus returns the verification status of the user as seen by the service, along with the ban or the requirement denying access, if any
+func (v *Verification) VerificationStatus(serviceUUID string, worldPerspective int, user *api.User) api.VerificationStatus {
+	status, unmetRule := v.status(serviceUUID, worldPerspective, user)
+	verificationStatus := api.VerificationStatus{
+		Status: status,
+		Ban:    GetActiveBan(user.Bans),
+	}
+	if unmetRule != nil {
+		verificationStatus.UnmetRequirement = unmetRule.Requirement()
+	}
+	return verificationStatus
+}
+
+// UnmetRequirement returns the first access policy rule the account does not meet, if any
+func (v *Verification) UnmetRequirement(worldPerspective *int, acc *api.Account) *AccessRule {
+	return v.policy.UnmetRule(worldPerspective, acc)
+}
+
+// UnmetSubmissionRequirement returns the first access policy rule the account does not meet when its api key is submitted, if any
+func (v *Verification) UnmetSubmissionRequirement(worldPerspective *int, acc *api.Account) *AccessRule {
+	return v.policy.UnmetRuleStrict(worldPerspective, acc)
+}
+
+func (v *Verification) status(serviceUUID string, worldPerspective int, user *api.User) (api.Status, *AccessRule) {
 	// Default, assume unknown denied
 	status := api.ACCESS_DENIED_UNKNOWN
 
 	activeBan := GetActiveBan(user.Bans)
 	if activeBan != nil {
-		return api.ACCESS_DENIED_BANNED
+		return api.ACCESS_DENIED_BANNED, nil
 	}
 
+	var unmetRule *AccessRule
 	for _, acc := range user.Accounts {
-		accStatus := v.AccountStatus(worldPerspective, &acc)
+		accStatus := v.AccountStatus(serviceUUID, worldPerspective, &acc)
+		// Accounts that would grant access still has to meet the access policy
+		if accStatus.AccessGranted() {
+			if rule := v.policy.UnmetRule(&worldPerspective, &acc); rule != nil {
+				accStatus = api.ACCESS_DENIED_REQUIREMENT_NOT_MET
+				if unmetRule == nil {
+					unmetRule = rule
+				}
+			}
+		}
 		if accStatus.Priority() > status.Priority() {
 			status = accStatus
 		}
@@ -39,18 +141,26 @@ func (v *Verification) Status(worldPerspective int, user *api.User) api.Status {
 			if assoc.Until == nil || assoc.World == nil || time.Now().After(*assoc.Until) {
 				continue
 			}
-			ephStatus := v.AccountWorldStatus(worldPerspective, *assoc.World, true)
+			var ephStatus api.Status
+			if IsTeamID(*assoc.World) {
+				ephStatus = v.AccountTeamStatus(worldPerspective, *assoc.World, true)
+			} else {
+				ephStatus = v.AccountWorldStatus(worldPerspective, *assoc.World, true)
+			}
 			if ephStatus.Priority() > status.Priority() {
 				status = ephStatus
 			}
 		}
 	}
 
-	return status
+	if status != api.ACCESS_DENIED_REQUIREMENT_NOT_MET {
+		unmetRule = nil
+	}
+	return status, unmetRule
 }
 
-// AccountStatus checks the verification api.ACCESS status for an account given a world perspective
-func (v *Verification) AccountStatus(worldPerspective int, acc *api.Account) api.Status {
+// AccountStatus checks the verification api.ACCESS status for an account given a world perspective, as seen by the service
+func (v *Verification) AccountStatus(serviceUUID string, worldPerspective int, acc *api.Account) api.Status {
 	if acc == nil || acc.ID == "" {
 		return api.ACCESS_DENIED_ACCOUNT_NOT_LINKED
 	}
@@ -60,7 +170,27 @@ func (v *Verification) AccountStatus(worldPerspective int, acc *api.Account) api
 		return api.ACCESS_DENIED_EXPIRED
 	}
 
-	return v.AccountWorldStatus(worldPerspective, acc.World, false)
+	status := v.AccountWorldStatus(worldPerspective, acc.World, false)
+
+	// Since the World Restructuring, the WvW team and alliance matter more than the home world
+	teamStatus := v.AccountTeamStatus(worldPerspective, acc.WvWTeamID, false)
+	if teamStatus.Priority() > status.Priority() {
+		status = teamStatus
+	}
+	allianceStatus := v.AccountAllianceStatus(worldPerspective, acc.WvWGuildID)
+	if allianceStatus.Priority() > status.Priority() {
+		status = allianceStatus
+	}
+	guildStatus := v.AccountGuildStatus(worldPerspective, acc)
+	if guildStatus.Priority() > status.Priority() {
+		status = guildStatus
+	}
+	serviceGuildStatus := v.AccountServiceGuildStatus(serviceUUID, acc)
+	if serviceGuildStatus.Priority() > status.Priority() {
+		status = serviceGuildStatus
+	}
+
+	return status
 }
 
 func (v *Verification) AccountWorldStatus(worldPerspective int, world int, ephemeral bool) api.Status {
@@ -88,3 +218,90 @@ func (v *Verification) AccountWorldStatus(worldPerspective int, world int, ephem
 
 	return api.ACCESS_DENIED_INVALID_WORLD
 }
+
+// AccountTeamStatus checks if the WvW team is the same team the world perspective is currently playing on
+func (v *Verification) AccountTeamStatus(worldPerspective int, team int, ephemeral bool) api.Status {
+	if team == 0 {
+		// Team is unknown, e.g. if the api key lacks the wvw permission
+		return api.ACCESS_DENIED_UNKNOWN
+	}
+
+	perspectiveTeam, err := v.worlds.GetWorldTeam(worldPerspective)
+	if err != nil {
+		zap.L().Error("could not get world team", zap.Error(err))
+		return api.ACCESS_DENIED_UNKNOWN
+	}
+	if perspectiveTeam == 0 {
+		return api.ACCESS_DENIED_UNKNOWN
+	}
+
+	if team == perspectiveTeam {
+		if ephemeral {
+			return api.ACCESS_GRANTED_WVW_TEAM_TEMPORARY
+		}
+		return api.ACCESS_GRANTED_WVW_TEAM
+	}
+
+	return api.ACCESS_DENIED_INVALID_WORLD
+}
+
+// AccountAllianceStatus checks if the WvW guild is one of the alliances configured for the world perspective
+func (v *Verification) AccountAllianceStatus(worldPerspective int, wvwGuildID *string) api.Status {
+	alliances, ok := v.alliances[worldPerspective]
+	if !ok || wvwGuildID == nil {
+		return api.ACCESS_DENIED_UNKNOWN
+	}
+
+	for _, alliance := range alliances {
+		if strings.EqualFold(alliance, *wvwGuildID) {
+			return api.ACCESS_GRANTED_WVW_ALLIANCE
+		}
+	}
+
+	return api.ACCESS_DENIED_INVALID_WORLD
+}
+
+// AccountGuildStatus checks if the account is a member of one of the guilds granting access for the world perspective
+func (v *Verification) AccountGuildStatus(worldPerspective int, acc *api.Account) api.Status {
+	guilds, ok := v.guilds[worldPerspective]
+	if !ok {
+		return api.ACCESS_DENIED_UNKNOWN
+	}
+
+	if IsGuildMember(acc, guilds) {
+		return api.ACCESS_GRANTED_GUILD
+	}
+
+	return api.ACCESS_DENIED_INVALID_WORLD
+}
+
+// AccountServiceGuildStatus checks if the account is a member of one of the guilds granting access to the service
+func (v *Verification) AccountServiceGuildStatus(serviceUUID string, acc *api.Account) api.Status {
+	guilds, ok := v.serviceGuilds[serviceUUID]
+	if !ok {
+		return api.ACCESS_DENIED_UNKNOWN
+	}
+
+	if IsGuildMember(acc, guilds) {
+		return api.ACCESS_GRANTED_GUILD
+	}
+
+	return api.ACCESS_DENIED_INVALID_WORLD
+}
+
+// IsGuildMember checks if the account is a member or leader of any of the given guilds
+func IsGuildMember(acc *api.Account, guildIDs []string) bool {
+	for _, memberships := range []*[]string{acc.Guilds, acc.GuildLeader} {
+		if memberships == nil {
+			continue
+		}
+		for _, membership := range *memberships {
+			for _, guildID := range guildIDs {
+				if strings.EqualFold(membership, guildID) {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
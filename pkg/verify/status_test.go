@@ -0,0 +1,80 @@
+package verify
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+)
+
+func TestParseServiceGuilds(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []string
+		want  map[string][]string
+	}{
+		{"pairs", []string{"service-a:guild-a", " service-a : guild-b ", "service-b:guild-c"}, map[string][]string{"service-a": {"guild-a", "guild-b"}, "service-b": {"guild-c"}}},
+		{"missing service", []string{"guild-a", ":guild-b"}, map[string][]string{}},
+		{"empty", nil, map[string][]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ParseServiceGuilds(test.pairs); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseServiceGuilds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestParseWorldGuilds(t *testing.T) {
+	tests := []struct {
+		name  string
+		pairs []string
+		want  map[int][]string
+	}{
+		{"pairs", []string{"2001:guild-a", " 2001 : guild-b", "12001:guild-c"}, map[int][]string{2001: {"guild-a", "guild-b"}, 12001: {"guild-c"}}},
+		{"missing world", []string{"guild-a"}, map[int][]string{}},
+		{"invalid world", []string{"world:guild-a"}, map[int][]string{}},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := ParseWorldGuilds(test.pairs); !reflect.DeepEqual(got, test.want) {
+				t.Errorf("ParseWorldGuilds() = %v, want %v", got, test.want)
+			}
+		})
+	}
+}
+
+func TestAccountServiceGuildStatus(t *testing.T) {
+	v := &Verification{
+		worlds: &Worlds{
+			isWorldLinksSynced: true,
+			linkedWorlds:       LinkedWorlds{},
+			worldTeams:         WorldTeams{},
+		},
+		guilds:        ParseWorldGuilds([]string{"2001:guild-world"}),
+		serviceGuilds: ParseServiceGuilds([]string{"service-a:guild-a"}),
+	}
+	member := []string{"GUILD-A"}
+	worldMember := []string{"guild-world"}
+	tests := []struct {
+		name        string
+		serviceUUID string
+		acc         api.Account
+		want        api.Status
+	}{
+		{"member", "service-a", api.Account{ID: "a", World: 1001, Guilds: &member}, api.ACCESS_GRANTED_GUILD},
+		{"leader", "service-a", api.Account{ID: "a", World: 1001, GuildLeader: &member}, api.ACCESS_GRANTED_GUILD},
+		{"other service", "service-b", api.Account{ID: "a", World: 1001, Guilds: &member}, api.ACCESS_DENIED_INVALID_WORLD},
+		{"without service", "", api.Account{ID: "a", World: 1001, Guilds: &member}, api.ACCESS_DENIED_INVALID_WORLD},
+		{"not a member", "service-a", api.Account{ID: "a", World: 1001}, api.ACCESS_DENIED_INVALID_WORLD},
+		{"world guild applies to every service", "service-b", api.Account{ID: "a", World: 1001, Guilds: &worldMember}, api.ACCESS_GRANTED_GUILD},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := v.AccountStatus(test.serviceUUID, 2001, &test.acc); got != test.want {
+				t.Errorf("AccountStatus() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,250 @@
+package verify
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"go.uber.org/zap"
+)
+
+// Errors raised.
+var (
+	ErrPlatformLinkNotFound = errors.New("platform link not found")
+	ErrAccountNotFound      = errors.New("account not found")
+	ErrAPIKeyNotFound       = errors.New("api key not found")
+)
+
+// UnlinkService removes platform links, accounts and api keys from users
+type UnlinkService struct {
+	em *EventEmitter
+}
+
+func NewUnlinkService(em *EventEmitter) *UnlinkService {
+	return &UnlinkService{
+		em: em,
+	}
+}
+
+// UnlinkPlatformUser removes the link between the platform user and its user and returns the removed link
+// If the link was the user's primary link on the platform, the oldest remaining link on the platform is promoted to primary.
+// The user is kept along with its accounts and bans, so unlinking cannot be used to evade a ban
+func (us *UnlinkService) UnlinkPlatformUser(idb bun.IDB, platformID int, platformUserID string) (*api.PlatformLink, error) {
+	ctx := context.Background()
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	var link orm.PlatformLink
+	_, err = tx.NewDelete().
+		Model(&link).
+		Where("platform_id = ? AND platform_user_id = ?", platformID, platformUserID).
+		Returning("*").
+		Exec(ctx, &link)
+	if err == sql.ErrNoRows || (err == nil && link.UserID == 0) {
+		return nil, ErrPlatformLinkNotFound
+	} else if err != nil {
+		return nil, errors.WithStack(err)
+	}
+
+	if link.Primary {
+		if err = promoteOldestPlatformLink(tx, platformID, link.UserID); err != nil {
+			return nil, err
+		}
+	}
+
+	if err = us.emitUser(tx, link.UserID); err != nil {
+		return nil, err
+	}
+	// The platform user is no longer part of the user, so it is emitted on its own without an id, accounts or bans.
+	// This lets consumers revoke the access of the platform user
+	err = us.em.Emit(tx, &api.User{
+		PlatformLinks: []api.PlatformLink{link.PlatformLink},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	committed = true
+
+	zap.L().Info("removed platform link", zap.Any("link", link.PlatformLink))
+	return &link.PlatformLink, nil
+}
+
+// promoteOldestPlatformLink makes the oldest link of the user on the platform primary
+func promoteOldestPlatformLink(idb bun.IDB, platformID int, userID int64) error {
+	ctx := context.Background()
+	_, err := idb.NewUpdate().
+		Model((*orm.PlatformLink)(nil)).
+		Set(`"primary" = TRUE`).
+		Set("db_updated = NOW()").
+		Where("(platform_id, platform_user_id) = (?)", idb.NewSelect().
+			Model((*orm.PlatformLink)(nil)).
+			Column("platform_id", "platform_user_id").
+			Where("platform_id = ? AND user_id = ?", platformID, userID).
+			Order("db_created").
+			Limit(1)).
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// DeleteAccount removes a gw2 account and its api keys from the user the platform user is linked to
+// Bans issued to the account are kept with the user, and are copied to whichever user the account is linked to again
+func (us *UnlinkService) DeleteAccount(idb bun.IDB, platformID int, platformUserID string, accountID string) error {
+	ctx := context.Background()
+	userID, err := linkedUserID(platformID, platformUserID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	deleted, err := deleteUserAccount(tx, userID, accountID)
+	if err != nil {
+		return err
+	}
+	if !deleted {
+		return ErrAccountNotFound
+	}
+	if err = us.emitUser(tx, userID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed = true
+
+	zap.L().Info("removed account", zap.Int64("user id", userID), zap.String("account id", accountID))
+	return nil
+}
+
+// DeleteAPIKey removes an api key from one of the accounts of the user the platform user is linked to
+// The account is removed as well if it was its last api key, as the account can no longer be verified
+func (us *UnlinkService) DeleteAPIKey(idb bun.IDB, platformID int, platformUserID string, apiKeyID string) error {
+	ctx := context.Background()
+	userID, err := linkedUserID(platformID, platformUserID)
+	if err != nil {
+		return err
+	}
+
+	tx, err := idb.BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	var accountIDs []string
+	_, err = tx.NewDelete().
+		Model((*orm.TokenInfo)(nil)).
+		Where("id = ?", apiKeyID).
+		Where("account_id IN (?)", tx.NewSelect().
+			Model((*api.Account)(nil)).
+			Column("id").
+			Where("user_id = ?", userID)).
+		Returning("account_id").
+		Exec(ctx, &accountIDs)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(accountIDs) == 0 {
+		return ErrAPIKeyNotFound
+	}
+
+	remaining, err := tx.NewSelect().
+		Model((*orm.TokenInfo)(nil)).
+		Where("account_id = ?", accountIDs[0]).
+		Count(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if remaining == 0 {
+		if _, err = deleteUserAccount(tx, userID, accountIDs[0]); err != nil {
+			return err
+		}
+	}
+	if err = us.emitUser(tx, userID); err != nil {
+		return err
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed = true
+
+	zap.L().Info("removed api key",
+		zap.Int64("user id", userID),
+		zap.String("account id", accountIDs[0]),
+		zap.String("key id", apiKeyID),
+		zap.Bool("account removed", remaining == 0))
+	return nil
+}
+
+// deleteUserAccount deletes the account if it belongs to the user. Api keys and account data are removed along with it
+func deleteUserAccount(idb bun.IDB, userID int64, accountID string) (deleted bool, err error) {
+	ctx := context.Background()
+	res, err := idb.NewDelete().
+		Model((*api.Account)(nil)).
+		Where("id = ? AND user_id = ?", accountID, userID).
+		Exec(ctx)
+	if err != nil {
+		return false, errors.WithStack(err)
+	}
+	affected, err := res.RowsAffected()
+	return affected > 0, errors.WithStack(err)
+}
+
+// linkedUserID returns the id of the user the platform user is linked to
+func linkedUserID(platformID int, platformUserID string) (int64, error) {
+	link, err := orm.GetPlatformLink(platformID, platformUserID)
+	if err != nil {
+		return 0, err
+	}
+	if link.UserID == 0 {
+		return 0, ErrUserNotFound
+	}
+	return link.UserID, nil
+}
+
+func (us *UnlinkService) emitUser(idb bun.IDB, userID int64) error {
+	ctx := context.Background()
+
+	var user api.User
+	err := orm.QueryGetUser(idb, &user, userID).
+		Scan(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	return us.em.Emit(idb, &user)
+}
@@ -0,0 +1,721 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	mrand "math/rand/v2"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/pkg/errors"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/driver/pgdriver"
+	"github.com/vennekilde/gw2verify/v2/internal/api"
+	"github.com/vennekilde/gw2verify/v2/internal/config"
+	"github.com/vennekilde/gw2verify/v2/internal/orm"
+	"go.uber.org/zap"
+)
+
+const (
+	// HeaderWebhookSignature contains the hex encoded HMAC-SHA256 of "<timestamp>.<body>", prefixed with "sha256="
+	HeaderWebhookSignature = "X-GW2Verify-Signature"
+	// HeaderWebhookTimestamp contains the unix timestamp the delivery was signed at
+	HeaderWebhookTimestamp = "X-GW2Verify-Timestamp"
+	// HeaderWebhookEventID contains the id of the delivered event, if any
+	HeaderWebhookEventID = "X-GW2Verify-Event-Id"
+	// HeaderWebhookPayload contains the payload type of the delivery
+	HeaderWebhookPayload = "X-GW2Verify-Payload"
+)
+
+// Errors raised.
+var (
+	ErrWebhookNotFound          = errors.New("webhook not found")
+	ErrDeadLetterNotFound       = errors.New("dead letter not found")
+	ErrWebhookAddressNotAllowed = errors.New("webhook url must not resolve to a loopback, link-local or private address")
+)
+
+// WebhookChannel is the postgres notification channel instances are notified on when a webhook is registered or removed
+const WebhookChannel = "webhooks_changed"
+
+// webhookCacheTTL is how long the cached webhooks are used, in case a change notification was missed while reconnecting
+const webhookCacheTTL = 5 * time.Minute
+
+// webhookRetryPollInterval is how often pending deliveries that are due for a retry are claimed
+const webhookRetryPollInterval = 5 * time.Second
+
+// webhookDeliveryLease is how long a claimed delivery is left alone by every instance, before it is considered abandoned and retried
+const webhookDeliveryLease = time.Minute
+
+// WebhookDispatcher delivers emitted events to the registered webhooks.
+// Events are queued and delivered by a fixed number of workers. Deliveries of persisted events are stored as pending until they succeed,
+// so their retries survive a restart and are picked up by any instance. Deliveries that fail after all attempts are kept as dead letters
+type WebhookDispatcher struct {
+	em         *EventEmitter
+	client     *http.Client
+	events     chan emittedEvent
+	deliveries chan *webhookDelivery
+
+	// webhooks caches every registered webhook, as they are needed for every dispatched event. Nil until loaded
+	webhooks       []orm.Webhook
+	webhooksLoaded time.Time
+	webhooksMu     sync.RWMutex
+}
+
+// emittedEvent is an emitted user waiting to be dispatched. The user is encoded when it is emitted, as it may change afterwards
+type emittedEvent struct {
+	user      []byte
+	transient bool
+}
+
+// webhookDelivery is the delivery of a single payload to a webhook
+type webhookDelivery struct {
+	webhook *orm.Webhook
+	// record is the stored delivery. Transient deliveries are never stored, and have an id of 0
+	record api.WebhookDelivery
+	body   []byte
+}
+
+func NewWebhookDispatcher(em *EventEmitter) *WebhookDispatcher {
+	conf := config.Config()
+	queueSize := max(conf.WebhookQueueSize, 1)
+	d := &WebhookDispatcher{
+		em: em,
+		client: &http.Client{
+			Timeout:   conf.WebhookTimeout,
+			Transport: newWebhookTransport(),
+		},
+		events:     make(chan emittedEvent, queueSize),
+		deliveries: make(chan *webhookDelivery, queueSize),
+	}
+	em.OnEmit(d.Dispatch)
+	return d
+}
+
+// newWebhookTransport returns a transport that refuses to connect to addresses webhooks may not be delivered to.
+// The address is checked when connecting, so a host cannot pass validation and resolve to a private address later
+func newWebhookTransport() *http.Transport {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if config.Config().WebhookAllowPrivateNetworks {
+		return transport
+	}
+	dialer := &net.Dialer{
+		Timeout:   30 * time.Second,
+		KeepAlive: 30 * time.Second,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return errors.WithStack(err)
+			}
+			ip := net.ParseIP(host)
+			if ip == nil || !isPublicAddress(ip) {
+				return ErrWebhookAddressNotAllowed
+			}
+			return nil
+		},
+	}
+	transport.DialContext = dialer.DialContext
+	return transport
+}
+
+// isPublicAddress reports whether webhooks may be delivered to the ip
+func isPublicAddress(ip net.IP) bool {
+	return !ip.IsLoopback() &&
+		!ip.IsPrivate() &&
+		!ip.IsLinkLocalUnicast() &&
+		!ip.IsLinkLocalMulticast() &&
+		!ip.IsInterfaceLocalMulticast() &&
+		!ip.IsMulticast() &&
+		!ip.IsUnspecified()
+}
+
+// ValidateWebhook ensures the webhook can be delivered to
+func ValidateWebhook(webhook *api.Webhook) error {
+	u, err := url.Parse(webhook.URL)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return errors.Errorf("webhook url must use http or https, not \"%s\"", u.Scheme)
+	}
+	if !config.Config().WebhookAllowPrivateNetworks {
+		if err = validateWebhookHost(u.Hostname()); err != nil {
+			return err
+		}
+	}
+	switch webhook.Payload {
+	case api.WebhookPayloadUser:
+	case api.WebhookPayloadVerificationStatus:
+		if webhook.World == nil {
+			return errors.New("a world perspective is required for verification_status webhooks")
+		}
+	default:
+		return errors.Errorf("unknown webhook payload \"%s\"", webhook.Payload)
+	}
+	return nil
+}
+
+// validateWebhookHost ensures the host does not resolve to an address webhooks may not be delivered to
+func validateWebhookHost(host string) error {
+	if host == "" {
+		return errors.New("webhook url must have a host")
+	}
+	ips := []net.IP{net.ParseIP(host)}
+	if ips[0] == nil {
+		var err error
+		ips, err = net.LookupIP(host)
+		if err != nil {
+			return errors.Errorf("unable to resolve webhook host \"%s\"", host)
+		}
+	}
+	for _, ip := range ips {
+		if !isPublicAddress(ip) {
+			return ErrWebhookAddressNotAllowed
+		}
+	}
+	return nil
+}
+
+// CreateWebhook registers a webhook for the service. A secret is generated if none is provided
+func CreateWebhook(idb bun.IDB, serviceUUID string, webhook *api.Webhook) error {
+	if err := ValidateWebhook(webhook); err != nil {
+		return err
+	}
+	if webhook.Secret == nil || *webhook.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return errors.WithStack(err)
+		}
+		secretStr := hex.EncodeToString(secret)
+		webhook.Secret = &secretStr
+	}
+	webhook.ID = 0
+	webhook.ServiceUuid = serviceUUID
+
+	ctx := context.Background()
+	record := orm.Webhook{Webhook: *webhook}
+	_, err := idb.NewInsert().
+		Model(&record).
+		Returning("id").
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	webhook.ID = record.ID
+	return notifyWebhooksChanged(idb)
+}
+
+// GetServiceWebhooks returns the webhooks registered by the service, without their secrets
+func GetServiceWebhooks(idb bun.IDB, serviceUUID string) (webhooks []api.Webhook, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model(&webhooks).
+		Where("service_uuid = ?", serviceUUID).
+		Order("id").
+		Scan(ctx)
+	if webhooks == nil {
+		webhooks = []api.Webhook{}
+	}
+	return webhooks, errors.WithStack(err)
+}
+
+// GetServiceWebhook returns a webhook registered by the service along with its secret. ErrWebhookNotFound is returned if it does not exist
+func GetServiceWebhook(idb bun.IDB, serviceUUID string, webhookID int64) (*orm.Webhook, error) {
+	ctx := context.Background()
+	var webhook orm.Webhook
+	err := idb.NewSelect().
+		Model(&webhook).
+		Where("service_uuid = ? AND id = ?", serviceUUID, webhookID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return nil, ErrWebhookNotFound
+	}
+	return &webhook, errors.WithStack(err)
+}
+
+// DeleteServiceWebhook unregisters a webhook registered by the service
+func DeleteServiceWebhook(idb bun.IDB, serviceUUID string, webhookID int64) error {
+	ctx := context.Background()
+	res, err := idb.NewDelete().
+		Model((*api.Webhook)(nil)).
+		Where("service_uuid = ? AND id = ?", serviceUUID, webhookID).
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if rows, err := res.RowsAffected(); err == nil && rows == 0 {
+		return ErrWebhookNotFound
+	}
+	return notifyWebhooksChanged(idb)
+}
+
+// notifyWebhooksChanged notifies every instance that its cached webhooks are outdated
+func notifyWebhooksChanged(idb bun.IDB) error {
+	ctx := context.Background()
+	_, err := idb.ExecContext(ctx, "SELECT pg_notify(?, '')", WebhookChannel)
+	return errors.WithStack(err)
+}
+
+// GetWebhookWorlds returns the world perspectives of all verification status webhooks
+func GetWebhookWorlds(idb bun.IDB) (worlds []int, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model((*api.Webhook)(nil)).
+		ColumnExpr("DISTINCT world").
+		Where("payload = ? AND world IS NOT NULL", api.WebhookPayloadVerificationStatus).
+		Scan(ctx, &worlds)
+	return worlds, errors.WithStack(err)
+}
+
+// GetDeadLetters returns the deliveries to the webhook that failed after all retries, oldest first
+func GetDeadLetters(idb bun.IDB, webhookID int64) (deliveries []api.WebhookDelivery, err error) {
+	ctx := context.Background()
+	err = idb.NewSelect().
+		Model(&deliveries).
+		Where("webhook_id = ? AND NOT pending", webhookID).
+		Order("id").
+		Scan(ctx)
+	if deliveries == nil {
+		deliveries = []api.WebhookDelivery{}
+	}
+	return deliveries, errors.WithStack(err)
+}
+
+// Dispatch queues the user for delivery to every webhook interested in it, so webhooks never hold back the emitter.
+// If the queue is full the event is dropped, consumers can still replay persisted events from the event log.
+// Deliveries of transient events are never stored
+func (d *WebhookDispatcher) Dispatch(user *api.User, transient bool) {
+	encoded, err := json.Marshal(user)
+	if err != nil {
+		zap.L().Error("unable to encode webhook event", zap.Int64("user id", user.Id), zap.Error(err))
+		return
+	}
+	select {
+	case d.events <- emittedEvent{user: encoded, transient: transient}:
+	default:
+		zap.L().Error("webhook queue is full, dropping event", zap.Int64("user id", user.Id), zap.Int64p("event id", user.EventID))
+	}
+}
+
+// Start starts the delivery workers, and retries the pending deliveries of every instance once they are due
+// Note: this method will block the calling goroutine indefinitely
+func (d *WebhookDispatcher) Start() {
+	go d.listenForChanges()
+	for range max(config.Config().WebhookWorkers, 1) {
+		go d.work()
+	}
+	for {
+		if err := d.claimDueDeliveries(); err != nil {
+			zap.L().Error("unable to claim pending webhook deliveries", zap.Error(err))
+		}
+		time.Sleep(webhookRetryPollInterval)
+	}
+}
+
+// listenForChanges invalidates the cached webhooks whenever a webhook is registered or removed by any instance
+func (d *WebhookDispatcher) listenForChanges() {
+	ctx := context.Background()
+	ln := pgdriver.NewListener(orm.DB())
+	defer ln.Close()
+	if err := ln.Listen(ctx, WebhookChannel); err != nil {
+		zap.L().Panic("unable to listen for webhook changes", zap.Error(err))
+	}
+
+	for range ln.Channel() {
+		d.InvalidateWebhooks()
+	}
+}
+
+// InvalidateWebhooks drops the cached webhooks, so they are loaded again on the next dispatch
+func (d *WebhookDispatcher) InvalidateWebhooks() {
+	d.webhooksMu.Lock()
+	defer d.webhooksMu.Unlock()
+	d.webhooks = nil
+}
+
+// cachedWebhooks returns every registered webhook, loading them if they are not cached or the cache has expired
+func (d *WebhookDispatcher) cachedWebhooks() ([]orm.Webhook, error) {
+	d.webhooksMu.RLock()
+	webhooks, loaded := d.webhooks, d.webhooksLoaded
+	d.webhooksMu.RUnlock()
+	if webhooks != nil && time.Since(loaded) < webhookCacheTTL {
+		return webhooks, nil
+	}
+
+	d.webhooksMu.Lock()
+	defer d.webhooksMu.Unlock()
+	webhooks = []orm.Webhook{}
+	err := orm.DB().NewSelect().
+		Model(&webhooks).
+		Scan(context.Background())
+	if err != nil {
+		return nil, errors.WithStack(err)
+	}
+	d.webhooks = webhooks
+	d.webhooksLoaded = time.Now()
+	return webhooks, nil
+}
+
+// work dispatches queued events and retries queued deliveries
+func (d *WebhookDispatcher) work() {
+	for {
+		select {
+		case event := <-d.events:
+			d.dispatch(event)
+		case delivery := <-d.deliveries:
+			d.attempt(delivery)
+		}
+	}
+}
+
+// dispatch delivers the event to every webhook interested in it
+func (d *WebhookDispatcher) dispatch(event emittedEvent) {
+	var user api.User
+	if err := json.Unmarshal(event.user, &user); err != nil {
+		zap.L().Error("unable to decode webhook event", zap.Error(err))
+		return
+	}
+
+	webhooks, err := d.cachedWebhooks()
+	if err != nil {
+		zap.L().Error("unable to fetch webhooks", zap.Error(err))
+		return
+	}
+
+	for i := range webhooks {
+		webhook := &webhooks[i]
+		if !isOnPlatform(&user, webhook.PlatformID) {
+			continue
+		}
+
+		var payload any = &user
+		if webhook.Payload == api.WebhookPayloadVerificationStatus {
+			world := 0
+			if webhook.World != nil {
+				world = *webhook.World
+			}
+			payload = d.em.UserStatus(webhook.ServiceUuid, &user, world, webhook.PlatformID)
+		}
+		body, err := json.Marshal(payload)
+		if err != nil {
+			zap.L().Error("unable to encode webhook payload", zap.Int64("webhook id", webhook.ID), zap.Error(err))
+			continue
+		}
+
+		delivery := &webhookDelivery{
+			webhook: webhook,
+			record: api.WebhookDelivery{
+				WebhookID: webhook.ID,
+				EventID:   user.EventID,
+			},
+			body: body,
+		}
+		if !event.transient {
+			// Stored before the first attempt, so the delivery is retried even if this instance stops
+			if err = storePendingDelivery(delivery); err != nil {
+				zap.L().Error("unable to store pending webhook delivery", zap.Int64("webhook id", webhook.ID), zap.Error(err))
+			}
+		}
+		d.attempt(delivery)
+	}
+}
+
+// storePendingDelivery stores the delivery as pending. It is leased to this instance, which makes the first attempt right away
+func storePendingDelivery(delivery *webhookDelivery) error {
+	if err := json.Unmarshal(delivery.body, &delivery.record.Body); err != nil {
+		return errors.WithStack(err)
+	}
+	_, err := orm.DB().NewInsert().
+		Model(&delivery.record).
+		Value("pending", "TRUE").
+		Value("next_attempt_at", "?", time.Now().Add(webhookDeliveryLease)).
+		Returning("id").
+		Exec(context.Background())
+	if err != nil {
+		delivery.record.ID = 0
+	}
+	return errors.WithStack(err)
+}
+
+// attempt makes a single delivery attempt. Failed deliveries are retried with exponential backoff until they run out of attempts,
+// after which stored deliveries are kept as dead letters
+func (d *WebhookDispatcher) attempt(delivery *webhookDelivery) {
+	ctx := context.Background()
+	statusCode, err := d.deliver(delivery.webhook, delivery.record.EventID, delivery.body)
+	delivery.record.Attempts++
+	if err == nil {
+		if delivery.record.ID != 0 {
+			_, err = orm.DB().NewDelete().
+				Model(&delivery.record).
+				WherePK().
+				Exec(ctx)
+			if err != nil {
+				zap.L().Error("unable to remove delivered webhook delivery", zap.Int64("delivery id", delivery.record.ID), zap.Error(err))
+			}
+		}
+		return
+	}
+
+	errStr := err.Error()
+	delivery.record.LastError = &errStr
+	if statusCode != 0 {
+		delivery.record.LastStatusCode = &statusCode
+	}
+
+	if delivery.record.Attempts >= max(config.Config().WebhookMaxAttempts, 1) {
+		if delivery.record.ID == 0 {
+			zap.L().Warn("unable to deliver transient webhook",
+				zap.Int64("webhook id", delivery.webhook.ID),
+				zap.Int("attempts", delivery.record.Attempts),
+				zap.Error(err))
+			return
+		}
+		zap.L().Warn("unable to deliver webhook, storing it as a dead letter",
+			zap.Int64("webhook id", delivery.webhook.ID),
+			zap.Int("attempts", delivery.record.Attempts),
+			zap.Error(err))
+		updateDelivery(delivery, nil)
+		return
+	}
+
+	// Add up to 50% jitter, so failing deliveries do not retry in lockstep
+	delay := config.Config().WebhookRetryBackoff << (delivery.record.Attempts - 1)
+	delay += time.Duration(mrand.Int64N(int64(delay)/2 + 1))
+	if delivery.record.ID == 0 {
+		// Transient deliveries cannot be stored, so they are retried from memory
+		time.AfterFunc(delay, func() {
+			select {
+			case d.deliveries <- delivery:
+			default:
+				zap.L().Error("webhook queue is full, dropping transient delivery", zap.Int64("webhook id", delivery.webhook.ID))
+			}
+		})
+		return
+	}
+	nextAttempt := time.Now().Add(delay)
+	updateDelivery(delivery, &nextAttempt)
+}
+
+// updateDelivery records a failed attempt of a stored delivery. The delivery becomes a dead letter if there is no next attempt
+func updateDelivery(delivery *webhookDelivery, nextAttempt *time.Time) {
+	query := orm.DB().NewUpdate().
+		Model(&delivery.record).
+		Set("attempts = ?", delivery.record.Attempts).
+		Set("last_error = ?", delivery.record.LastError).
+		Set("last_status_code = ?", delivery.record.LastStatusCode).
+		Set("pending = ?", nextAttempt != nil).
+		Set("next_attempt_at = ?", nextAttempt).
+		WherePK()
+	if nextAttempt == nil {
+		// Dead letters report when they failed
+		query.Set("db_created = ?", time.Now())
+	}
+	if _, err := query.Exec(context.Background()); err != nil {
+		zap.L().Error("unable to update webhook delivery", zap.Int64("delivery id", delivery.record.ID), zap.Error(err))
+	}
+}
+
+// claimDueDeliveries claims the pending deliveries that are due for a retry and queues them.
+// The deliveries are leased, so neither other instances nor the next claim pick them up meanwhile
+func (d *WebhookDispatcher) claimDueDeliveries() error {
+	limit := cap(d.deliveries) - len(d.deliveries)
+	if limit <= 0 {
+		return nil
+	}
+
+	ctx := context.Background()
+	tx, err := orm.DB().BeginTx(ctx, nil)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed := false
+	defer func() {
+		if !committed {
+			tx.Rollback()
+		}
+	}()
+
+	now := time.Now()
+	var records []api.WebhookDelivery
+	err = tx.NewSelect().
+		Model(&records).
+		Where("pending AND next_attempt_at <= ?", now).
+		Order("next_attempt_at").
+		Limit(limit).
+		For("UPDATE SKIP LOCKED").
+		Scan(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	if len(records) == 0 {
+		return nil
+	}
+
+	ids := make([]int64, len(records))
+	webhookIDs := make([]int64, len(records))
+	for i := range records {
+		ids[i] = records[i].ID
+		webhookIDs[i] = records[i].WebhookID
+	}
+	_, err = tx.NewUpdate().
+		Model((*api.WebhookDelivery)(nil)).
+		Set("next_attempt_at = ?", now.Add(webhookDeliveryLease)).
+		Where("id IN (?)", bun.In(ids)).
+		Exec(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	var webhooks []orm.Webhook
+	err = tx.NewSelect().
+		Model(&webhooks).
+		Where("id IN (?)", bun.In(webhookIDs)).
+		Scan(ctx)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+
+	err = tx.Commit()
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	committed = true
+
+	webhooksByID := make(map[int64]*orm.Webhook, len(webhooks))
+	for i := range webhooks {
+		webhooksByID[webhooks[i].ID] = &webhooks[i]
+	}
+	for i := range records {
+		webhook, ok := webhooksByID[records[i].WebhookID]
+		if !ok {
+			continue
+		}
+		body, err := json.Marshal(records[i].Body)
+		if err != nil {
+			zap.L().Error("unable to encode pending webhook delivery", zap.Int64("delivery id", records[i].ID), zap.Error(err))
+			continue
+		}
+		d.deliveries <- &webhookDelivery{
+			webhook: webhook,
+			record:  records[i],
+			body:    body,
+		}
+	}
+	return nil
+}
+
+// deliver makes a single signed delivery attempt. Any non 2xx response is treated as a failure
+func (d *WebhookDispatcher) deliver(webhook *orm.Webhook, eventID *int64, body []byte) (statusCode int, err error) {
+	req, err := http.NewRequest(http.MethodPost, webhook.URL, bytes.NewReader(body))
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+
+	timestamp := strconv.FormatInt(time.Now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderWebhookPayload, string(webhook.Payload))
+	req.Header.Set(HeaderWebhookTimestamp, timestamp)
+	if webhook.Secret != nil {
+		req.Header.Set(HeaderWebhookSignature, "sha256="+SignWebhookBody(*webhook.Secret, timestamp, body))
+	}
+	if eventID != nil {
+		req.Header.Set(HeaderWebhookEventID, strconv.FormatInt(*eventID, 10))
+	}
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return 0, errors.WithStack(err)
+	}
+	defer resp.Body.Close()
+	// Drain the body, so the connection can be reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("webhook responded with status code %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// Ping delivers a signed ping to the webhook, without retries
+func (d *WebhookDispatcher) Ping(serviceUUID string, webhookID int64) error {
+	webhook, err := GetServiceWebhook(orm.DB(), serviceUUID, webhookID)
+	if err != nil {
+		return err
+	}
+	body, err := json.Marshal(map[string]any{"ping": true, "webhook_id": webhook.ID})
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	_, err = d.deliver(webhook, nil, body)
+	return err
+}
+
+// RetryDeadLetter delivers a dead letter again, and removes it if the webhook accepts it
+func (d *WebhookDispatcher) RetryDeadLetter(serviceUUID string, webhookID int64, deliveryID int64) error {
+	ctx := context.Background()
+	webhook, err := GetServiceWebhook(orm.DB(), serviceUUID, webhookID)
+	if err != nil {
+		return err
+	}
+
+	var deadLetter api.WebhookDelivery
+	err = orm.DB().NewSelect().
+		Model(&deadLetter).
+		Where("id = ? AND webhook_id = ? AND NOT pending", deliveryID, webhook.ID).
+		Scan(ctx)
+	if err == sql.ErrNoRows {
+		return ErrDeadLetterNotFound
+	} else if err != nil {
+		return errors.WithStack(err)
+	}
+
+	body, err := json.Marshal(deadLetter.Body)
+	if err != nil {
+		return errors.WithStack(err)
+	}
+	statusCode, deliveryErr := d.deliver(webhook, deadLetter.EventID, body)
+	if deliveryErr != nil {
+		// Keep track of the failed attempt
+		errStr := deliveryErr.Error()
+		query := orm.DB().NewUpdate().
+			Model(&deadLetter).
+			Set("attempts = attempts + 1").
+			Set("last_error = ?", errStr).
+			WherePK()
+		if statusCode != 0 {
+			query.Set("last_status_code = ?", statusCode)
+		}
+		if _, err = query.Exec(ctx); err != nil {
+			zap.L().Error("unable to update dead letter", zap.Int64("dead letter id", deadLetter.ID), zap.Error(err))
+		}
+		return deliveryErr
+	}
+
+	_, err = orm.DB().NewDelete().
+		Model(&deadLetter).
+		WherePK().
+		Exec(ctx)
+	return errors.WithStack(err)
+}
+
+// SignWebhookBody returns the hex encoded HMAC-SHA256 signature of a webhook delivery
+// Receivers should compute the same signature and compare it to the X-GW2Verify-Signature header
+func SignWebhookBody(secret string, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(timestamp))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,25 @@
+package verify
+
+import "testing"
+
+func TestSignWebhookBody(t *testing.T) {
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		body      string
+		want      string
+	}{
+		{"signature", "secret", "1700000000", `{"id":1}`, "3dd1b9aef568d75f6790a84bd2e5dfa1f44409eef3cbdbd3f10b837376100c11"},
+		{"other secret", "other", "1700000000", `{"id":1}`, "e0cb77fc6d5b2877ec062213c262d236b5dd5a833d29fdc5a058c5fbfa287b47"},
+		{"other timestamp", "secret", "1700000001", `{"id":1}`, "d0c79a345e51a61362e0123dd2fc00ec01a78397760f2babc7a052bbbf46c313"},
+		{"empty body", "secret", "1700000000", "", "4bc5f74d868b97888288889c5d9d65df02526f94c1592a79fdf4fe8b26e311e5"},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := SignWebhookBody(test.secret, test.timestamp, []byte(test.body)); got != test.want {
+				t.Errorf("SignWebhookBody() = %s, want %s", got, test.want)
+			}
+		})
+	}
+}
@@ -8,6 +8,12 @@ type World struct {
 	Name string
 }
 
+// IsTeamID checks if the id is a WvW team id instead of a world id
+// Since the World Restructuring, ArenaNet assigns team ids in the 11xxx (NA) and 12xxx (EU) ranges
+func IsTeamID(id int) bool {
+	return id >= 11000 && id < 13000
+}
+
 // NormalizedWorldName returns the string representation of the world by its id
 func NormalizedWorldName(worldID int) string {
 	world := WorldNames[worldID]
@@ -0,0 +1,12 @@
+CREATE TABLE "matchups" (
+    "db_created" timestamptz DEFAULT now() NOT NULL,
+    "match_id" character varying(16) NOT NULL,
+    "color" character varying(16) NOT NULL,
+    "start_time" timestamptz NOT NULL,
+    "end_time" timestamptz NOT NULL,
+    "team_id" integer DEFAULT 0 NOT NULL,
+    "worlds" jsonb NOT NULL,
+    PRIMARY KEY ("match_id", "color", "start_time")
+);
+
+CREATE INDEX "matchups_end_time" ON "matchups" ("end_time");
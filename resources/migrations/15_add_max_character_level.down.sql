@@ -0,0 +1,2 @@
+ALTER TABLE "accounts"
+    DROP "max_character_level";
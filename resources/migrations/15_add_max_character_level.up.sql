@@ -0,0 +1,2 @@
+ALTER TABLE "accounts"
+    ADD "max_character_level" integer DEFAULT 0 NOT NULL;
@@ -0,0 +1,24 @@
+-- Bans issued without an account cannot be represented before this migration.
+-- Attach them to an account of the banned user, and refuse to roll back if any are left, instead of deleting moderation records
+UPDATE "bans" SET "account_id" = (
+    SELECT "id" FROM "accounts" WHERE "accounts"."user_id" = "bans"."user_id" ORDER BY "db_created" LIMIT 1
+) WHERE "account_id" IS NULL;
+
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM "bans" WHERE "account_id" IS NULL) THEN
+        RAISE EXCEPTION 'bans of users without accounts exist, attach them to an account or remove them before rolling back';
+    END IF;
+END
+$$;
+
+DROP INDEX "bans_until";
+DROP INDEX "bans_user_id";
+
+ALTER TABLE "bans"
+    ALTER "account_id" SET NOT NULL,
+    DROP "lifted_at",
+    DROP "lifted_by",
+    DROP "updated_by",
+    DROP "created_by",
+    DROP "id";
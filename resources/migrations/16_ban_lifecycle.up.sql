@@ -0,0 +1,10 @@
+ALTER TABLE "bans"
+    ADD "id" serial NOT NULL PRIMARY KEY,
+    ALTER "account_id" DROP NOT NULL,
+    ADD "created_by" character varying(64) NULL,
+    ADD "updated_by" character varying(64) NULL,
+    ADD "lifted_by" character varying(64) NULL,
+    ADD "lifted_at" timestamptz NULL;
+
+CREATE INDEX "bans_user_id" ON "bans" ("user_id");
+CREATE INDEX "bans_until" ON "bans" ("until");
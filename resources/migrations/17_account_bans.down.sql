@@ -0,0 +1,3 @@
+DROP INDEX "bans_account_id";
+
+DROP TABLE "account_users";
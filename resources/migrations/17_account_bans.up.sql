@@ -0,0 +1,16 @@
+CREATE TABLE "account_users" (
+    "db_created" timestamptz DEFAULT now() NOT NULL,
+    "db_updated" timestamptz DEFAULT now() NOT NULL,
+    "account_id" uuid NOT NULL,
+    "user_id" integer NOT NULL,
+    PRIMARY KEY ("account_id", "user_id"),
+    FOREIGN KEY ("user_id") REFERENCES "users" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX "account_users_user_id" ON "account_users" ("user_id");
+
+-- Every account has at least been linked to its current user
+INSERT INTO "account_users" ("account_id", "user_id")
+SELECT "id", "user_id" FROM "accounts" WHERE "user_id" IS NOT NULL;
+
+CREATE INDEX "bans_account_id" ON "bans" ("account_id");
@@ -0,0 +1,10 @@
+CREATE TABLE "events" (
+    "id" bigserial NOT NULL,
+    "db_created" timestamptz DEFAULT now() NOT NULL,
+    "user_id" integer NOT NULL,
+    "user" jsonb NOT NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX "events_user_id" ON "events" ("user_id");
+CREATE INDEX "events_db_created" ON "events" ("db_created");
@@ -0,0 +1,29 @@
+CREATE TABLE "webhooks" (
+    "id" bigserial NOT NULL,
+    "db_created" timestamptz DEFAULT now() NOT NULL,
+    "db_updated" timestamptz DEFAULT now() NOT NULL,
+    "service_uuid" character varying(64) NOT NULL,
+    "url" text NOT NULL,
+    "secret" character varying(256) NOT NULL,
+    "payload" character varying(32) NOT NULL,
+    "platform_id" integer NULL,
+    "world" integer NULL,
+    PRIMARY KEY ("id")
+);
+
+CREATE INDEX "webhooks_service_uuid" ON "webhooks" ("service_uuid");
+
+CREATE TABLE "webhook_deliveries" (
+    "id" bigserial NOT NULL,
+    "db_created" timestamptz DEFAULT now() NOT NULL,
+    "webhook_id" bigint NOT NULL,
+    "event_id" bigint NULL,
+    "body" jsonb NOT NULL,
+    "attempts" integer NOT NULL,
+    "last_status_code" integer NULL,
+    "last_error" text NULL,
+    PRIMARY KEY ("id"),
+    FOREIGN KEY ("webhook_id") REFERENCES "webhooks" ("id") ON DELETE CASCADE ON UPDATE NO ACTION
+);
+
+CREATE INDEX "webhook_deliveries_webhook_id" ON "webhook_deliveries" ("webhook_id");
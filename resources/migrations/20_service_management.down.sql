@@ -0,0 +1,4 @@
+ALTER TABLE "services"
+    DROP CONSTRAINT "services_api_key",
+    DROP COLUMN "disabled_at",
+    DROP COLUMN "db_created";
@@ -0,0 +1,4 @@
+ALTER TABLE "services"
+    ADD COLUMN "db_created" timestamptz NOT NULL DEFAULT NOW(),
+    ADD COLUMN "disabled_at" timestamptz,
+    ADD CONSTRAINT "services_api_key" UNIQUE ("api_key");
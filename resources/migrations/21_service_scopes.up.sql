@@ -0,0 +1,14 @@
+ALTER TABLE "services"
+    ADD COLUMN "scopes" text[] NOT NULL DEFAULT '{}';
+
+-- Existing services keep access to everything they could access before, except managing other services
+UPDATE "services" SET "scopes" = ARRAY[
+    'status:read',
+    'apikey:write',
+    'requirements:skip',
+    'ban:write',
+    'temporary:write',
+    'statistics:write',
+    'properties:own',
+    'webhooks:own'
+];
@@ -0,0 +1,10 @@
+-- Hashed keys cannot be restored, so every service needs its key rotated after reverting
+ALTER TABLE "services"
+    ADD COLUMN "api_key" character varying(256);
+UPDATE "services" SET "api_key" = md5(random()::text || clock_timestamp()::text || "uuid");
+ALTER TABLE "services"
+    ALTER COLUMN "api_key" SET NOT NULL,
+    ADD CONSTRAINT "services_api_key" UNIQUE ("api_key"),
+    DROP COLUMN "key_hash",
+    DROP COLUMN "key_salt",
+    DROP COLUMN "key_prefix";
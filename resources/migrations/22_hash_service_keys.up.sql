@@ -0,0 +1,20 @@
+ALTER TABLE "services"
+    ADD COLUMN "key_prefix" character varying(8),
+    ADD COLUMN "key_salt" character varying(64),
+    ADD COLUMN "key_hash" character varying(64);
+
+-- Hash the existing plaintext keys. Must match services.LookupPrefix and services.HashAPIKey
+UPDATE "services" SET
+    "key_prefix" = left("api_key", LEAST(8, length("api_key") / 2)),
+    "key_salt" = md5(random()::text || clock_timestamp()::text || "uuid");
+UPDATE "services" SET
+    "key_hash" = encode(sha256(convert_to("key_salt" || "api_key", 'UTF8')), 'hex');
+
+ALTER TABLE "services"
+    ALTER COLUMN "key_prefix" SET NOT NULL,
+    ALTER COLUMN "key_salt" SET NOT NULL,
+    ALTER COLUMN "key_hash" SET NOT NULL,
+    DROP CONSTRAINT "services_api_key",
+    DROP COLUMN "api_key";
+
+CREATE INDEX "services_key_prefix" ON "services" ("key_prefix");
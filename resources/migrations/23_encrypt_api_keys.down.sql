@@ -0,0 +1,13 @@
+-- Encrypted api keys cannot be decrypted by the database, so they have to be decrypted with: gw2verify-admin apikeys decrypt
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM "token_infos" WHERE "api_key_ciphertext" IS NOT NULL) THEN
+        RAISE EXCEPTION 'encrypted api keys exist, decrypt them with "gw2verify-admin apikeys decrypt" before rolling back';
+    END IF;
+END $$;
+ALTER TABLE "token_infos"
+    DROP CONSTRAINT "token_infos_api_key_stored",
+    DROP COLUMN "api_key_key_id",
+    DROP COLUMN "api_key_wrapped_key",
+    DROP COLUMN "api_key_ciphertext",
+    ALTER COLUMN "api_key" SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Api keys are envelope encrypted once a master key is configured. Existing keys are encrypted with: gw2verify-admin apikeys reencrypt
+ALTER TABLE "token_infos"
+    ALTER COLUMN "api_key" DROP NOT NULL,
+    ADD COLUMN "api_key_ciphertext" bytea,
+    ADD COLUMN "api_key_wrapped_key" bytea,
+    ADD COLUMN "api_key_key_id" character varying(16),
+    ADD CONSTRAINT "token_infos_api_key_stored" CHECK ("api_key" IS NOT NULL OR "api_key_ciphertext" IS NOT NULL);
@@ -0,0 +1,17 @@
+CREATE TABLE "audit_entries" (
+    "id" bigserial PRIMARY KEY,
+    "db_created" timestamptz NOT NULL DEFAULT NOW(),
+    "service_uuid" character varying(64) NOT NULL,
+    "action" character varying(32) NOT NULL,
+    "user_id" integer,
+    "platform_id" integer,
+    "platform_user_id" character varying(256),
+    "subject" text,
+    "before" jsonb,
+    "after" jsonb
+);
+
+CREATE INDEX "audit_entries_service_uuid" ON "audit_entries" ("service_uuid");
+CREATE INDEX "audit_entries_user_id" ON "audit_entries" ("user_id");
+CREATE INDEX "audit_entries_platform_user" ON "audit_entries" ("platform_id", "platform_user_id");
+CREATE INDEX "audit_entries_action" ON "audit_entries" ("action");
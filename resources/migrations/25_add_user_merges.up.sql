@@ -0,0 +1,13 @@
+CREATE TABLE "user_merges" (
+    "id" bigserial PRIMARY KEY,
+    "db_created" timestamptz NOT NULL DEFAULT NOW(),
+    "source_user_id" integer NOT NULL,
+    "target_user_id" integer NOT NULL,
+    "automatic" boolean NOT NULL,
+    "account_id" uuid,
+    "service_uuid" character varying(64),
+    "source_user" jsonb NOT NULL
+);
+
+CREATE INDEX "user_merges_source_user_id" ON "user_merges" ("source_user_id");
+CREATE INDEX "user_merges_target_user_id" ON "user_merges" ("target_user_id");
@@ -0,0 +1,2 @@
+ALTER TABLE "token_infos"
+    DROP "failures";
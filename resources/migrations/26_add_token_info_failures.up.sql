@@ -0,0 +1,2 @@
+ALTER TABLE "token_infos"
+    ADD "failures" integer NOT NULL DEFAULT 0;
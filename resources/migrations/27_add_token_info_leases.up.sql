@@ -0,0 +1,2 @@
+ALTER TABLE "token_infos"
+    ADD "leased_until" timestamptz NULL;
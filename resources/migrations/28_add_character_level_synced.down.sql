@@ -0,0 +1,2 @@
+ALTER TABLE "accounts"
+    DROP "character_level_synced";
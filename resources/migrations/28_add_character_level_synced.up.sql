@@ -0,0 +1,3 @@
+ALTER TABLE "accounts"
+    ADD "character_level_synced" boolean DEFAULT false NOT NULL;
+UPDATE "accounts" SET "character_level_synced" = true WHERE "max_character_level" > 0;
@@ -0,0 +1,7 @@
+DROP INDEX "webhook_deliveries_next_attempt_at";
+
+DELETE FROM "webhook_deliveries" WHERE "pending";
+
+ALTER TABLE "webhook_deliveries"
+    DROP "pending",
+    DROP "next_attempt_at";
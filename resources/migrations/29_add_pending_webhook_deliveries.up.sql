@@ -0,0 +1,5 @@
+ALTER TABLE "webhook_deliveries"
+    ADD "pending" boolean DEFAULT false NOT NULL,
+    ADD "next_attempt_at" timestamptz NULL;
+
+CREATE INDEX "webhook_deliveries_next_attempt_at" ON "webhook_deliveries" ("next_attempt_at") WHERE "pending";
@@ -0,0 +1,3 @@
+DROP INDEX "bans_group_id";
+
+ALTER TABLE "bans" DROP "group_id";
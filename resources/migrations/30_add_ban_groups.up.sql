@@ -0,0 +1,21 @@
+CREATE SEQUENCE "bans_group_id_seq";
+
+ALTER TABLE "bans" ADD "group_id" bigint NULL;
+
+-- The rows of a ban issued to several accounts and identities, and its copies, share the reason, expiration and issuer
+UPDATE "bans" SET "group_id" = "groups"."group_id"
+FROM (
+    SELECT "reason", "until", "created_by", nextval('bans_group_id_seq') AS "group_id"
+    FROM "bans"
+    GROUP BY "reason", "until", "created_by"
+) AS "groups"
+WHERE "bans"."reason" = "groups"."reason"
+    AND "bans"."until" = "groups"."until"
+    AND "bans"."created_by" IS NOT DISTINCT FROM "groups"."created_by";
+
+ALTER TABLE "bans"
+    ALTER "group_id" SET DEFAULT nextval('bans_group_id_seq'),
+    ALTER "group_id" SET NOT NULL;
+ALTER SEQUENCE "bans_group_id_seq" OWNED BY "bans"."group_id";
+
+CREATE INDEX "bans_group_id" ON "bans" ("group_id");
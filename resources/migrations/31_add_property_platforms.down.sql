@@ -0,0 +1,3 @@
+DROP INDEX "properties_subject";
+
+ALTER TABLE "properties" DROP "platform_id";
@@ -0,0 +1,4 @@
+-- Properties of platform users are erased along with the platform user, without touching other platforms' users sharing the id
+ALTER TABLE "properties" ADD "platform_id" integer NULL;
+
+CREATE INDEX "properties_subject" ON "properties" ("subject");
@@ -0,0 +1,7 @@
+DROP INDEX "events_platform_ids";
+DROP INDEX "events_unsequenced";
+DROP INDEX "events_seq";
+
+ALTER TABLE "events"
+    DROP "platform_ids",
+    DROP "seq";
@@ -0,0 +1,21 @@
+-- Ids are handed out when an event is appended, so they become visible out of order when transactions commit out of order.
+-- The sequence number is assigned once the event has committed, so consumers never skip an event by advancing their cursor
+CREATE SEQUENCE "events_seq_seq";
+
+ALTER TABLE "events"
+    ADD "seq" bigint NULL,
+    ADD "platform_ids" integer[] DEFAULT '{}' NOT NULL;
+
+-- Events appended before this migration keep their ids as cursors
+UPDATE "events" SET "seq" = "id";
+SELECT setval('events_seq_seq', COALESCE((SELECT MAX("id") FROM "events"), 0) + 1, false);
+ALTER SEQUENCE "events_seq_seq" OWNED BY "events"."seq";
+
+UPDATE "events" SET "platform_ids" = ARRAY(
+    SELECT DISTINCT ("link"->>'platform_id')::integer
+    FROM jsonb_array_elements("user"->'platform_links') AS "link"
+) WHERE jsonb_typeof("user"->'platform_links') = 'array';
+
+CREATE UNIQUE INDEX "events_seq" ON "events" ("seq");
+CREATE INDEX "events_unsequenced" ON "events" ("id") WHERE "seq" IS NULL;
+CREATE INDEX "events_platform_ids" ON "events" USING gin ("platform_ids");
@@ -0,0 +1,2 @@
+ALTER TABLE "accounts"
+    ADD "character_level_synced_at" timestamptz NULL;
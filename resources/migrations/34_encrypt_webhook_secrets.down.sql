@@ -0,0 +1,13 @@
+-- Encrypted webhook secrets cannot be decrypted by the database, so they have to be decrypted with: gw2verify-admin apikeys decrypt
+DO $$
+BEGIN
+    IF EXISTS (SELECT 1 FROM "webhooks" WHERE "secret_ciphertext" IS NOT NULL) THEN
+        RAISE EXCEPTION 'encrypted webhook secrets exist, decrypt them with "gw2verify-admin apikeys decrypt" before rolling back';
+    END IF;
+END $$;
+ALTER TABLE "webhooks"
+    DROP CONSTRAINT "webhooks_secret_stored",
+    DROP COLUMN "secret_key_id",
+    DROP COLUMN "secret_wrapped_key",
+    DROP COLUMN "secret_ciphertext",
+    ALTER COLUMN "secret" SET NOT NULL;
@@ -0,0 +1,7 @@
+-- Webhook secrets are envelope encrypted once a master key is configured. Existing secrets are encrypted with: gw2verify-admin apikeys reencrypt
+ALTER TABLE "webhooks"
+    ALTER COLUMN "secret" DROP NOT NULL,
+    ADD COLUMN "secret_ciphertext" bytea,
+    ADD COLUMN "secret_wrapped_key" bytea,
+    ADD COLUMN "secret_key_id" character varying(16),
+    ADD CONSTRAINT "webhooks_secret_stored" CHECK ("secret" IS NOT NULL OR "secret_ciphertext" IS NOT NULL);
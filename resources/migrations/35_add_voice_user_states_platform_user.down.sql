@@ -0,0 +1 @@
+DROP INDEX "voice_user_states_platform_user_timestamp";
@@ -0,0 +1,2 @@
+-- The scheduler looks up recent voice activity of the platform users linked to each api key
+CREATE INDEX "voice_user_states_platform_user_timestamp" ON "voice_user_states" ("platform_id", "platform_user_id", "timestamp");